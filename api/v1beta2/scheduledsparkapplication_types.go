@@ -34,14 +34,44 @@ type ScheduledSparkApplicationSpec struct {
 
 	// Schedule is a cron schedule on which the application should run.
 	Schedule string `json:"schedule"`
+	// TimeZone is the name of the IANA time zone in which the schedule is interpreted, e.g. "Europe/Berlin".
+	// Defaults to the local time zone of the operator.
+	// +optional
+	TimeZone *string `json:"timeZone,omitempty"`
 	// Template is a template from which SparkApplication instances can be created.
+	// The arguments, the Spark and Hadoop configurations and the environment variables of the driver and executors
+	// can use Go templates delimited by "${{" and "}}" with the variables .ScheduledTime, .PreviousSuccessfulRunTime,
+	// .RunName and .Namespace, e.g. "${{ .ScheduledTime.Format \"2006-01-02\" }}". The times are in the time zone
+	// of the schedule. Spark's own placeholders such as "{{APP_ID}}" are left untouched.
 	Template SparkApplicationSpec `json:"template"`
+	// Tasks are additional SparkApplications created with every run, which form a graph of applications with the
+	// one created from Template. The DependsOn field of the templates can refer to the tasks by their names and to
+	// the application created from Template by the name of the ScheduledSparkApplication.
+	// +optional
+	Tasks []ScheduledSparkApplicationTask `json:"tasks,omitempty"`
 	// Suspend is a flag telling the controller to suspend subsequent runs of the application if set to true.
 	// +optional
 	// Defaults to false.
 	Suspend *bool `json:"suspend,omitempty"`
 	// ConcurrencyPolicy is the policy governing concurrent SparkApplication runs.
 	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+	// StartingDeadlineSeconds is the deadline in seconds for starting a run after its scheduled time.
+	// Runs that cannot be started before the deadline, e.g. because the operator was unavailable, are skipped.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+	// CatchUpPolicy is the policy governing the runs missed since the last run, e.g. while the operator was
+	// unavailable.
+	// +kubebuilder:validation:Enum={None,LatestOnly,All}
+	// +optional
+	// Defaults to LatestOnly.
+	CatchUpPolicy CatchUpPolicy `json:"catchUpPolicy,omitempty"`
+	// MaxCatchUpRuns is the maximum number of missed runs to start if CatchUpPolicy is All.
+	// If more runs have been missed, only the most recent ones are started.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	// Defaults to 10.
+	MaxCatchUpRuns *int32 `json:"maxCatchUpRuns,omitempty"`
 	// SuccessfulRunHistoryLimit is the number of past successful runs of the application to keep.
 	// +optional
 	// Defaults to 1.
@@ -52,27 +82,57 @@ type ScheduledSparkApplicationSpec struct {
 	FailedRunHistoryLimit *int32 `json:"failedRunHistoryLimit,omitempty"`
 }
 
+// ScheduledSparkApplicationTask is a SparkApplication created with every run of a ScheduledSparkApplication.
+type ScheduledSparkApplicationTask struct {
+	// Name is the name of the task, which is appended to the name of the run to name the SparkApplication.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+	// Template is a template from which the SparkApplication of the task is created.
+	// Its schema is not embedded in the CRD to keep it within the size limit of Kubernetes objects.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Template SparkApplicationSpec `json:"template"`
+}
+
 // ScheduledSparkApplicationStatus defines the observed state of ScheduledSparkApplication.
 type ScheduledSparkApplicationStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make generate" to regenerate code after modifying this file
 
-	// LastRun is the time when the last run of the application started.
+	// LastRun is the scheduled time of the last run of the application, which may be earlier than the time the
+	// run started at, e.g. for missed runs caught up with or backfilled runs.
 	// +nullable
 	LastRun metav1.Time `json:"lastRun,omitempty"`
 	// NextRun is the time when the next run of the application will start.
 	// +nullable
 	NextRun metav1.Time `json:"nextRun,omitempty"`
+	// ObservedGeneration is the generation of the spec NextRun was computed from, so that NextRun is computed again
+	// once the schedule or its time zone changes.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 	// LastRunName is the name of the SparkApplication for the most recent run of the application.
 	LastRunName string `json:"lastRunName,omitempty"`
 	// PastSuccessfulRunNames keeps the names of SparkApplications for past successful runs.
 	PastSuccessfulRunNames []string `json:"pastSuccessfulRunNames,omitempty"`
 	// PastFailedRunNames keeps the names of SparkApplications for past failed runs.
 	PastFailedRunNames []string `json:"pastFailedRunNames,omitempty"`
+	// PendingManualRuns keeps the scheduled times of the manually triggered or backfilled runs that have not
+	// been started yet, in chronological order.
+	PendingManualRuns []metav1.Time `json:"pendingManualRuns,omitempty"`
+	// ManualRunCount is the number of manual runs started so far. It numbers the SparkApplications of the manual
+	// runs, so that re-running a scheduled time creates a new SparkApplication.
+	ManualRunCount int32 `json:"manualRunCount,omitempty"`
 	// ScheduleState is the current scheduling state of the application.
 	ScheduleState ScheduleState `json:"scheduleState,omitempty"`
 	// Reason tells why the ScheduledSparkApplication is in the particular ScheduleState.
 	Reason string `json:"reason,omitempty"`
+	// Conditions are the latest observations of the state of the ScheduledSparkApplication, maintained
+	// alongside ScheduleState.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 }
 
 // +kubebuilder:object:root=true
@@ -80,7 +140,9 @@ type ScheduledSparkApplicationStatus struct {
 // +kubebuilder:resource:scope=Namespaced,shortName=scheduledsparkapp,singular=scheduledsparkapplication
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:JSONPath=.spec.schedule,name=Schedule,type=string
+// +kubebuilder:printcolumn:JSONPath=.spec.timeZone,name=Time Zone,type=string,priority=1
 // +kubebuilder:printcolumn:JSONPath=.spec.suspend,name=Suspend,type=string
+// +kubebuilder:printcolumn:JSONPath=".status.conditions[?(@.type==\"Scheduled\")].status",name=Scheduled,type=string
 // +kubebuilder:printcolumn:JSONPath=.status.lastRun,name=Last Run,type=date
 // +kubebuilder:printcolumn:JSONPath=.status.lastRunName,name=Last Run Name,type=string
 // +kubebuilder:printcolumn:JSONPath=.metadata.creationTimestamp,name=Age,type=date
@@ -115,6 +177,17 @@ const (
 	ConcurrencyReplace ConcurrencyPolicy = "Replace"
 )
 
+type CatchUpPolicy string
+
+const (
+	// CatchUpNone skips all the missed runs. A run is only started if no later run has been missed since.
+	CatchUpNone CatchUpPolicy = "None"
+	// CatchUpLatestOnly starts the most recent missed run and skips the others.
+	CatchUpLatestOnly CatchUpPolicy = "LatestOnly"
+	// CatchUpAll starts all the missed runs, up to MaxCatchUpRuns of them, in the order of their scheduled times.
+	CatchUpAll CatchUpPolicy = "All"
+)
+
 type ScheduleState string
 
 const (
@@ -123,3 +196,13 @@ const (
 	ScheduleStateScheduled        ScheduleState = "Scheduled"
 	ScheduleStateFailedValidation ScheduleState = "FailedValidation"
 )
+
+// Different types of conditions of a ScheduledSparkApplication.
+const (
+	// ScheduledSparkApplicationConditionScheduled tells whether the schedule of the application is valid and
+	// its runs are started accordingly.
+	ScheduledSparkApplicationConditionScheduled = "Scheduled"
+	// ScheduledSparkApplicationConditionSuspended tells whether the application is suspended, so that no run is
+	// started.
+	ScheduledSparkApplicationConditionSuspended = "Suspended"
+)
@@ -62,7 +62,9 @@ type SparkApplicationSpec struct {
 	// +optional
 	MainClass *string `json:"mainClass,omitempty"`
 	// MainFile is the path to a bundled JAR, Python, or R file of the application.
-	MainApplicationFile *string `json:"mainApplicationFile"`
+	// It is required unless Server is set, in which case the server bundled with Spark is run by default.
+	// +optional
+	MainApplicationFile *string `json:"mainApplicationFile,omitempty"`
 	// Arguments is a list of arguments to be passed to the application.
 	// +optional
 	Arguments []string `json:"arguments,omitempty"`
@@ -142,6 +144,63 @@ type SparkApplicationSpec struct {
 	// scheduler backend since Spark 3.0.
 	// +optional
 	DynamicAllocation *DynamicAllocation `json:"dynamicAllocation,omitempty"`
+	// Submitter configures how the driver of this application is launched, overriding the default
+	// submitter configured on the operator. "spark-submit" runs spark-submit in the operator while
+	// "native" creates the driver pod and its supporting resources directly.
+	// +kubebuilder:validation:Enum={spark-submit,native}
+	// +optional
+	Submitter *SubmitterType `json:"submitter,omitempty"`
+	// SubmissionTimeoutSeconds is the maximum duration in seconds a single submission of this application
+	// may take, overriding the default submission timeout configured on the operator. A submission that
+	// exceeds it is cancelled and the application moves to the SUBMISSION_FAILED state.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	SubmissionTimeoutSeconds *int64 `json:"submissionTimeoutSeconds,omitempty"`
+	// ActiveDeadlineSeconds is the maximum duration in seconds an attempt of this application may run, counted
+	// from its submission. The driver and executors of an attempt that exceeds it are killed, and the application
+	// fails with the DEADLINE_EXCEEDED reason.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+	// PendingDeadlineSeconds is the maximum duration in seconds the driver of an attempt of this application may
+	// take to start running after its submission, e.g. while waiting to be scheduled. An attempt that exceeds it
+	// is handled like one exceeding ActiveDeadlineSeconds.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PendingDeadlineSeconds *int64 `json:"pendingDeadlineSeconds,omitempty"`
+	// StuckPendingPolicy tells what to do when the driver, or all the executors, of this application are stuck
+	// pending, e.g. because they cannot be scheduled or their image cannot be pulled. Why they are stuck is
+	// reported in the application state regardless, but nothing is done about it without a policy.
+	// +optional
+	StuckPendingPolicy *StuckPendingPolicy `json:"stuckPendingPolicy,omitempty"`
+	// DependsOn is the list of names of the SparkApplications in the same namespace this application depends on.
+	// The application waits in the WAITING state until all of them have completed before it is submitted. It
+	// fails if some of them still do not exist five minutes after its creation. The dependencies must not form a
+	// cycle.
+	// +optional
+	DependsOn []string `json:"dependsOn,omitempty"`
+	// UpstreamFailurePolicy is the policy governing this application if one of the applications it depends on
+	// fails or is skipped.
+	// +kubebuilder:validation:Enum={Fail,Skip}
+	// +optional
+	// Defaults to Fail.
+	UpstreamFailurePolicy UpstreamFailurePolicy `json:"upstreamFailurePolicy,omitempty"`
+	// Priority is the priority of this application in the admission queue of its namespace, which takes
+	// precedence over the value of the PriorityClass of the driver. Applications with a higher priority are
+	// admitted first, and may preempt running applications with a lower priority if preemption is enabled.
+	// +optional
+	Priority *int32 `json:"priority,omitempty"`
+	// Server makes the driver of this application run a long-lived Spark Connect or Thrift JDBC/ODBC server
+	// instead of a batch job. The server is exposed through a Service and optionally an Ingress, and is
+	// restarted whenever it terminates, regardless of the RestartPolicy.
+	// +optional
+	Server *ServerSpec `json:"server,omitempty"`
+	// Suspend tells the controller to not submit the application, or to tear down its driver and executors if
+	// it has been submitted already, if set to true. The application is submitted again from scratch once
+	// Suspend is unset.
+	// +optional
+	// Defaults to false.
+	Suspend *bool `json:"suspend,omitempty"`
 }
 
 // SparkApplicationStatus defines the observed state of SparkApplication
@@ -156,6 +215,9 @@ type SparkApplicationStatus struct {
 	// LastSubmissionAttemptTime is the time for the last application submission attempt.
 	// +nullable
 	LastSubmissionAttemptTime metav1.Time `json:"lastSubmissionAttemptTime,omitempty"`
+	// NextRetryTime is the time at which the failed application is due to be retried, if it is.
+	// +nullable
+	NextRetryTime metav1.Time `json:"nextRetryTime,omitempty"`
 	// CompletionTime is the time when the application runs to completion if it does.
 	// +nullable
 	TerminationTime metav1.Time `json:"terminationTime,omitempty"`
@@ -163,6 +225,9 @@ type SparkApplicationStatus struct {
 	DriverInfo DriverInfo `json:"driverInfo"`
 	// AppState tells the overall application state.
 	AppState ApplicationState `json:"applicationState,omitempty"`
+	// FailureReason is a machine-readable reason for the failure of the last attempt, if it failed.
+	// +optional
+	FailureReason FailureReason `json:"failureReason,omitempty"`
 	// ExecutorState records the state of executors by executor Pod names.
 	ExecutorState map[string]ExecutorState `json:"executorState,omitempty"`
 	// ExecutionAttempts is the total number of attempts to run a submitted application to completion.
@@ -171,13 +236,46 @@ type SparkApplicationStatus struct {
 	// SubmissionAttempts is the total number of attempts to submit an application to run.
 	// Incremented upon each attempted submission of the application and reset upon invalidation and rerun.
 	SubmissionAttempts int32 `json:"submissionAttempts,omitempty"`
+	// LastSubmissionAttempt records the details of the last attempt to submit the application.
+	// +optional
+	LastSubmissionAttempt *SubmissionAttemptInfo `json:"lastSubmissionAttempt,omitempty"`
+	// BatchSchedulerStatus tells whether the batch scheduler of the application has admitted it for submission.
+	// +optional
+	BatchSchedulerStatus *BatchSchedulerStatus `json:"batchSchedulerStatus,omitempty"`
+	// ServerStatus tells how to reach the server of the application and whether it is healthy.
+	// +optional
+	ServerStatus *ServerStatus `json:"serverStatus,omitempty"`
+	// ExecutorReplicas is the number of running executors, reported as the replicas of the scale subresource.
+	// +optional
+	ExecutorReplicas int32 `json:"executorReplicas,omitempty"`
+	// ExecutorSelector is the label selector of the executor pods of the current attempt, reported as the
+	// selector of the scale subresource.
+	// +optional
+	ExecutorSelector string `json:"executorSelector,omitempty"`
+	// ExecutorLimit is the number of executors the current attempt of an application with dynamic allocation was
+	// scaled to while running, bounded by its minimum and maximum number of executors. Executors beyond it are
+	// removed, and the driver is not allowed to create new ones beyond it.
+	// +optional
+	ExecutorLimit *int32 `json:"executorLimit,omitempty"`
+	// Attempts is the history of the most recent attempts of the application, oldest first, which keeps the
+	// details of past attempts after the status is reset for the next one.
+	// +optional
+	Attempts []AttemptInfo `json:"attempts,omitempty"`
+	// Conditions are the latest observations of the state of the application, maintained alongside AppState.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:metadata:annotations="api-approved.kubernetes.io=https://github.com/kubeflow/spark-operator/pull/1298"
 // +kubebuilder:resource:scope=Namespaced,shortName=sparkapp,singular=sparkapplication
 // +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.executor.instances,statuspath=.status.executorReplicas,selectorpath=.status.executorSelector
 // +kubebuilder:printcolumn:JSONPath=.status.applicationState.state,name=Status,type=string
+// +kubebuilder:printcolumn:JSONPath=".status.conditions[?(@.type==\"Ready\")].status",name=Ready,type=string
+// +kubebuilder:printcolumn:JSONPath=.status.failureReason,name=Failure Reason,type=string,priority=1
 // +kubebuilder:printcolumn:JSONPath=.status.executionAttempts,name=Attempts,type=string
 // +kubebuilder:printcolumn:JSONPath=.status.lastSubmissionAttemptTime,name=Start,type=string
 // +kubebuilder:printcolumn:JSONPath=.status.terminationTime,name=Finish,type=string
@@ -222,6 +320,15 @@ const (
 	DeployModeInClusterClient DeployMode = "in-cluster-client"
 )
 
+// SubmitterType describes how the driver of a SparkApplication is launched.
+type SubmitterType string
+
+// Different types of submitters.
+const (
+	SubmitterTypeSparkSubmit SubmitterType = "spark-submit"
+	SubmitterTypeNative      SubmitterType = "native"
+)
+
 // RestartPolicy is the policy of if and in which conditions the controller should restart a terminated application.
 // This completely defines actions to be taken on any kind of Failures during an application run.
 type RestartPolicy struct {
@@ -250,6 +357,71 @@ type RestartPolicy struct {
 	// +kubebuilder:validation:Minimum=1
 	// +optional
 	OnFailureRetryInterval *int64 `json:"onFailureRetryInterval,omitempty"`
+
+	// RetryOnDeadlineExceeded tells whether runs which exceed their deadline are retried like other failed runs.
+	// Defaults to false.
+	// +optional
+	RetryOnDeadlineExceeded *bool `json:"retryOnDeadlineExceeded,omitempty"`
+
+	// Backoff makes the interval between retries grow exponentially with the number of attempts, instead of
+	// linearly. The intervals are counted from the failure of the last attempt.
+	// +optional
+	Backoff *RestartBackoff `json:"backoff,omitempty"`
+
+	// RetryOnFailureReasons restricts retries to failures with one of the given reasons, if it is not empty.
+	// Listing DeadlineExceeded implies RetryOnDeadlineExceeded.
+	// +optional
+	RetryOnFailureReasons []FailureReason `json:"retryOnFailureReasons,omitempty"`
+}
+
+// StuckPendingAction is the action taken on an application whose pods are stuck pending.
+type StuckPendingAction string
+
+const (
+	// StuckPendingActionFail fails the application, which is then retried according to its restart policy.
+	StuckPendingActionFail StuckPendingAction = "Fail"
+	// StuckPendingActionResubmit resubmits the application regardless of its restart policy.
+	StuckPendingActionResubmit StuckPendingAction = "Resubmit"
+)
+
+// StuckPendingPolicy is the policy for applications whose pods are stuck pending.
+type StuckPendingPolicy struct {
+	// GracePeriodSeconds is how long in seconds pods may be stuck pending, counted from their creation, before
+	// the action is taken.
+	// +kubebuilder:validation:Minimum=0
+	GracePeriodSeconds int64 `json:"gracePeriodSeconds"`
+	// Action is the action taken once the grace period has passed.
+	// +kubebuilder:validation:Enum={Fail,Resubmit}
+	Action StuckPendingAction `json:"action"`
+}
+
+// RestartBackoff is an exponential backoff policy for retries. The interval before the n-th retry is
+// base * multiplier^(n-1), capped at the max interval, plus a random jitter.
+type RestartBackoff struct {
+	// BaseSeconds is the interval in seconds before the first retry.
+	// Defaults to OnFailureRetryInterval or OnSubmissionFailureRetryInterval, depending on the failure retried.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	BaseSeconds *int64 `json:"baseSeconds,omitempty"`
+
+	// Multiplier is the factor by which the interval grows with each retry.
+	// Defaults to 2.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Multiplier *int32 `json:"multiplier,omitempty"`
+
+	// MaxIntervalSeconds caps the interval in seconds between retries, before jitter.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxIntervalSeconds *int64 `json:"maxIntervalSeconds,omitempty"`
+
+	// JitterPercent is the maximum random jitter added to each interval, as a percentage of the interval,
+	// so that applications failing together do not retry in lockstep.
+	// Defaults to 0.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	JitterPercent *int32 `json:"jitterPercent,omitempty"`
 }
 
 type RestartPolicyType string
@@ -260,6 +432,15 @@ const (
 	RestartPolicyAlways    RestartPolicyType = "Always"
 )
 
+type UpstreamFailurePolicy string
+
+const (
+	// UpstreamFailurePolicyFail fails the application if one of its upstream applications fails or is skipped.
+	UpstreamFailurePolicyFail UpstreamFailurePolicy = "Fail"
+	// UpstreamFailurePolicySkip skips the application if one of its upstream applications fails or is skipped.
+	UpstreamFailurePolicySkip UpstreamFailurePolicy = "Skip"
+)
+
 // BatchSchedulerConfiguration used to configure how to batch scheduling Spark Application
 type BatchSchedulerConfiguration struct {
 	// Queue stands for the resource queue which the application belongs to, it's being used in Volcano batch scheduler.
@@ -272,8 +453,23 @@ type BatchSchedulerConfiguration struct {
 	// If specified, volcano scheduler will consider it as the resources requested.
 	// +optional
 	Resources corev1.ResourceList `json:"resources,omitempty"`
+	// GangPolicy tells which executors are scheduled together with the driver as a gang by the batch scheduler.
+	// `Initial` takes the initial executors into account, while `Minimum` only takes the minimum executors of
+	// dynamic allocation into account. Defaults to `Initial`.
+	// +kubebuilder:validation:Enum={Initial,Minimum}
+	// +optional
+	GangPolicy *GangPolicy `json:"gangPolicy,omitempty"`
 }
 
+// GangPolicy tells which executors are scheduled together with the driver as a gang.
+type GangPolicy string
+
+// Different gang policies.
+const (
+	GangPolicyInitial GangPolicy = "Initial"
+	GangPolicyMinimum GangPolicy = "Minimum"
+)
+
 // SparkUIConfiguration is for driver UI specific configuration parameters.
 type SparkUIConfiguration struct {
 	// ServicePort allows configuring the port at service level that might be different from the targetPort.
@@ -328,30 +524,195 @@ type DriverIngressConfiguration struct {
 	IngressTLS []networkingv1.IngressTLS `json:"ingressTLS,omitempty"`
 }
 
+// ServerType is the type of a long-lived Spark server.
+type ServerType string
+
+// Different types of long-lived Spark servers.
+const (
+	// ServerTypeConnect is a Spark Connect server, which serves gRPC clients.
+	ServerTypeConnect ServerType = "Connect"
+	// ServerTypeThrift is a Thrift JDBC/ODBC server, which requires a Spark image built with Hive support.
+	ServerTypeThrift ServerType = "Thrift"
+)
+
+// ServerSpec defines a long-lived Spark server run by the driver of an application.
+type ServerSpec struct {
+	// Type is the type of the server.
+	// +kubebuilder:validation:Enum={Connect,Thrift}
+	Type ServerType `json:"type"`
+	// Port is the port the server listens on and is exposed on by its Service.
+	// Defaults to 15002 for Spark Connect and 10000 for Thrift.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+	// ServiceType is the type of the Service exposing the server. Defaults to ClusterIP.
+	// +optional
+	ServiceType *corev1.ServiceType `json:"serviceType,omitempty"`
+	// ServiceAnnotations is a map of key,value pairs of annotations that might be added to the Service.
+	// +optional
+	ServiceAnnotations map[string]string `json:"serviceAnnotations,omitempty"`
+	// ServiceLabels is a map of key,value pairs of labels that might be added to the Service.
+	// +optional
+	ServiceLabels map[string]string `json:"serviceLabels,omitempty"`
+	// Ingress exposes the server through an Ingress if set.
+	// +optional
+	Ingress *ServerIngressConfiguration `json:"ingress,omitempty"`
+}
+
+// ServerIngressConfiguration defines the Ingress exposing a long-lived Spark server.
+type ServerIngressConfiguration struct {
+	// Host is the host of the Ingress rule, which may contain the {{$appName}} and {{$appNamespace}} placeholders.
+	Host string `json:"host"`
+	// IngressClassName is the class of the Ingress. Defaults to the ingress class configured on the operator.
+	// +optional
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+	// IngressAnnotations is a map of key,value pairs of annotations that might be added to the Ingress.
+	// +optional
+	IngressAnnotations map[string]string `json:"ingressAnnotations,omitempty"`
+	// IngressTLS declares the TLS configuration of the Ingress.
+	// +optional
+	IngressTLS []networkingv1.IngressTLS `json:"ingressTLS,omitempty"`
+}
+
+// ServerHealth tells whether a long-lived Spark server is ready to serve clients.
+type ServerHealth string
+
+// Different health states of a long-lived Spark server.
+const (
+	ServerHealthUnknown   ServerHealth = "Unknown"
+	ServerHealthHealthy   ServerHealth = "Healthy"
+	ServerHealthUnhealthy ServerHealth = "Unhealthy"
+)
+
+// ServerStatus describes the endpoints and the health of a long-lived Spark server.
+type ServerStatus struct {
+	// ServiceName is the name of the Service exposing the server.
+	ServiceName string `json:"serviceName,omitempty"`
+	// Endpoint is the in-cluster endpoint of the server, e.g. sc://<service>.<namespace>.svc:15002 for Spark Connect.
+	Endpoint string `json:"endpoint,omitempty"`
+	// IngressName is the name of the Ingress exposing the server, if any.
+	IngressName string `json:"ingressName,omitempty"`
+	// IngressEndpoint is the endpoint of the server through its Ingress, if any.
+	IngressEndpoint string `json:"ingressEndpoint,omitempty"`
+	// Health tells whether the server is ready to serve clients, which is the case when its driver pod is ready.
+	Health ServerHealth `json:"health,omitempty"`
+	// LastHealthTransitionTime is the last time the health of the server changed.
+	// +nullable
+	LastHealthTransitionTime metav1.Time `json:"lastHealthTransitionTime,omitempty"`
+	// Restarts is the number of times the server has been restarted after it terminated.
+	Restarts int32 `json:"restarts,omitempty"`
+}
+
 // ApplicationStateType represents the type of the current state of an application.
 type ApplicationStateType string
 
 // Different states an application may have.
 const (
-	ApplicationStateNew              ApplicationStateType = ""
-	ApplicationStateSubmitted        ApplicationStateType = "SUBMITTED"
-	ApplicationStateRunning          ApplicationStateType = "RUNNING"
-	ApplicationStateCompleted        ApplicationStateType = "COMPLETED"
-	ApplicationStateFailed           ApplicationStateType = "FAILED"
-	ApplicationStateFailedSubmission ApplicationStateType = "SUBMISSION_FAILED"
-	ApplicationStatePendingRerun     ApplicationStateType = "PENDING_RERUN"
-	ApplicationStateInvalidating     ApplicationStateType = "INVALIDATING"
-	ApplicationStateSucceeding       ApplicationStateType = "SUCCEEDING"
-	ApplicationStateFailing          ApplicationStateType = "FAILING"
-	ApplicationStateUnknown          ApplicationStateType = "UNKNOWN"
+	ApplicationStateNew               ApplicationStateType = ""
+	ApplicationStateWaiting           ApplicationStateType = "WAITING"
+	ApplicationStateQueued            ApplicationStateType = "QUEUED"
+	ApplicationStatePendingSubmission ApplicationStateType = "PENDING_SUBMISSION"
+	ApplicationStateSubmitted         ApplicationStateType = "SUBMITTED"
+	ApplicationStateRunning           ApplicationStateType = "RUNNING"
+	ApplicationStateCompleted         ApplicationStateType = "COMPLETED"
+	ApplicationStateFailed            ApplicationStateType = "FAILED"
+	ApplicationStateSkipped           ApplicationStateType = "SKIPPED"
+	ApplicationStateFailedSubmission  ApplicationStateType = "SUBMISSION_FAILED"
+	ApplicationStatePendingRerun      ApplicationStateType = "PENDING_RERUN"
+	ApplicationStateInvalidating      ApplicationStateType = "INVALIDATING"
+	ApplicationStateSucceeding        ApplicationStateType = "SUCCEEDING"
+	ApplicationStateFailing           ApplicationStateType = "FAILING"
+	ApplicationStateUnknown           ApplicationStateType = "UNKNOWN"
+	ApplicationStateSuspended         ApplicationStateType = "SUSPENDED"
+)
+
+// ApplicationStateReason tells why an application is in its current state.
+type ApplicationStateReason string
+
+// Different reasons for the state of an application.
+const (
+	// ApplicationStateReasonPreempted means that the application was preempted by one with a higher priority.
+	ApplicationStateReasonPreempted ApplicationStateReason = "PREEMPTED"
+	// ApplicationStateReasonEvicted means that the application was evicted by its batch scheduler and queued again.
+	ApplicationStateReasonEvicted ApplicationStateReason = "EVICTED"
+	// ApplicationStateReasonRetryingFailure means that the application is rerun after a failure.
+	ApplicationStateReasonRetryingFailure ApplicationStateReason = "RETRYING_FAILURE"
+	// ApplicationStateReasonRestartingSuccess means that the application is rerun after a success, as it is
+	// always restarted.
+	ApplicationStateReasonRestartingSuccess ApplicationStateReason = "RESTARTING_SUCCESS"
+	// ApplicationStateReasonSpecUpdated means that the application is rerun as its spec was updated.
+	ApplicationStateReasonSpecUpdated ApplicationStateReason = "SPEC_UPDATED"
+	// ApplicationStateReasonResumed means that the application is rerun as it is no longer suspended.
+	ApplicationStateReasonResumed ApplicationStateReason = "RESUMED"
+	// ApplicationStateReasonDeadlineExceeded means that the application was killed as it exceeded its active or
+	// pending deadline.
+	ApplicationStateReasonDeadlineExceeded ApplicationStateReason = "DEADLINE_EXCEEDED"
+	// ApplicationStateReasonStuckPending means that the driver or executors of the application are stuck pending.
+	ApplicationStateReasonStuckPending ApplicationStateReason = "STUCK_PENDING"
+)
+
+// FailureReason is a machine-readable reason for the failure of an application.
+// +kubebuilder:validation:Enum={SubmissionError,DriverOOMKilled,DriverEvicted,ExecutorLossExceeded,DeadlineExceeded,ImagePullFailure,Unschedulable,ContainerConfigError,NodeLost,Preempted,UpstreamFailed,UserCodeError,Unknown}
+type FailureReason string
+
+// Different reasons an application may fail for.
+const (
+	// FailureReasonSubmissionError means that the application could not be submitted.
+	FailureReasonSubmissionError FailureReason = "SubmissionError"
+	// FailureReasonDriverOOMKilled means that the driver container was killed as it ran out of memory.
+	FailureReasonDriverOOMKilled FailureReason = "DriverOOMKilled"
+	// FailureReasonDriverEvicted means that the driver pod was evicted, e.g. due to node pressure.
+	FailureReasonDriverEvicted FailureReason = "DriverEvicted"
+	// FailureReasonExecutorLossExceeded means that the driver gave up as too many executors failed.
+	FailureReasonExecutorLossExceeded FailureReason = "ExecutorLossExceeded"
+	// FailureReasonDeadlineExceeded means that the application was killed as it exceeded its active or pending
+	// deadline.
+	FailureReasonDeadlineExceeded FailureReason = "DeadlineExceeded"
+	// FailureReasonImagePullFailure means that the image of the driver or executors could not be pulled.
+	FailureReasonImagePullFailure FailureReason = "ImagePullFailure"
+	// FailureReasonUnschedulable means that the driver or executors could not be scheduled.
+	FailureReasonUnschedulable FailureReason = "Unschedulable"
+	// FailureReasonContainerConfigError means that the driver or executor containers could not be created, e.g.
+	// as a referenced secret does not exist.
+	FailureReasonContainerConfigError FailureReason = "ContainerConfigError"
+	// FailureReasonNodeLost means that the node of the driver became unreachable.
+	FailureReasonNodeLost FailureReason = "NodeLost"
+	// FailureReasonPreempted means that the application was preempted by one with a higher priority.
+	FailureReasonPreempted FailureReason = "Preempted"
+	// FailureReasonUpstreamFailed means that an application the application depends on failed.
+	FailureReasonUpstreamFailed FailureReason = "UpstreamFailed"
+	// FailureReasonUserCodeError means that the driver container exited with a non-zero exit code.
+	FailureReasonUserCodeError FailureReason = "UserCodeError"
+	// FailureReasonUnknown means that the reason for the failure is not known, e.g. as the driver pod was deleted.
+	FailureReasonUnknown FailureReason = "Unknown"
 )
 
 // ApplicationState tells the current state of the application and an error message in case of failures.
 type ApplicationState struct {
-	State        ApplicationStateType `json:"state"`
-	ErrorMessage string               `json:"errorMessage,omitempty"`
+	State ApplicationStateType `json:"state"`
+	// Reason is a brief reason for the current state, if any.
+	// +optional
+	Reason       ApplicationStateReason `json:"reason,omitempty"`
+	ErrorMessage string                 `json:"errorMessage,omitempty"`
 }
 
+// Different types of conditions of a SparkApplication.
+const (
+	// SparkApplicationConditionSubmitted tells whether the current attempt of the application was submitted.
+	SparkApplicationConditionSubmitted = "Submitted"
+	// SparkApplicationConditionRunning tells whether the driver of the application is running.
+	SparkApplicationConditionRunning = "Running"
+	// SparkApplicationConditionReady tells whether the driver of the application is ready, and so is its web UI.
+	SparkApplicationConditionReady = "Ready"
+	// SparkApplicationConditionSucceeded tells whether the application completed successfully.
+	SparkApplicationConditionSucceeded = "Succeeded"
+	// SparkApplicationConditionFailed tells whether the application failed for good, i.e. it is not retried.
+	SparkApplicationConditionFailed = "Failed"
+	// SparkApplicationConditionSuspended tells whether the application is suspended.
+	SparkApplicationConditionSuspended = "Suspended"
+)
+
 // DriverState tells the current state of a spark driver.
 type DriverState string
 
@@ -554,7 +915,9 @@ type DriverSpec struct {
 // ExecutorSpec is specification of the executor.
 type ExecutorSpec struct {
 	SparkPodSpec `json:",inline"`
-	// Instances is the number of executor instances.
+	// Instances is the number of executor instances, which are the replicas of the scale subresource. Changing it
+	// while the driver runs scales the executors live within the dynamic allocation bounds if dynamic allocation
+	// is enabled, and takes effect from the next attempt otherwise.
 	// +optional
 	// +kubebuilder:validation:Minimum=1
 	Instances *int32 `json:"instances,omitempty"`
@@ -614,6 +977,93 @@ type DriverInfo struct {
 	PodName             string `json:"podName,omitempty"`
 }
 
+// SubmissionAttemptInfo captures information about an attempt to submit the application.
+type SubmissionAttemptInfo struct {
+	// Attempt is the number of the submission attempt, matching SubmissionAttempts at the time of the attempt.
+	Attempt int32 `json:"attempt"`
+	// Submitter is the submitter used for the attempt.
+	// +optional
+	Submitter SubmitterType `json:"submitter,omitempty"`
+	// ExitCode is the exit code of spark-submit. It is not set if spark-submit was not run or was killed.
+	// +optional
+	ExitCode *int32 `json:"exitCode,omitempty"`
+	// Output is the tail of the combined standard output and standard error of spark-submit.
+	// +optional
+	Output string `json:"output,omitempty"`
+}
+
+// AttemptInfo captures information about an attempt to run the application.
+type AttemptInfo struct {
+	// Attempt is the number of the attempt, counted from the first attempt recorded in the history.
+	Attempt int32 `json:"attempt"`
+	// SubmissionID is the submission ID of the attempt.
+	SubmissionID string `json:"submissionID,omitempty"`
+	// SparkApplicationID is the Spark application ID of the attempt.
+	// +optional
+	SparkApplicationID string `json:"sparkApplicationId,omitempty"`
+	// DriverPodName is the name of the driver pod of the attempt.
+	// +optional
+	DriverPodName string `json:"driverPodName,omitempty"`
+	// StartTime is the time when the attempt was submitted.
+	// +nullable
+	StartTime metav1.Time `json:"startTime,omitempty"`
+	// EndTime is the time when the attempt terminated, if it did.
+	// +nullable
+	EndTime metav1.Time `json:"endTime,omitempty"`
+	// State is the final state of the attempt, i.e. COMPLETED, FAILED or SUBMISSION_FAILED, or its last state
+	// if it was interrupted or is still in progress.
+	State ApplicationStateType `json:"state,omitempty"`
+	// Reason is the reason of the state of the application when the attempt ended, e.g. SPEC_UPDATED.
+	// +optional
+	Reason ApplicationStateReason `json:"reason,omitempty"`
+	// FailureReason is the reason for the failure of the attempt, if it failed.
+	// +optional
+	FailureReason FailureReason `json:"failureReason,omitempty"`
+	// ErrorMessage is the beginning of the error message of the attempt, if any, truncated to a few hundred bytes.
+	// +optional
+	ErrorMessage string `json:"errorMessage,omitempty"`
+	// Executors summarizes the states of the executors of the attempt.
+	// +optional
+	Executors ExecutorSummary `json:"executors,omitempty"`
+}
+
+// ExecutorSummary counts the executors of an attempt by state.
+type ExecutorSummary struct {
+	// Total is the number of tracked executors.
+	Total int32 `json:"total,omitempty"`
+	// Running is the number of running executors.
+	Running int32 `json:"running,omitempty"`
+	// Completed is the number of completed executors.
+	Completed int32 `json:"completed,omitempty"`
+	// Failed is the number of failed executors.
+	Failed int32 `json:"failed,omitempty"`
+}
+
+// BatchSchedulerAdmissionPhase is the phase of the admission of an application by its batch scheduler.
+type BatchSchedulerAdmissionPhase string
+
+// Different phases of the admission of an application by its batch scheduler.
+const (
+	BatchSchedulerAdmissionPending  BatchSchedulerAdmissionPhase = "Pending"
+	BatchSchedulerAdmissionAdmitted BatchSchedulerAdmissionPhase = "Admitted"
+	BatchSchedulerAdmissionRejected BatchSchedulerAdmissionPhase = "Rejected"
+)
+
+// BatchSchedulerStatus captures the admission of an application by its batch scheduler.
+type BatchSchedulerStatus struct {
+	// Name is the name of the batch scheduler.
+	Name string `json:"name"`
+	// Phase is the phase of the admission of the application.
+	// +kubebuilder:validation:Enum={Pending,Admitted,Rejected}
+	Phase BatchSchedulerAdmissionPhase `json:"phase"`
+	// Reason is the reason given by the batch scheduler for the phase, e.g. what the application is waiting for.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// LastTransitionTime is the time the phase last changed.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
 // SecretInfo captures information of a secret.
 type SecretInfo struct {
 	Name string     `json:"name"`
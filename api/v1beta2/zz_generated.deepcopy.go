@@ -21,8 +21,9 @@ limitations under the License.
 package v1beta2
 
 import (
-	"k8s.io/api/core/v1"
+	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -41,6 +42,24 @@ func (in *ApplicationState) DeepCopy() *ApplicationState {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AttemptInfo) DeepCopyInto(out *AttemptInfo) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	in.EndTime.DeepCopyInto(&out.EndTime)
+	out.Executors = in.Executors
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AttemptInfo.
+func (in *AttemptInfo) DeepCopy() *AttemptInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(AttemptInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BatchSchedulerConfiguration) DeepCopyInto(out *BatchSchedulerConfiguration) {
 	*out = *in
@@ -56,11 +75,16 @@ func (in *BatchSchedulerConfiguration) DeepCopyInto(out *BatchSchedulerConfigura
 	}
 	if in.Resources != nil {
 		in, out := &in.Resources, &out.Resources
-		*out = make(v1.ResourceList, len(*in))
+		*out = make(corev1.ResourceList, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val.DeepCopy()
 		}
 	}
+	if in.GangPolicy != nil {
+		in, out := &in.GangPolicy, &out.GangPolicy
+		*out = new(GangPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchSchedulerConfiguration.
@@ -73,6 +97,22 @@ func (in *BatchSchedulerConfiguration) DeepCopy() *BatchSchedulerConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchSchedulerStatus) DeepCopyInto(out *BatchSchedulerStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchSchedulerStatus.
+func (in *BatchSchedulerStatus) DeepCopy() *BatchSchedulerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchSchedulerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Dependencies) DeepCopyInto(out *Dependencies) {
 	*out = *in
@@ -153,7 +193,7 @@ func (in *DriverIngressConfiguration) DeepCopyInto(out *DriverIngressConfigurati
 	}
 	if in.ServiceType != nil {
 		in, out := &in.ServiceType, &out.ServiceType
-		*out = new(v1.ServiceType)
+		*out = new(corev1.ServiceType)
 		**out = **in
 	}
 	if in.ServiceAnnotations != nil {
@@ -217,7 +257,7 @@ func (in *DriverSpec) DeepCopyInto(out *DriverSpec) {
 	}
 	if in.Lifecycle != nil {
 		in, out := &in.Lifecycle, &out.Lifecycle
-		*out = new(v1.Lifecycle)
+		*out = new(corev1.Lifecycle)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.KubernetesMaster != nil {
@@ -317,7 +357,7 @@ func (in *ExecutorSpec) DeepCopyInto(out *ExecutorSpec) {
 	}
 	if in.Lifecycle != nil {
 		in, out := &in.Lifecycle, &out.Lifecycle
-		*out = new(v1.Lifecycle)
+		*out = new(corev1.Lifecycle)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.DeleteOnTermination != nil {
@@ -347,6 +387,21 @@ func (in *ExecutorSpec) DeepCopy() *ExecutorSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecutorSummary) DeepCopyInto(out *ExecutorSummary) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecutorSummary.
+func (in *ExecutorSummary) DeepCopy() *ExecutorSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecutorSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GPUSpec) DeepCopyInto(out *GPUSpec) {
 	*out = *in
@@ -472,6 +527,41 @@ func (in *PrometheusSpec) DeepCopy() *PrometheusSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestartBackoff) DeepCopyInto(out *RestartBackoff) {
+	*out = *in
+	if in.BaseSeconds != nil {
+		in, out := &in.BaseSeconds, &out.BaseSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Multiplier != nil {
+		in, out := &in.Multiplier, &out.Multiplier
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxIntervalSeconds != nil {
+		in, out := &in.MaxIntervalSeconds, &out.MaxIntervalSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.JitterPercent != nil {
+		in, out := &in.JitterPercent, &out.JitterPercent
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestartBackoff.
+func (in *RestartBackoff) DeepCopy() *RestartBackoff {
+	if in == nil {
+		return nil
+	}
+	out := new(RestartBackoff)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RestartPolicy) DeepCopyInto(out *RestartPolicy) {
 	*out = *in
@@ -495,6 +585,21 @@ func (in *RestartPolicy) DeepCopyInto(out *RestartPolicy) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.RetryOnDeadlineExceeded != nil {
+		in, out := &in.RetryOnDeadlineExceeded, &out.RetryOnDeadlineExceeded
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Backoff != nil {
+		in, out := &in.Backoff, &out.Backoff
+		*out = new(RestartBackoff)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RetryOnFailureReasons != nil {
+		in, out := &in.RetryOnFailureReasons, &out.RetryOnFailureReasons
+		*out = make([]FailureReason, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestartPolicy.
@@ -569,12 +674,34 @@ func (in *ScheduledSparkApplicationList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScheduledSparkApplicationSpec) DeepCopyInto(out *ScheduledSparkApplicationSpec) {
 	*out = *in
+	if in.TimeZone != nil {
+		in, out := &in.TimeZone, &out.TimeZone
+		*out = new(string)
+		**out = **in
+	}
 	in.Template.DeepCopyInto(&out.Template)
+	if in.Tasks != nil {
+		in, out := &in.Tasks, &out.Tasks
+		*out = make([]ScheduledSparkApplicationTask, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Suspend != nil {
 		in, out := &in.Suspend, &out.Suspend
 		*out = new(bool)
 		**out = **in
 	}
+	if in.StartingDeadlineSeconds != nil {
+		in, out := &in.StartingDeadlineSeconds, &out.StartingDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxCatchUpRuns != nil {
+		in, out := &in.MaxCatchUpRuns, &out.MaxCatchUpRuns
+		*out = new(int32)
+		**out = **in
+	}
 	if in.SuccessfulRunHistoryLimit != nil {
 		in, out := &in.SuccessfulRunHistoryLimit, &out.SuccessfulRunHistoryLimit
 		*out = new(int32)
@@ -612,6 +739,20 @@ func (in *ScheduledSparkApplicationStatus) DeepCopyInto(out *ScheduledSparkAppli
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.PendingManualRuns != nil {
+		in, out := &in.PendingManualRuns, &out.PendingManualRuns
+		*out = make([]v1.Time, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledSparkApplicationStatus.
@@ -624,6 +765,22 @@ func (in *ScheduledSparkApplicationStatus) DeepCopy() *ScheduledSparkApplication
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledSparkApplicationTask) DeepCopyInto(out *ScheduledSparkApplicationTask) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledSparkApplicationTask.
+func (in *ScheduledSparkApplicationTask) DeepCopy() *ScheduledSparkApplicationTask {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledSparkApplicationTask)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretInfo) DeepCopyInto(out *SecretInfo) {
 	*out = *in
@@ -639,6 +796,100 @@ func (in *SecretInfo) DeepCopy() *SecretInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerIngressConfiguration) DeepCopyInto(out *ServerIngressConfiguration) {
+	*out = *in
+	if in.IngressClassName != nil {
+		in, out := &in.IngressClassName, &out.IngressClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.IngressAnnotations != nil {
+		in, out := &in.IngressAnnotations, &out.IngressAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.IngressTLS != nil {
+		in, out := &in.IngressTLS, &out.IngressTLS
+		*out = make([]networkingv1.IngressTLS, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerIngressConfiguration.
+func (in *ServerIngressConfiguration) DeepCopy() *ServerIngressConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerIngressConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerSpec) DeepCopyInto(out *ServerSpec) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ServiceType != nil {
+		in, out := &in.ServiceType, &out.ServiceType
+		*out = new(corev1.ServiceType)
+		**out = **in
+	}
+	if in.ServiceAnnotations != nil {
+		in, out := &in.ServiceAnnotations, &out.ServiceAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ServiceLabels != nil {
+		in, out := &in.ServiceLabels, &out.ServiceLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(ServerIngressConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerSpec.
+func (in *ServerSpec) DeepCopy() *ServerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerStatus) DeepCopyInto(out *ServerStatus) {
+	*out = *in
+	in.LastHealthTransitionTime.DeepCopyInto(&out.LastHealthTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerStatus.
+func (in *ServerStatus) DeepCopy() *ServerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SparkApplication) DeepCopyInto(out *SparkApplication) {
 	*out = *in
@@ -762,7 +1013,7 @@ func (in *SparkApplicationSpec) DeepCopyInto(out *SparkApplicationSpec) {
 	}
 	if in.Volumes != nil {
 		in, out := &in.Volumes, &out.Volumes
-		*out = make([]v1.Volume, len(*in))
+		*out = make([]corev1.Volume, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -835,6 +1086,51 @@ func (in *SparkApplicationSpec) DeepCopyInto(out *SparkApplicationSpec) {
 		*out = new(DynamicAllocation)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Submitter != nil {
+		in, out := &in.Submitter, &out.Submitter
+		*out = new(SubmitterType)
+		**out = **in
+	}
+	if in.SubmissionTimeoutSeconds != nil {
+		in, out := &in.SubmissionTimeoutSeconds, &out.SubmissionTimeoutSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ActiveDeadlineSeconds != nil {
+		in, out := &in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PendingDeadlineSeconds != nil {
+		in, out := &in.PendingDeadlineSeconds, &out.PendingDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.StuckPendingPolicy != nil {
+		in, out := &in.StuckPendingPolicy, &out.StuckPendingPolicy
+		*out = new(StuckPendingPolicy)
+		**out = **in
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Server != nil {
+		in, out := &in.Server, &out.Server
+		*out = new(ServerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SparkApplicationSpec.
@@ -851,6 +1147,7 @@ func (in *SparkApplicationSpec) DeepCopy() *SparkApplicationSpec {
 func (in *SparkApplicationStatus) DeepCopyInto(out *SparkApplicationStatus) {
 	*out = *in
 	in.LastSubmissionAttemptTime.DeepCopyInto(&out.LastSubmissionAttemptTime)
+	in.NextRetryTime.DeepCopyInto(&out.NextRetryTime)
 	in.TerminationTime.DeepCopyInto(&out.TerminationTime)
 	out.DriverInfo = in.DriverInfo
 	out.AppState = in.AppState
@@ -861,6 +1158,40 @@ func (in *SparkApplicationStatus) DeepCopyInto(out *SparkApplicationStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.LastSubmissionAttempt != nil {
+		in, out := &in.LastSubmissionAttempt, &out.LastSubmissionAttempt
+		*out = new(SubmissionAttemptInfo)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BatchSchedulerStatus != nil {
+		in, out := &in.BatchSchedulerStatus, &out.BatchSchedulerStatus
+		*out = new(BatchSchedulerStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServerStatus != nil {
+		in, out := &in.ServerStatus, &out.ServerStatus
+		*out = new(ServerStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExecutorLimit != nil {
+		in, out := &in.ExecutorLimit, &out.ExecutorLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Attempts != nil {
+		in, out := &in.Attempts, &out.Attempts
+		*out = make([]AttemptInfo, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SparkApplicationStatus.
@@ -878,7 +1209,7 @@ func (in *SparkPodSpec) DeepCopyInto(out *SparkPodSpec) {
 	*out = *in
 	if in.Template != nil {
 		in, out := &in.Template, &out.Template
-		*out = new(v1.PodTemplateSpec)
+		*out = new(corev1.PodTemplateSpec)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.Cores != nil {
@@ -923,7 +1254,7 @@ func (in *SparkPodSpec) DeepCopyInto(out *SparkPodSpec) {
 	}
 	if in.Env != nil {
 		in, out := &in.Env, &out.Env
-		*out = make([]v1.EnvVar, len(*in))
+		*out = make([]corev1.EnvVar, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -937,7 +1268,7 @@ func (in *SparkPodSpec) DeepCopyInto(out *SparkPodSpec) {
 	}
 	if in.EnvFrom != nil {
 		in, out := &in.EnvFrom, &out.EnvFrom
-		*out = make([]v1.EnvFromSource, len(*in))
+		*out = make([]corev1.EnvFromSource, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -965,31 +1296,31 @@ func (in *SparkPodSpec) DeepCopyInto(out *SparkPodSpec) {
 	}
 	if in.VolumeMounts != nil {
 		in, out := &in.VolumeMounts, &out.VolumeMounts
-		*out = make([]v1.VolumeMount, len(*in))
+		*out = make([]corev1.VolumeMount, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	if in.Affinity != nil {
 		in, out := &in.Affinity, &out.Affinity
-		*out = new(v1.Affinity)
+		*out = new(corev1.Affinity)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.Tolerations != nil {
 		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]v1.Toleration, len(*in))
+		*out = make([]corev1.Toleration, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	if in.PodSecurityContext != nil {
 		in, out := &in.PodSecurityContext, &out.PodSecurityContext
-		*out = new(v1.PodSecurityContext)
+		*out = new(corev1.PodSecurityContext)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.SecurityContext != nil {
 		in, out := &in.SecurityContext, &out.SecurityContext
-		*out = new(v1.SecurityContext)
+		*out = new(corev1.SecurityContext)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.SchedulerName != nil {
@@ -999,14 +1330,14 @@ func (in *SparkPodSpec) DeepCopyInto(out *SparkPodSpec) {
 	}
 	if in.Sidecars != nil {
 		in, out := &in.Sidecars, &out.Sidecars
-		*out = make([]v1.Container, len(*in))
+		*out = make([]corev1.Container, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	if in.InitContainers != nil {
 		in, out := &in.InitContainers, &out.InitContainers
-		*out = make([]v1.Container, len(*in))
+		*out = make([]corev1.Container, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -1025,7 +1356,7 @@ func (in *SparkPodSpec) DeepCopyInto(out *SparkPodSpec) {
 	}
 	if in.DNSConfig != nil {
 		in, out := &in.DNSConfig, &out.DNSConfig
-		*out = new(v1.PodDNSConfig)
+		*out = new(corev1.PodDNSConfig)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.TerminationGracePeriodSeconds != nil {
@@ -1040,7 +1371,7 @@ func (in *SparkPodSpec) DeepCopyInto(out *SparkPodSpec) {
 	}
 	if in.HostAliases != nil {
 		in, out := &in.HostAliases, &out.HostAliases
-		*out = make([]v1.HostAlias, len(*in))
+		*out = make([]corev1.HostAlias, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -1077,7 +1408,7 @@ func (in *SparkUIConfiguration) DeepCopyInto(out *SparkUIConfiguration) {
 	}
 	if in.ServiceType != nil {
 		in, out := &in.ServiceType, &out.ServiceType
-		*out = new(v1.ServiceType)
+		*out = new(corev1.ServiceType)
 		**out = **in
 	}
 	if in.ServiceAnnotations != nil {
@@ -1119,3 +1450,38 @@ func (in *SparkUIConfiguration) DeepCopy() *SparkUIConfiguration {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StuckPendingPolicy) DeepCopyInto(out *StuckPendingPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StuckPendingPolicy.
+func (in *StuckPendingPolicy) DeepCopy() *StuckPendingPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(StuckPendingPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubmissionAttemptInfo) DeepCopyInto(out *SubmissionAttemptInfo) {
+	*out = *in
+	if in.ExitCode != nil {
+		in, out := &in.ExitCode, &out.ExitCode
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubmissionAttemptInfo.
+func (in *SubmissionAttemptInfo) DeepCopy() *SubmissionAttemptInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(SubmissionAttemptInfo)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -165,6 +165,7 @@ Create the role policy rules for the controller in every Spark job namespace
   verbs:
   - get
   - create
+  - update
   - delete
   - list
   - watch
@@ -192,6 +193,16 @@ Create the role policy rules for the controller in every Spark job namespace
   - get
   - update
   - patch
+{{- if .Values.controller.admissionQueue.enable }}
+- apiGroups:
+  - ""
+  resources:
+  - resourcequotas
+  verbs:
+  - get
+  - list
+  - watch
+{{- end }}
 {{- if .Values.controller.batchScheduler.enable }}
 {{/* required for the `volcano` batch scheduler */}}
 - apiGroups:
@@ -202,5 +213,20 @@ Create the role policy rules for the controller in every Spark job namespace
   - podgroups
   verbs:
   - "*"
+- apiGroups:
+  - scheduling.volcano.sh
+  resources:
+  - queues
+  verbs:
+  - get
+  - list
+  - watch
+{{/* required for the `kueue` batch scheduler */}}
+- apiGroups:
+  - kueue.x-k8s.io
+  resources:
+  - workloads
+  verbs:
+  - "*"
 {{- end }}
 {{- end -}}
@@ -19,6 +19,8 @@ package main
 import (
 	"fmt"
 	"os"
+	// Embed the time zone database for the time zones of ScheduledSparkApplications.
+	_ "time/tzdata"
 
 	"github.com/kubeflow/spark-operator/cmd/operator"
 )
@@ -33,6 +33,7 @@ import (
 	"go.uber.org/zap/zapcore"
 	"golang.org/x/time/rate"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -57,6 +58,7 @@ import (
 	"github.com/kubeflow/spark-operator/internal/metrics"
 	"github.com/kubeflow/spark-operator/internal/scheduler"
 	"github.com/kubeflow/spark-operator/internal/scheduler/kubescheduler"
+	"github.com/kubeflow/spark-operator/internal/scheduler/kueue"
 	"github.com/kubeflow/spark-operator/internal/scheduler/volcano"
 	"github.com/kubeflow/spark-operator/internal/scheduler/yunikorn"
 	"github.com/kubeflow/spark-operator/pkg/common"
@@ -76,6 +78,7 @@ var (
 	controllerThreads        int
 	cacheSyncTimeout         time.Duration
 	maxTrackedExecutorPerApp int
+	maxAttemptHistory        int
 
 	//WorkQueue
 	workqueueRateLimiterBucketQPS  int
@@ -87,6 +90,18 @@ var (
 	kubeSchedulerNames    []string
 	defaultBatchScheduler string
 
+	// Submission
+	defaultSubmitter  string
+	submissionWorkers int
+	submissionTimeout time.Duration
+
+	// Admission queue
+	enableAdmissionQueue  bool
+	namespaceCPUBudget    resource.QuantityValue
+	namespaceMemoryBudget resource.QuantityValue
+	enablePreemption      bool
+	preemptionGracePeriod time.Duration
+
 	// Spark web UI service and ingress
 	enableUIService  bool
 	ingressClassName string
@@ -142,6 +157,7 @@ func NewStartCommand() *cobra.Command {
 	command.Flags().StringSliceVar(&namespaces, "namespaces", []string{}, "The Kubernetes namespace to manage. Will manage custom resource objects of the managed CRD types for the whole cluster if unset or contains empty string.")
 	command.Flags().DurationVar(&cacheSyncTimeout, "cache-sync-timeout", 30*time.Second, "Informer cache sync timeout.")
 	command.Flags().IntVar(&maxTrackedExecutorPerApp, "max-tracked-executor-per-app", 1000, "The maximum number of tracked executors per SparkApplication.")
+	command.Flags().IntVar(&maxAttemptHistory, "max-attempt-history", 10, "The maximum number of attempts recorded in the status of each SparkApplication. The history is disabled if it is zero.")
 
 	command.Flags().IntVar(&workqueueRateLimiterBucketQPS, "workqueue-ratelimiter-bucket-qps", 10, "QPS of the bucket rate of the workqueue.")
 	command.Flags().IntVar(&workqueueRateLimiterBucketSize, "workqueue-ratelimiter-bucket-size", 100, "The token bucket size of the workqueue.")
@@ -151,6 +167,16 @@ func NewStartCommand() *cobra.Command {
 	command.Flags().StringSliceVar(&kubeSchedulerNames, "kube-scheduler-names", []string{}, "The kube-scheduler names for scheduling Spark applications.")
 	command.Flags().StringVar(&defaultBatchScheduler, "default-batch-scheduler", "", "Default batch scheduler.")
 
+	command.Flags().StringVar(&defaultSubmitter, "default-submitter", string(v1beta2.SubmitterTypeSparkSubmit), "Default submitter used to launch the driver of SparkApplications. Can be one of spark-submit or native.")
+	command.Flags().IntVar(&submissionWorkers, "submission-workers", 10, "Number of workers submitting SparkApplications asynchronously. SparkApplications are submitted by the controller worker threads if set to 0.")
+	command.Flags().DurationVar(&submissionTimeout, "submission-timeout", 0, "Default timeout of a single SparkApplication submission, which can be overridden by spec.submissionTimeoutSeconds. The timeout is disabled if set to 0.")
+
+	command.Flags().BoolVar(&enableAdmissionQueue, "enable-admission-queue", false, "Hold SparkApplications in the QUEUED state until they fit the resource quotas and the budget of their namespace.")
+	command.Flags().Var(&namespaceCPUBudget, "namespace-cpu-budget", "Maximum CPU requested by the admitted SparkApplications of each namespace. Not limited if set to 0. Requires the admission queue to be enabled.")
+	command.Flags().Var(&namespaceMemoryBudget, "namespace-memory-budget", "Maximum memory requested by the admitted SparkApplications of each namespace. Not limited if set to 0. Requires the admission queue to be enabled.")
+	command.Flags().BoolVar(&enablePreemption, "enable-preemption", false, "Preempt running SparkApplications with a lower priority when a queued SparkApplication does not fit the budget of its namespace. Requires the admission queue to be enabled.")
+	command.Flags().DurationVar(&preemptionGracePeriod, "preemption-grace-period", 30*time.Second, "Grace period given to the driver of a preempted SparkApplication to terminate.")
+
 	command.Flags().BoolVar(&enableUIService, "enable-ui-service", true, "Enable Spark Web UI service.")
 	command.Flags().StringVar(&ingressClassName, "ingress-class-name", "", "Set ingressClassName for ingress resources created.")
 	command.Flags().StringVar(&ingressURLFormat, "ingress-url-format", "", "Ingress URL format.")
@@ -247,6 +273,7 @@ func start() {
 		registry = scheduler.GetRegistry()
 		_ = registry.Register(common.VolcanoSchedulerName, volcano.Factory)
 		_ = registry.Register(yunikorn.SchedulerName, yunikorn.Factory)
+		_ = registry.Register(kueue.SchedulerName, kueue.Factory)
 
 		// Register kube-schedulers.
 		for _, name := range kubeSchedulerNames {
@@ -260,6 +287,11 @@ func start() {
 		}
 	}
 
+	if defaultSubmitter != string(v1beta2.SubmitterTypeSparkSubmit) && defaultSubmitter != string(v1beta2.SubmitterTypeNative) {
+		logger.Error(nil, "Unsupported default submitter", "submitter", defaultSubmitter)
+		os.Exit(1)
+	}
+
 	// Setup controller for SparkApplication.
 	if err = sparkapplication.NewReconciler(
 		mgr,
@@ -387,11 +419,14 @@ func newControllerOptions() controller.Options {
 func newSparkApplicationReconcilerOptions() sparkapplication.Options {
 	var sparkApplicationMetrics *metrics.SparkApplicationMetrics
 	var sparkExecutorMetrics *metrics.SparkExecutorMetrics
+	var submissionMetrics *metrics.SubmissionMetrics
 	if enableMetrics {
 		sparkApplicationMetrics = metrics.NewSparkApplicationMetrics(metricsPrefix, metricsLabels, metricsJobStartLatencyBuckets)
 		sparkApplicationMetrics.Register()
 		sparkExecutorMetrics = metrics.NewSparkExecutorMetrics(metricsPrefix, metricsLabels)
 		sparkExecutorMetrics.Register()
+		submissionMetrics = metrics.NewSubmissionMetrics(metricsPrefix)
+		submissionMetrics.Register()
 	}
 	options := sparkapplication.Options{
 		Namespaces:               namespaces,
@@ -399,9 +434,18 @@ func newSparkApplicationReconcilerOptions() sparkapplication.Options {
 		IngressClassName:         ingressClassName,
 		IngressURLFormat:         ingressURLFormat,
 		DefaultBatchScheduler:    defaultBatchScheduler,
+		DefaultSubmitter:         defaultSubmitter,
 		SparkApplicationMetrics:  sparkApplicationMetrics,
 		SparkExecutorMetrics:     sparkExecutorMetrics,
 		MaxTrackedExecutorPerApp: maxTrackedExecutorPerApp,
+		MaxAttemptHistory:        maxAttemptHistory,
+		SubmissionWorkers:        submissionWorkers,
+		SubmissionTimeout:        submissionTimeout,
+		SubmissionMetrics:        submissionMetrics,
+		EnableAdmissionQueue:     enableAdmissionQueue,
+		NamespaceBudget:          newNamespaceBudget(),
+		EnablePreemption:         enablePreemption,
+		PreemptionGracePeriod:    preemptionGracePeriod,
 	}
 	if enableBatchScheduler {
 		options.KubeSchedulerNames = kubeSchedulerNames
@@ -409,6 +453,18 @@ func newSparkApplicationReconcilerOptions() sparkapplication.Options {
 	return options
 }
 
+// newNamespaceBudget creates and returns the per-namespace resource budget of SparkApplications.
+func newNamespaceBudget() corev1.ResourceList {
+	budget := corev1.ResourceList{}
+	if !namespaceCPUBudget.IsZero() {
+		budget[corev1.ResourceCPU] = namespaceCPUBudget.Quantity
+	}
+	if !namespaceMemoryBudget.IsZero() {
+		budget[corev1.ResourceMemory] = namespaceMemoryBudget.Quantity
+	}
+	return budget
+}
+
 func newScheduledSparkApplicationReconcilerOptions() scheduledsparkapplication.Options {
 	options := scheduledsparkapplication.Options{
 		Namespaces: namespaces,
@@ -0,0 +1,59 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduledsparkapplication
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+)
+
+// setScheduledSparkApplicationConditions sets the conditions of the given ScheduledSparkApplication according to
+// its schedule state and whether it is suspended.
+func setScheduledSparkApplicationConditions(scheduledApp *v1beta2.ScheduledSparkApplication) {
+	switch scheduledApp.Status.ScheduleState {
+	case v1beta2.ScheduleStateScheduled:
+		setScheduledSparkApplicationCondition(scheduledApp, v1beta2.ScheduledSparkApplicationConditionScheduled, true, string(v1beta2.ScheduleStateScheduled), "")
+	case v1beta2.ScheduleStateFailedValidation:
+		setScheduledSparkApplicationCondition(scheduledApp, v1beta2.ScheduledSparkApplicationConditionScheduled, false, string(v1beta2.ScheduleStateFailedValidation), scheduledApp.Status.Reason)
+	case v1beta2.ScheduleStateValidating:
+		setScheduledSparkApplicationCondition(scheduledApp, v1beta2.ScheduledSparkApplicationConditionScheduled, false, string(v1beta2.ScheduleStateValidating), "")
+	default:
+		setScheduledSparkApplicationCondition(scheduledApp, v1beta2.ScheduledSparkApplicationConditionScheduled, false, "New", "")
+	}
+
+	if scheduledApp.Spec.Suspend != nil && *scheduledApp.Spec.Suspend {
+		setScheduledSparkApplicationCondition(scheduledApp, v1beta2.ScheduledSparkApplicationConditionSuspended, true, "Suspended", "no run is started while the application is suspended")
+	} else {
+		setScheduledSparkApplicationCondition(scheduledApp, v1beta2.ScheduledSparkApplicationConditionSuspended, false, "NotSuspended", "")
+	}
+}
+
+func setScheduledSparkApplicationCondition(scheduledApp *v1beta2.ScheduledSparkApplication, conditionType string, status bool, reason, message string) {
+	conditionStatus := metav1.ConditionFalse
+	if status {
+		conditionStatus = metav1.ConditionTrue
+	}
+	meta.SetStatusCondition(&scheduledApp.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             conditionStatus,
+		ObservedGeneration: scheduledApp.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduledsparkapplication
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+func TestSetScheduledSparkApplicationConditions(t *testing.T) {
+	scheduledApp := &v1beta2.ScheduledSparkApplication{}
+	scheduledApp.Generation = 2
+	scheduledApp.Status.ScheduleState = v1beta2.ScheduleStateScheduled
+
+	setScheduledSparkApplicationConditions(scheduledApp)
+	assert.True(t, meta.IsStatusConditionTrue(scheduledApp.Status.Conditions, v1beta2.ScheduledSparkApplicationConditionScheduled))
+	assert.True(t, meta.IsStatusConditionFalse(scheduledApp.Status.Conditions, v1beta2.ScheduledSparkApplicationConditionSuspended))
+	assert.Equal(t, int64(2), meta.FindStatusCondition(scheduledApp.Status.Conditions, v1beta2.ScheduledSparkApplicationConditionScheduled).ObservedGeneration)
+
+	scheduledApp.Spec.Suspend = util.BoolPtr(true)
+	setScheduledSparkApplicationConditions(scheduledApp)
+	assert.True(t, meta.IsStatusConditionTrue(scheduledApp.Status.Conditions, v1beta2.ScheduledSparkApplicationConditionSuspended))
+
+	scheduledApp.Status.ScheduleState = v1beta2.ScheduleStateFailedValidation
+	scheduledApp.Status.Reason = "invalid schedule"
+	setScheduledSparkApplicationConditions(scheduledApp)
+	scheduled := meta.FindStatusCondition(scheduledApp.Status.Conditions, v1beta2.ScheduledSparkApplicationConditionScheduled)
+	assert.Equal(t, "FailedValidation", scheduled.Reason)
+	assert.Equal(t, "invalid schedule", scheduled.Message)
+	assert.True(t, meta.IsStatusConditionFalse(scheduledApp.Status.Conditions, v1beta2.ScheduledSparkApplicationConditionScheduled))
+}
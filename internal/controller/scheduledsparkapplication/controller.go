@@ -24,6 +24,8 @@ import (
 	"time"
 
 	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -47,6 +49,18 @@ var (
 	logger = log.Log.WithName("")
 )
 
+const (
+	// scheduleTimeLayout is the layout of the schedule times of the runs in their labels.
+	scheduleTimeLayout = "20060102T150405Z"
+
+	// defaultMaxCatchUpRuns is the default maximum number of missed runs to start with the All catch-up policy.
+	defaultMaxCatchUpRuns = 10
+
+	// catchUpRetryInterval is the interval at which missed runs are retried with the All catch-up policy if the
+	// concurrency policy does not allow them to start.
+	catchUpRetryInterval = 10 * time.Second
+)
+
 type Options struct {
 	Namespaces []string
 }
@@ -103,13 +117,21 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	scheduledApp := oldScheduledApp.DeepCopy()
 	logger.Info("Reconciling ScheduledSparkApplication", "name", scheduledApp.Name, "namespace", scheduledApp.Namespace, "state", scheduledApp.Status.ScheduleState)
 
+	// The conditions may change without the schedule state, e.g. as the application is suspended or resumed.
+	setScheduledSparkApplicationConditions(scheduledApp)
+	if !equality.Semantic.DeepEqual(oldScheduledApp.Status, scheduledApp.Status) {
+		if err := r.updateScheduledSparkApplicationStatus(ctx, scheduledApp); err != nil {
+			return ctrl.Result{Requeue: true}, err
+		}
+	}
+
 	if scheduledApp.Spec.Suspend != nil && *scheduledApp.Spec.Suspend {
 		return ctrl.Result{}, nil
 	}
 
-	schedule, parseErr := cron.ParseStandard(scheduledApp.Spec.Schedule)
+	schedule, parseErr := parseSchedule(scheduledApp)
 	if parseErr != nil {
-		logger.Error(err, "Failed to parse schedule of ScheduledSparkApplication", "name", scheduledApp.Name, "namespace", scheduledApp.Namespace, "schedule", scheduledApp.Spec.Schedule)
+		logger.Error(parseErr, "Failed to parse schedule of ScheduledSparkApplication", "name", scheduledApp.Name, "namespace", scheduledApp.Namespace, "schedule", scheduledApp.Spec.Schedule)
 		scheduledApp.Status.ScheduleState = v1beta2.ScheduleStateFailedValidation
 		scheduledApp.Status.Reason = parseErr.Error()
 		if updateErr := r.updateScheduledSparkApplicationStatus(ctx, scheduledApp); updateErr != nil {
@@ -118,6 +140,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, nil
 	}
 
+	if scheduledApp.Status.ScheduleState == v1beta2.ScheduleStateScheduled {
+		if err := r.reconcileManualRuns(ctx, scheduledApp, schedule); err != nil {
+			return ctrl.Result{Requeue: true}, err
+		}
+		// Manual runs are only held back by the concurrency policy, which holds back the scheduled runs as well.
+		if len(scheduledApp.Status.PendingManualRuns) > 0 {
+			return ctrl.Result{RequeueAfter: manualRunRetryInterval}, nil
+		}
+	}
+
 	switch scheduledApp.Status.ScheduleState {
 	case v1beta2.ScheduleStateNew:
 		now := r.clock.Now()
@@ -126,6 +158,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		if oldNextRunTime.IsZero() || nextRunTime.Before(oldNextRunTime) {
 			scheduledApp.Status.NextRun = metav1.NewTime(nextRunTime)
 		}
+		scheduledApp.Status.ObservedGeneration = scheduledApp.Generation
 		scheduledApp.Status.ScheduleState = v1beta2.ScheduleStateScheduled
 		if err := r.updateScheduledSparkApplicationStatus(ctx, scheduledApp); err != nil {
 			return ctrl.Result{Requeue: true}, err
@@ -133,6 +166,17 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{RequeueAfter: nextRunTime.Sub(now)}, err
 	case v1beta2.ScheduleStateScheduled:
 		now := r.clock.Now()
+		// The next run is computed again from the current schedule once the spec has changed, unless it is already
+		// due, so that it is started before a new schedule or time zone takes effect.
+		if scheduledApp.Status.ObservedGeneration != scheduledApp.Generation {
+			if scheduledApp.Status.NextRun.After(now) {
+				scheduledApp.Status.NextRun = metav1.NewTime(schedule.Next(now))
+			}
+			scheduledApp.Status.ObservedGeneration = scheduledApp.Generation
+			if err := r.updateScheduledSparkApplicationStatus(ctx, scheduledApp); err != nil {
+				return ctrl.Result{Requeue: true}, err
+			}
+		}
 		nextRunTime := scheduledApp.Status.NextRun
 		if nextRunTime.IsZero() {
 			scheduledApp.Status.NextRun = metav1.NewTime(schedule.Next(now))
@@ -146,31 +190,72 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			return ctrl.Result{RequeueAfter: nextRunTime.Time.Sub(now)}, nil
 		}
 
+		scheduleTimes, skipped := getDueScheduleTimes(scheduledApp, schedule, now)
+		if skipped > 0 {
+			logger.Info("Skipping missed runs of ScheduledSparkApplication", "name", scheduledApp.Name, "namespace", scheduledApp.Namespace, "count", skipped)
+			r.recorder.Eventf(
+				scheduledApp,
+				corev1.EventTypeWarning,
+				common.EventScheduledSparkApplicationMissedRuns,
+				"Skipped %d missed runs of ScheduledSparkApplication %s",
+				skipped,
+				scheduledApp.Name,
+			)
+		}
+		if len(scheduleTimes) == 0 {
+			scheduledApp.Status.NextRun = metav1.NewTime(schedule.Next(now))
+			if err := r.updateScheduledSparkApplicationStatus(ctx, scheduledApp); err != nil {
+				return ctrl.Result{Requeue: true}, err
+			}
+			return ctrl.Result{RequeueAfter: schedule.Next(now).Sub(now)}, nil
+		}
+		scheduleTime := scheduleTimes[0]
+
 		ok, err := r.shouldStartNextRun(scheduledApp)
 		if err != nil {
 			return ctrl.Result{Requeue: true}, err
 		}
 		if !ok {
-			return ctrl.Result{RequeueAfter: schedule.Next(now).Sub(now)}, nil
+			// Record the skipped runs so that they are not reported again.
+			if skipped > 0 {
+				scheduledApp.Status.NextRun = metav1.NewTime(scheduleTime)
+				if err := r.updateScheduledSparkApplicationStatus(ctx, scheduledApp); err != nil {
+					return ctrl.Result{Requeue: true}, err
+				}
+			}
+			// The missed runs to catch up with are started as soon as the previous run has finished.
+			requeueAfter := schedule.Next(now).Sub(now)
+			if scheduledApp.Spec.CatchUpPolicy == v1beta2.CatchUpAll && requeueAfter > catchUpRetryInterval {
+				requeueAfter = catchUpRetryInterval
+			}
+			return ctrl.Result{RequeueAfter: requeueAfter}, nil
 		}
 
-		logger.Info("Next run of ScheduledSparkApplication is due", "name", scheduledApp.Name, "namespace", scheduledApp.Namespace)
-		app, err := r.startNextRun(scheduledApp, now)
+		logger.Info("Next run of ScheduledSparkApplication is due", "name", scheduledApp.Name, "namespace", scheduledApp.Namespace, "scheduleTime", scheduleTime)
+		app, err := r.startNextRun(scheduledApp, util.GetScheduledRunName(scheduledApp.Name, scheduleTime), scheduleTime)
 		if err != nil {
 			logger.Error(err, "Failed to start next run for ScheduledSparkApplication", "name", scheduledApp.Name, "namespace", scheduledApp.Namespace)
 			return ctrl.Result{RequeueAfter: schedule.Next(now).Sub(now)}, err
 		}
 
-		scheduledApp.Status.LastRun = metav1.NewTime(now)
+		scheduledApp.Status.LastRun = metav1.NewTime(scheduleTime)
 		scheduledApp.Status.LastRunName = app.Name
-		scheduledApp.Status.NextRun = metav1.NewTime(schedule.Next(now))
+		// The remaining missed runs are started one after another.
+		if len(scheduleTimes) > 1 {
+			scheduledApp.Status.NextRun = metav1.NewTime(scheduleTimes[1])
+		} else {
+			scheduledApp.Status.NextRun = metav1.NewTime(schedule.Next(now))
+		}
 		if err = r.checkAndUpdatePastRuns(ctx, scheduledApp); err != nil {
 			return ctrl.Result{Requeue: true}, err
 		}
 		if err := r.updateScheduledSparkApplicationStatus(ctx, scheduledApp); err != nil {
 			return ctrl.Result{Requeue: true}, err
 		}
-		return ctrl.Result{RequeueAfter: schedule.Next(now).Sub(now)}, nil
+		if requeueAfter := scheduledApp.Status.NextRun.Sub(now); requeueAfter > 0 {
+			return ctrl.Result{RequeueAfter: requeueAfter}, nil
+		}
+		return ctrl.Result{Requeue: true}, nil
 	case v1beta2.ScheduleStateFailedValidation:
 		return ctrl.Result{}, nil
 	}
@@ -192,6 +277,88 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, options controller.Optio
 		Complete(r)
 }
 
+// parseSchedule parses the cron schedule of the given ScheduledSparkApplication in its time zone.
+func parseSchedule(scheduledApp *v1beta2.ScheduledSparkApplication) (cron.Schedule, error) {
+	schedule, err := cron.ParseStandard(scheduledApp.Spec.Schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	if scheduledApp.Spec.TimeZone == nil {
+		return schedule, nil
+	}
+	location, err := getScheduleLocation(scheduledApp)
+	if err != nil {
+		return nil, err
+	}
+	// Schedules like "@every 1h" do not depend on the time zone.
+	if specSchedule, ok := schedule.(*cron.SpecSchedule); ok {
+		specSchedule.Location = location
+	}
+	return schedule, nil
+}
+
+// getDueScheduleTimes returns the scheduled times of the runs of the given ScheduledSparkApplication to start
+// in chronological order, i.e. the runs that are due since its next run according to its starting deadline and
+// catch-up policy. It also returns the number of due runs that are skipped.
+func getDueScheduleTimes(scheduledApp *v1beta2.ScheduledSparkApplication, schedule cron.Schedule, now time.Time) ([]time.Time, int) {
+	var due []time.Time
+	for t := scheduledApp.Status.NextRun.Time; !t.IsZero() && !t.After(now); t = schedule.Next(t) {
+		due = append(due, t)
+	}
+
+	startable := due
+	if scheduledApp.Spec.StartingDeadlineSeconds != nil {
+		deadline := time.Duration(*scheduledApp.Spec.StartingDeadlineSeconds) * time.Second
+		startable = nil
+		for _, t := range due {
+			if now.Sub(t) <= deadline {
+				startable = append(startable, t)
+			}
+		}
+	}
+
+	var scheduleTimes []time.Time
+	switch scheduledApp.Spec.CatchUpPolicy {
+	case v1beta2.CatchUpNone:
+		if len(due) == 1 {
+			scheduleTimes = startable
+		}
+	case v1beta2.CatchUpAll:
+		limit := defaultMaxCatchUpRuns
+		if scheduledApp.Spec.MaxCatchUpRuns != nil {
+			limit = int(*scheduledApp.Spec.MaxCatchUpRuns)
+		}
+		if len(startable) > limit {
+			startable = startable[len(startable)-limit:]
+		}
+		scheduleTimes = startable
+	default:
+		if len(startable) > 0 {
+			scheduleTimes = startable[len(startable)-1:]
+		}
+	}
+	return scheduleTimes, len(due) - len(scheduleTimes)
+}
+
+// formatScheduleTime formats the given schedule time as a label value.
+func formatScheduleTime(t time.Time) string {
+	return t.UTC().Format(scheduleTimeLayout)
+}
+
+// getScheduleLocation returns the time zone of the schedule of the given ScheduledSparkApplication, or the local
+// time zone if it does not specify one.
+func getScheduleLocation(scheduledApp *v1beta2.ScheduledSparkApplication) (*time.Location, error) {
+	if scheduledApp.Spec.TimeZone == nil {
+		return time.Local, nil
+	}
+	location, err := time.LoadLocation(*scheduledApp.Spec.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q: %v", *scheduledApp.Spec.TimeZone, err)
+	}
+	return location, nil
+}
+
 func (r *Reconciler) getScheduledSparkApplication(ctx context.Context, key types.NamespacedName) (*v1beta2.ScheduledSparkApplication, error) {
 	app := &v1beta2.ScheduledSparkApplication{}
 	if err := r.client.Get(ctx, key, app); err != nil {
@@ -202,6 +369,7 @@ func (r *Reconciler) getScheduledSparkApplication(ctx context.Context, key types
 
 func (r *Reconciler) createSparkApplication(
 	scheduledApp *v1beta2.ScheduledSparkApplication,
+	runName string,
 	t time.Time,
 ) (*v1beta2.SparkApplication, error) {
 	labels := map[string]string{
@@ -210,9 +378,10 @@ func (r *Reconciler) createSparkApplication(
 	for key, value := range scheduledApp.Labels {
 		labels[key] = value
 	}
+	labels[common.LabelScheduledTime] = formatScheduleTime(t)
 	app := &v1beta2.SparkApplication{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("%s-%d", scheduledApp.Name, t.UnixNano()),
+			Name:      runName,
 			Namespace: scheduledApp.Namespace,
 			Labels:    labels,
 			OwnerReferences: []metav1.OwnerReference{{
@@ -223,14 +392,106 @@ func (r *Reconciler) createSparkApplication(
 				BlockOwnerDeletion: util.BoolPtr(true),
 			}},
 		},
-		Spec: scheduledApp.Spec.Template,
+		Spec: *scheduledApp.Spec.Template.DeepCopy(),
+	}
+	previousSuccessfulRunTime, err := r.getPreviousSuccessfulRunTime(scheduledApp, t)
+	if err != nil {
+		return nil, err
+	}
+	if err := prepareRunSpec(scheduledApp, app, app.Name, t, previousSuccessfulRunTime); err != nil {
+		return nil, err
+	}
+	app, err = r.createOrGetSparkApplication(app)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.createTaskSparkApplications(scheduledApp, app, t, previousSuccessfulRunTime); err != nil {
+		return nil, err
+	}
+	return app, nil
+}
+
+// prepareRunSpec renders the templates in the spec of the given SparkApplication of a run of the given
+// ScheduledSparkApplication, resolves its dependencies within the run and passes it the scheduled time.
+func prepareRunSpec(
+	scheduledApp *v1beta2.ScheduledSparkApplication,
+	app *v1beta2.SparkApplication,
+	runName string,
+	t time.Time,
+	previousSuccessfulRunTime time.Time,
+) error {
+	location, err := getScheduleLocation(scheduledApp)
+	if err != nil {
+		return err
+	}
+	vars := util.ScheduledRunVariables{
+		ScheduledTime:             t.In(location),
+		PreviousSuccessfulRunTime: previousSuccessfulRunTime,
+		RunName:                   app.Name,
+		Namespace:                 app.Namespace,
 	}
+	if err := util.RenderSparkApplicationTemplate(&app.Spec, vars); err != nil {
+		return err
+	}
+	app.Spec.DependsOn = resolveRunDependencies(scheduledApp, runName, app.Spec.DependsOn)
+	// Pass the scheduled time of the run to the application.
+	if app.Spec.SparkConf == nil {
+		app.Spec.SparkConf = make(map[string]string)
+	}
+	app.Spec.SparkConf[common.SparkScheduledTime] = t.UTC().Format(time.RFC3339)
+	return nil
+}
+
+// createOrGetSparkApplication creates the given SparkApplication, or gets it if it already exists.
+func (r *Reconciler) createOrGetSparkApplication(app *v1beta2.SparkApplication) (*v1beta2.SparkApplication, error) {
 	if err := r.client.Create(context.TODO(), app); err != nil {
+		// The run has already been started, e.g. before the status of the ScheduledSparkApplication failed to update.
+		if errors.IsAlreadyExists(err) {
+			existing := &v1beta2.SparkApplication{}
+			if err := r.client.Get(context.TODO(), client.ObjectKeyFromObject(app), existing); err != nil {
+				return nil, err
+			}
+			return existing, nil
+		}
 		return nil, err
 	}
 	return app, nil
 }
 
+// getPreviousSuccessfulRunTime returns the scheduled time of the most recent successful run of the given
+// ScheduledSparkApplication scheduled before the given time, or the zero time if there is none.
+func (r *Reconciler) getPreviousSuccessfulRunTime(scheduledApp *v1beta2.ScheduledSparkApplication, t time.Time) (time.Time, error) {
+	apps, err := r.listSparkApplications(scheduledApp)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var previous time.Time
+	for _, app := range apps {
+		if app.Status.AppState.State != v1beta2.ApplicationStateCompleted {
+			continue
+		}
+		// Runs started before their scheduled time was recorded are assumed to be scheduled at their creation.
+		scheduleTime := app.CreationTimestamp.Time
+		if value, ok := app.Labels[common.LabelScheduledTime]; ok {
+			if parsed, err := time.Parse(scheduleTimeLayout, value); err == nil {
+				scheduleTime = parsed
+			}
+		}
+		if scheduleTime.Before(t) && scheduleTime.After(previous) {
+			previous = scheduleTime
+		}
+	}
+	if previous.IsZero() {
+		return previous, nil
+	}
+	location, err := getScheduleLocation(scheduledApp)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return previous.In(location), nil
+}
+
 // shouldStartNextRun checks if the next run should be started.
 func (r *Reconciler) shouldStartNextRun(scheduledApp *v1beta2.ScheduledSparkApplication) (bool, error) {
 	apps, err := r.listSparkApplications(scheduledApp)
@@ -248,7 +509,7 @@ func (r *Reconciler) shouldStartNextRun(scheduledApp *v1beta2.ScheduledSparkAppl
 	case v1beta2.ConcurrencyAllow:
 		return true, nil
 	case v1beta2.ConcurrencyForbid:
-		return r.hasLastRunFinished(lastRun), nil
+		return r.hasRunFinished(lastRun)
 	case v1beta2.ConcurrencyReplace:
 		if err := r.killLastRunIfNotFinished(lastRun); err != nil {
 			return false, err
@@ -258,26 +519,25 @@ func (r *Reconciler) shouldStartNextRun(scheduledApp *v1beta2.ScheduledSparkAppl
 	return false, nil
 }
 
-func (r *Reconciler) startNextRun(scheduledApp *v1beta2.ScheduledSparkApplication, now time.Time) (*v1beta2.SparkApplication, error) {
-	app, err := r.createSparkApplication(scheduledApp, now)
+// startNextRun starts the run of the given ScheduledSparkApplication with the given name scheduled at the given time.
+func (r *Reconciler) startNextRun(scheduledApp *v1beta2.ScheduledSparkApplication, runName string, scheduleTime time.Time) (*v1beta2.SparkApplication, error) {
+	app, err := r.createSparkApplication(scheduledApp, runName, scheduleTime)
 	if err != nil {
 		return nil, err
 	}
 	return app, nil
 }
 
-func (r *Reconciler) hasLastRunFinished(app *v1beta2.SparkApplication) bool {
-	return app.Status.AppState.State == v1beta2.ApplicationStateCompleted ||
-		app.Status.AppState.State == v1beta2.ApplicationStateFailed
-}
-
 func (r *Reconciler) killLastRunIfNotFinished(app *v1beta2.SparkApplication) error {
-	finished := r.hasLastRunFinished(app)
+	finished, err := r.hasRunFinished(app)
+	if err != nil {
+		return err
+	}
 	if finished {
 		return nil
 	}
 
-	// Delete the SparkApplication object of the last run.
+	// Delete the SparkApplication object of the last run, whose tasks are garbage collected with it.
 	if err := r.client.Delete(context.TODO(), app, client.GracePeriodSeconds(0)); err != nil {
 		return err
 	}
@@ -337,6 +597,7 @@ func (r *Reconciler) checkAndUpdatePastRuns(ctx context.Context, scheduledApp *v
 
 func (r *Reconciler) updateScheduledSparkApplicationStatus(ctx context.Context, scheduledApp *v1beta2.ScheduledSparkApplication) error {
 	// logger.Info("Updating SchedulingSparkApplication", "name", scheduledApp.Name, "namespace", scheduledApp.Namespace, "status", scheduledApp.Status)
+	setScheduledSparkApplicationConditions(scheduledApp)
 	if err := r.client.Status().Update(ctx, scheduledApp); err != nil {
 		return fmt.Errorf("failed to update ScheduledSparkApplication status: %v", err)
 	}
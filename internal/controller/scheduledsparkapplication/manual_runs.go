@@ -0,0 +1,215 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduledsparkapplication
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+const (
+	// manualRunRetryInterval is the interval at which pending manual runs are retried if the concurrency
+	// policy does not allow them to start.
+	manualRunRetryInterval = 10 * time.Second
+
+	// maxBackfillRuns is the maximum number of runs a single backfill request can create.
+	maxBackfillRuns = 100
+)
+
+// reconcileManualRuns takes the manual runs requested through the annotations of the given
+// ScheduledSparkApplication and starts the pending ones as far as its concurrency policy allows. Unless the
+// policy allows concurrent runs, at most one run is started per reconcile, as the cached runs do not include
+// the run just started yet and replacing a run would kill the one just started.
+func (r *Reconciler) reconcileManualRuns(ctx context.Context, scheduledApp *v1beta2.ScheduledSparkApplication, schedule cron.Schedule) error {
+	requested := r.getRequestedManualRuns(scheduledApp, schedule)
+	if len(requested) > 0 {
+		scheduledApp.Status.PendingManualRuns = mergeManualRuns(scheduledApp.Status.PendingManualRuns, requested)
+		r.recorder.Eventf(
+			scheduledApp,
+			corev1.EventTypeNormal,
+			common.EventScheduledSparkApplicationManualRunsRequested,
+			"%d manual runs of ScheduledSparkApplication %s requested",
+			len(requested),
+			scheduledApp.Name,
+		)
+	}
+
+	started := false
+	for len(scheduledApp.Status.PendingManualRuns) > 0 {
+		ok, err := r.shouldStartNextRun(scheduledApp)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+
+		scheduleTime := scheduledApp.Status.PendingManualRuns[0].Time
+		// Manual runs are named apart from the runs started by the schedule, so that a scheduled time that has
+		// already run is run again. The name is deterministic, so that a run whose start failed to be recorded
+		// is not started twice.
+		runName := util.GetScheduledManualRunName(scheduledApp.Name, scheduleTime, scheduledApp.Status.ManualRunCount+1)
+		app, err := r.startNextRun(scheduledApp, runName, scheduleTime)
+		if err != nil {
+			logger.Error(err, "Failed to start manual run for ScheduledSparkApplication", "name", scheduledApp.Name, "namespace", scheduledApp.Namespace, "scheduleTime", scheduleTime)
+			return err
+		}
+		r.recorder.Eventf(
+			scheduledApp,
+			corev1.EventTypeNormal,
+			common.EventScheduledSparkApplicationManualRunStarted,
+			"Manual run %s of ScheduledSparkApplication %s scheduled at %s started",
+			app.Name,
+			scheduledApp.Name,
+			scheduleTime.UTC().Format(time.RFC3339),
+		)
+		scheduledApp.Status.LastRun = metav1.NewTime(scheduleTime)
+		scheduledApp.Status.LastRunName = app.Name
+		scheduledApp.Status.PendingManualRuns = scheduledApp.Status.PendingManualRuns[1:]
+		scheduledApp.Status.ManualRunCount++
+		started = true
+		if scheduledApp.Spec.ConcurrencyPolicy != v1beta2.ConcurrencyAllow {
+			break
+		}
+	}
+
+	if started {
+		if err := r.checkAndUpdatePastRuns(ctx, scheduledApp); err != nil {
+			return err
+		}
+	}
+	if len(requested) > 0 || started {
+		if err := r.updateScheduledSparkApplicationStatus(ctx, scheduledApp); err != nil {
+			return err
+		}
+	}
+
+	// The requests are removed only once they have been recorded in the status.
+	_, hasTrigger := scheduledApp.Annotations[common.AnnotationTriggerRun]
+	_, hasBackfill := scheduledApp.Annotations[common.AnnotationBackfill]
+	if !hasTrigger && !hasBackfill {
+		return nil
+	}
+	delete(scheduledApp.Annotations, common.AnnotationTriggerRun)
+	delete(scheduledApp.Annotations, common.AnnotationBackfill)
+	if err := r.client.Update(ctx, scheduledApp); err != nil {
+		return fmt.Errorf("failed to remove run requests from ScheduledSparkApplication: %v", err)
+	}
+	return nil
+}
+
+// getRequestedManualRuns returns the scheduled times of the runs requested through the annotations of the given
+// ScheduledSparkApplication. Invalid requests are reported through events and ignored.
+func (r *Reconciler) getRequestedManualRuns(scheduledApp *v1beta2.ScheduledSparkApplication, schedule cron.Schedule) []time.Time {
+	var requested []time.Time
+	if value, ok := scheduledApp.Annotations[common.AnnotationTriggerRun]; ok {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			r.recordInvalidRunRequest(scheduledApp, common.AnnotationTriggerRun, fmt.Errorf("invalid scheduled time %q: %v", value, err))
+		} else {
+			requested = append(requested, t)
+		}
+	}
+	if value, ok := scheduledApp.Annotations[common.AnnotationBackfill]; ok {
+		times, err := getBackfillScheduleTimes(value, schedule)
+		if err != nil {
+			r.recordInvalidRunRequest(scheduledApp, common.AnnotationBackfill, err)
+		} else {
+			requested = append(requested, times...)
+		}
+	}
+	return requested
+}
+
+func (r *Reconciler) recordInvalidRunRequest(scheduledApp *v1beta2.ScheduledSparkApplication, annotation string, err error) {
+	logger.Info("Ignoring invalid run request of ScheduledSparkApplication", "name", scheduledApp.Name, "namespace", scheduledApp.Namespace, "annotation", annotation, "error", err.Error())
+	r.recorder.Eventf(
+		scheduledApp,
+		corev1.EventTypeWarning,
+		common.EventScheduledSparkApplicationInvalidRunRequest,
+		"Ignoring annotation %s of ScheduledSparkApplication %s: %v",
+		annotation,
+		scheduledApp.Name,
+		err,
+	)
+}
+
+// getBackfillScheduleTimes returns the scheduled times of the given schedule within the given interval, which
+// is formatted as "<start>/<end>" in RFC 3339 format and includes both ends.
+func getBackfillScheduleTimes(interval string, schedule cron.Schedule) ([]time.Time, error) {
+	parts := strings.Split(interval, "/")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid interval %q, expected <start>/<end>", interval)
+	}
+	start, err := time.Parse(time.RFC3339, parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid start time %q: %v", parts[0], err)
+	}
+	end, err := time.Parse(time.RFC3339, parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid end time %q: %v", parts[1], err)
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("end time %s is before start time %s", parts[1], parts[0])
+	}
+
+	var times []time.Time
+	// Schedules have a resolution of one second, so this includes the start time if it is a scheduled time.
+	for t := schedule.Next(start.Add(-time.Second)); !t.IsZero() && !t.After(end); t = schedule.Next(t) {
+		if len(times) == maxBackfillRuns {
+			return nil, fmt.Errorf("interval %q contains more than %d scheduled times", interval, maxBackfillRuns)
+		}
+		times = append(times, t)
+	}
+	if len(times) == 0 {
+		return nil, fmt.Errorf("interval %q does not contain any scheduled time", interval)
+	}
+	return times, nil
+}
+
+// mergeManualRuns adds the requested scheduled times to the pending ones, omitting duplicates, and returns them
+// in chronological order.
+func mergeManualRuns(pending []metav1.Time, requested []time.Time) []metav1.Time {
+	merged := append([]metav1.Time{}, pending...)
+	for _, t := range requested {
+		duplicate := false
+		for _, p := range merged {
+			if p.Time.Equal(t) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			merged = append(merged, metav1.NewTime(t))
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Time.Before(merged[j].Time)
+	})
+	return merged
+}
@@ -0,0 +1,242 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduledsparkapplication
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+func TestGetBackfillScheduleTimes(t *testing.T) {
+	schedule, err := parseSchedule(&v1beta2.ScheduledSparkApplication{
+		Spec: v1beta2.ScheduledSparkApplicationSpec{Schedule: "0 2 * * *"},
+	})
+	assert.Nil(t, err)
+
+	times, err := getBackfillScheduleTimes("2024-03-01T02:00:00Z/2024-03-03T23:59:59Z", schedule)
+	assert.Nil(t, err)
+	assert.Equal(t, []time.Time{
+		time.Date(2024, time.March, 1, 2, 0, 0, 0, time.UTC),
+		time.Date(2024, time.March, 2, 2, 0, 0, 0, time.UTC),
+		time.Date(2024, time.March, 3, 2, 0, 0, 0, time.UTC),
+	}, times)
+
+	for _, interval := range []string{
+		"2024-03-01T00:00:00Z",
+		"2024-03-01/2024-03-03",
+		"2024-03-03T00:00:00Z/2024-03-01T00:00:00Z",
+		"2024-03-01T03:00:00Z/2024-03-01T04:00:00Z",
+		"2024-01-01T00:00:00Z/2024-12-31T00:00:00Z",
+	} {
+		_, err := getBackfillScheduleTimes(interval, schedule)
+		assert.NotNil(t, err, interval)
+	}
+}
+
+func TestMergeManualRuns(t *testing.T) {
+	hour := func(h int) time.Time { return time.Date(2024, time.March, 1, h, 0, 0, 0, time.UTC) }
+	merged := mergeManualRuns(
+		[]metav1.Time{metav1.NewTime(hour(2)), metav1.NewTime(hour(4))},
+		[]time.Time{hour(3), hour(1), hour(4)},
+	)
+	var times []time.Time
+	for _, t := range merged {
+		times = append(times, t.Time)
+	}
+	assert.Equal(t, []time.Time{hour(1), hour(2), hour(3), hour(4)}, times)
+}
+
+func TestReconcileManualRuns(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.Nil(t, v1beta2.AddToScheme(scheme))
+	scheduledApp := &v1beta2.ScheduledSparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "spark-pi",
+			Namespace: "default",
+			Annotations: map[string]string{
+				common.AnnotationBackfill: "2024-03-01T00:00:00Z/2024-03-02T23:59:59Z",
+			},
+		},
+		Spec: v1beta2.ScheduledSparkApplicationSpec{
+			Schedule:          "0 2 * * *",
+			TimeZone:          util.StringPtr("UTC"),
+			ConcurrencyPolicy: v1beta2.ConcurrencyForbid,
+			Template: v1beta2.SparkApplicationSpec{
+				Arguments: []string{"${{ .ScheduledTime.Format \"2006-01-02\" }}", "${{ .PreviousSuccessfulRunTime.Format \"2006-01-02\" }}"},
+			},
+		},
+		Status: v1beta2.ScheduledSparkApplicationStatus{ScheduleState: v1beta2.ScheduleStateScheduled},
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(scheduledApp).
+		WithStatusSubresource(scheduledApp).
+		Build()
+	r := NewReconciler(scheme, c, record.NewFakeRecorder(10), clocktesting.NewFakeClock(time.Now()), Options{})
+	schedule, err := parseSchedule(scheduledApp)
+	assert.Nil(t, err)
+
+	// Only the first run is started as the concurrency policy forbids concurrent runs.
+	assert.Nil(t, r.reconcileManualRuns(context.TODO(), scheduledApp, schedule))
+	assert.NotContains(t, scheduledApp.Annotations, common.AnnotationBackfill)
+	assert.Len(t, scheduledApp.Status.PendingManualRuns, 1)
+	assert.True(t, time.Date(2024, time.March, 2, 2, 0, 0, 0, time.UTC).Equal(scheduledApp.Status.PendingManualRuns[0].Time))
+
+	app := &v1beta2.SparkApplication{}
+	assert.Nil(t, c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: scheduledApp.Status.LastRunName}, app))
+	assert.Equal(t, "20240301T020000Z", app.Labels[common.LabelScheduledTime])
+	assert.Equal(t, "2024-03-01T02:00:00Z", app.Spec.SparkConf[common.SparkScheduledTime])
+	assert.Equal(t, []string{"2024-03-01", "0001-01-01"}, app.Spec.Arguments)
+
+	// The next run is started once the previous one has completed.
+	app.Status.AppState.State = v1beta2.ApplicationStateCompleted
+	assert.Nil(t, c.Update(context.TODO(), app))
+	assert.Nil(t, r.reconcileManualRuns(context.TODO(), scheduledApp, schedule))
+	assert.Empty(t, scheduledApp.Status.PendingManualRuns)
+	assert.NotEqual(t, app.Name, scheduledApp.Status.LastRunName)
+	assert.Nil(t, c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: scheduledApp.Status.LastRunName}, app))
+	assert.Equal(t, []string{"2024-03-02", "2024-03-01"}, app.Spec.Arguments)
+}
+
+func TestReconcileManualRunsStartsOneRunPerReconcile(t *testing.T) {
+	testCases := []struct {
+		policy        v1beta2.ConcurrencyPolicy
+		expectStarted int
+	}{
+		{policy: v1beta2.ConcurrencyAllow, expectStarted: 3},
+		{policy: v1beta2.ConcurrencyForbid, expectStarted: 1},
+		{policy: v1beta2.ConcurrencyReplace, expectStarted: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.policy), func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			assert.Nil(t, v1beta2.AddToScheme(scheme))
+			scheduledApp := &v1beta2.ScheduledSparkApplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "spark-pi",
+					Namespace: "default",
+					Annotations: map[string]string{
+						common.AnnotationBackfill: "2024-03-01T00:00:00Z/2024-03-03T23:59:59Z",
+					},
+				},
+				Spec: v1beta2.ScheduledSparkApplicationSpec{
+					Schedule:          "0 2 * * *",
+					TimeZone:          util.StringPtr("UTC"),
+					ConcurrencyPolicy: tc.policy,
+				},
+				Status: v1beta2.ScheduledSparkApplicationStatus{ScheduleState: v1beta2.ScheduleStateScheduled},
+			}
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(scheduledApp).
+				WithStatusSubresource(scheduledApp).
+				Build()
+			r := NewReconciler(scheme, c, record.NewFakeRecorder(10), clocktesting.NewFakeClock(time.Now()), Options{})
+			schedule, err := parseSchedule(scheduledApp)
+			assert.Nil(t, err)
+
+			assert.Nil(t, r.reconcileManualRuns(context.TODO(), scheduledApp, schedule))
+			assert.Len(t, scheduledApp.Status.PendingManualRuns, 3-tc.expectStarted)
+
+			apps := &v1beta2.SparkApplicationList{}
+			assert.Nil(t, c.List(context.TODO(), apps, client.InNamespace("default")))
+			assert.Len(t, apps.Items, tc.expectStarted)
+			for _, app := range apps.Items {
+				assert.NotEqual(t, v1beta2.ApplicationStateFailed, app.Status.AppState.State)
+			}
+		})
+	}
+}
+
+func TestReconcileManualRunsOverlappingExistingRuns(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.Nil(t, v1beta2.AddToScheme(scheme))
+	scheduleTime := time.Date(2024, time.March, 1, 2, 0, 0, 0, time.UTC)
+	scheduledApp := &v1beta2.ScheduledSparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "spark-pi",
+			Namespace: "default",
+			Annotations: map[string]string{
+				common.AnnotationBackfill: "2024-03-01T00:00:00Z/2024-03-02T23:59:59Z",
+			},
+		},
+		Spec: v1beta2.ScheduledSparkApplicationSpec{
+			Schedule:          "0 2 * * *",
+			TimeZone:          util.StringPtr("UTC"),
+			ConcurrencyPolicy: v1beta2.ConcurrencyAllow,
+		},
+		Status: v1beta2.ScheduledSparkApplicationStatus{ScheduleState: v1beta2.ScheduleStateScheduled},
+	}
+	// The night of 1 March has already run.
+	existing := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      util.GetScheduledRunName(scheduledApp.Name, scheduleTime),
+			Namespace: "default",
+			Labels: map[string]string{
+				common.LabelScheduledSparkAppName: scheduledApp.Name,
+				common.LabelScheduledTime:         formatScheduleTime(scheduleTime),
+			},
+		},
+		Status: v1beta2.SparkApplicationStatus{AppState: v1beta2.ApplicationState{State: v1beta2.ApplicationStateFailed}},
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(scheduledApp, existing).
+		WithStatusSubresource(scheduledApp, existing).
+		Build()
+	r := NewReconciler(scheme, c, record.NewFakeRecorder(10), clocktesting.NewFakeClock(time.Now()), Options{})
+	schedule, err := parseSchedule(scheduledApp)
+	assert.Nil(t, err)
+
+	assert.Nil(t, r.reconcileManualRuns(context.TODO(), scheduledApp, schedule))
+	assert.Empty(t, scheduledApp.Status.PendingManualRuns)
+	assert.Equal(t, int32(2), scheduledApp.Status.ManualRunCount)
+
+	apps := &v1beta2.SparkApplicationList{}
+	assert.Nil(t, c.List(context.TODO(), apps, client.InNamespace("default")))
+	var names []string
+	for _, app := range apps.Items {
+		names = append(names, app.Name)
+	}
+	assert.ElementsMatch(t, []string{
+		existing.Name,
+		util.GetScheduledManualRunName(scheduledApp.Name, scheduleTime, 1),
+		util.GetScheduledManualRunName(scheduledApp.Name, scheduleTime.AddDate(0, 0, 1), 2),
+	}, names)
+	assert.Equal(t, util.GetScheduledManualRunName(scheduledApp.Name, scheduleTime.AddDate(0, 0, 1), 2), scheduledApp.Status.LastRunName)
+
+	// A run is re-run by another manual run.
+	scheduledApp.Annotations = map[string]string{common.AnnotationTriggerRun: "2024-03-01T02:00:00Z"}
+	assert.Nil(t, r.reconcileManualRuns(context.TODO(), scheduledApp, schedule))
+	assert.Equal(t, util.GetScheduledManualRunName(scheduledApp.Name, scheduleTime, 3), scheduledApp.Status.LastRunName)
+	app := &v1beta2.SparkApplication{}
+	assert.Nil(t, c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: scheduledApp.Status.LastRunName}, app))
+}
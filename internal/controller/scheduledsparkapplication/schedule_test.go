@@ -0,0 +1,266 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduledsparkapplication
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+func TestParseScheduleWithTimeZone(t *testing.T) {
+	scheduledApp := &v1beta2.ScheduledSparkApplication{
+		Spec: v1beta2.ScheduledSparkApplicationSpec{
+			Schedule: "0 2 * * *",
+			TimeZone: util.StringPtr("Europe/Berlin"),
+		},
+	}
+	schedule, err := parseSchedule(scheduledApp)
+	assert.Nil(t, err)
+
+	// 02:00 in Berlin is 01:00 UTC in winter and 00:00 UTC in summer.
+	winter := time.Date(2024, time.January, 10, 12, 0, 0, 0, time.UTC)
+	assert.True(t, time.Date(2024, time.January, 11, 1, 0, 0, 0, time.UTC).Equal(schedule.Next(winter)))
+	summer := time.Date(2024, time.July, 10, 12, 0, 0, 0, time.UTC)
+	assert.True(t, time.Date(2024, time.July, 11, 0, 0, 0, 0, time.UTC).Equal(schedule.Next(summer)))
+}
+
+func TestParseScheduleWithoutTimeZone(t *testing.T) {
+	scheduledApp := &v1beta2.ScheduledSparkApplication{
+		Spec: v1beta2.ScheduledSparkApplicationSpec{
+			Schedule: "@every 1h",
+		},
+	}
+	schedule, err := parseSchedule(scheduledApp)
+	assert.Nil(t, err)
+	now := time.Date(2024, time.January, 10, 12, 0, 0, 0, time.UTC)
+	assert.True(t, now.Add(time.Hour).Equal(schedule.Next(now)))
+}
+
+func TestParseScheduleInvalidTimeZone(t *testing.T) {
+	scheduledApp := &v1beta2.ScheduledSparkApplication{
+		Spec: v1beta2.ScheduledSparkApplicationSpec{
+			Schedule: "0 2 * * *",
+			TimeZone: util.StringPtr("Mars/Olympus_Mons"),
+		},
+	}
+	_, err := parseSchedule(scheduledApp)
+	assert.NotNil(t, err)
+	_, err = getScheduleLocation(scheduledApp)
+	assert.NotNil(t, err)
+}
+
+func TestGetDueScheduleTimes(t *testing.T) {
+	schedule, err := parseSchedule(&v1beta2.ScheduledSparkApplication{
+		Spec: v1beta2.ScheduledSparkApplicationSpec{Schedule: "0 * * * *"},
+	})
+	assert.Nil(t, err)
+
+	// Runs at 08:00, 09:00, 10:00 and 11:00 have been missed.
+	nextRun := time.Date(2024, time.January, 10, 8, 0, 0, 0, time.UTC)
+	now := time.Date(2024, time.January, 10, 11, 30, 0, 0, time.UTC)
+	hour := func(h int) time.Time { return time.Date(2024, time.January, 10, h, 0, 0, 0, time.UTC) }
+
+	testCases := []struct {
+		name              string
+		spec              v1beta2.ScheduledSparkApplicationSpec
+		expectedTimes     []time.Time
+		expectedSkipCount int
+	}{
+		{
+			name:              "latest only by default",
+			expectedTimes:     []time.Time{hour(11)},
+			expectedSkipCount: 3,
+		},
+		{
+			name:              "none",
+			spec:              v1beta2.ScheduledSparkApplicationSpec{CatchUpPolicy: v1beta2.CatchUpNone},
+			expectedSkipCount: 4,
+		},
+		{
+			name:              "all",
+			spec:              v1beta2.ScheduledSparkApplicationSpec{CatchUpPolicy: v1beta2.CatchUpAll},
+			expectedTimes:     []time.Time{hour(8), hour(9), hour(10), hour(11)},
+			expectedSkipCount: 0,
+		},
+		{
+			name: "all with max catch-up runs",
+			spec: v1beta2.ScheduledSparkApplicationSpec{
+				CatchUpPolicy:  v1beta2.CatchUpAll,
+				MaxCatchUpRuns: util.Int32Ptr(2),
+			},
+			expectedTimes:     []time.Time{hour(10), hour(11)},
+			expectedSkipCount: 2,
+		},
+		{
+			name: "all with starting deadline",
+			spec: v1beta2.ScheduledSparkApplicationSpec{
+				CatchUpPolicy:           v1beta2.CatchUpAll,
+				StartingDeadlineSeconds: util.Int64Ptr(7200),
+			},
+			expectedTimes:     []time.Time{hour(10), hour(11)},
+			expectedSkipCount: 2,
+		},
+		{
+			name: "latest only past starting deadline",
+			spec: v1beta2.ScheduledSparkApplicationSpec{
+				StartingDeadlineSeconds: util.Int64Ptr(600),
+			},
+			expectedSkipCount: 4,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scheduledApp := &v1beta2.ScheduledSparkApplication{Spec: tc.spec}
+			scheduledApp.Status.NextRun.Time = nextRun
+			times, skipped := getDueScheduleTimes(scheduledApp, schedule, now)
+			assert.Equal(t, tc.expectedTimes, times)
+			assert.Equal(t, tc.expectedSkipCount, skipped)
+		})
+	}
+}
+
+func TestGetDueScheduleTimesSingleRun(t *testing.T) {
+	schedule, err := parseSchedule(&v1beta2.ScheduledSparkApplication{
+		Spec: v1beta2.ScheduledSparkApplicationSpec{
+			Schedule:      "0 * * * *",
+			CatchUpPolicy: v1beta2.CatchUpNone,
+		},
+	})
+	assert.Nil(t, err)
+
+	scheduledApp := &v1beta2.ScheduledSparkApplication{
+		Spec: v1beta2.ScheduledSparkApplicationSpec{CatchUpPolicy: v1beta2.CatchUpNone},
+	}
+	scheduledApp.Status.NextRun.Time = time.Date(2024, time.January, 10, 8, 0, 0, 0, time.UTC)
+	times, skipped := getDueScheduleTimes(scheduledApp, schedule, time.Date(2024, time.January, 10, 8, 0, 5, 0, time.UTC))
+	assert.Equal(t, []time.Time{scheduledApp.Status.NextRun.Time}, times)
+	assert.Equal(t, 0, skipped)
+}
+
+func TestReconcileBlockedCatchUpRuns(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.Nil(t, v1beta2.AddToScheme(scheme))
+	now := time.Date(2024, time.January, 10, 8, 30, 0, 0, time.UTC)
+	scheduledApp := &v1beta2.ScheduledSparkApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "spark-pi", Namespace: "default"},
+		Spec: v1beta2.ScheduledSparkApplicationSpec{
+			Schedule:          "0 * * * *",
+			TimeZone:          util.StringPtr("UTC"),
+			ConcurrencyPolicy: v1beta2.ConcurrencyForbid,
+			CatchUpPolicy:     v1beta2.CatchUpAll,
+		},
+		Status: v1beta2.ScheduledSparkApplicationStatus{
+			ScheduleState: v1beta2.ScheduleStateScheduled,
+			NextRun:       metav1.NewTime(time.Date(2024, time.January, 10, 6, 0, 0, 0, time.UTC)),
+		},
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(scheduledApp).
+		WithStatusSubresource(scheduledApp).
+		Build()
+	r := NewReconciler(scheme, c, record.NewFakeRecorder(10), clocktesting.NewFakeClock(now), Options{})
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "spark-pi", Namespace: "default"}}
+
+	// The first missed run is started.
+	result, err := r.Reconcile(context.TODO(), req)
+	assert.Nil(t, err)
+	assert.True(t, result.Requeue)
+
+	// The next missed run is blocked by the first one, which is retried shortly rather than at the next
+	// scheduled time.
+	result, err = r.Reconcile(context.TODO(), req)
+	assert.Nil(t, err)
+	assert.Equal(t, catchUpRetryInterval, result.RequeueAfter)
+}
+
+func TestReconcileScheduleChanged(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.Nil(t, v1beta2.AddToScheme(scheme))
+	newScheduledApp := func(nextRun time.Time) *v1beta2.ScheduledSparkApplication {
+		return &v1beta2.ScheduledSparkApplication{
+			ObjectMeta: metav1.ObjectMeta{Name: "spark-pi", Namespace: "default", Generation: 2},
+			Spec: v1beta2.ScheduledSparkApplicationSpec{
+				// The schedule was changed from "0 * * * *".
+				Schedule:          "30 * * * *",
+				TimeZone:          util.StringPtr("UTC"),
+				ConcurrencyPolicy: v1beta2.ConcurrencyAllow,
+			},
+			Status: v1beta2.ScheduledSparkApplicationStatus{
+				ScheduleState:      v1beta2.ScheduleStateScheduled,
+				NextRun:            metav1.NewTime(nextRun),
+				ObservedGeneration: 1,
+			},
+		}
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "spark-pi", Namespace: "default"}}
+
+	testCases := []struct {
+		name            string
+		nextRun         time.Time
+		now             time.Time
+		expectedLastRun time.Time
+		expectedNextRun time.Time
+	}{
+		{
+			name:            "next run is computed from the new schedule",
+			nextRun:         time.Date(2024, time.January, 10, 9, 0, 0, 0, time.UTC),
+			now:             time.Date(2024, time.January, 10, 8, 10, 0, 0, time.UTC),
+			expectedNextRun: time.Date(2024, time.January, 10, 8, 30, 0, 0, time.UTC),
+		},
+		{
+			name:            "due run is started before the new schedule takes effect",
+			nextRun:         time.Date(2024, time.January, 10, 8, 0, 0, 0, time.UTC),
+			now:             time.Date(2024, time.January, 10, 8, 0, 5, 0, time.UTC),
+			expectedLastRun: time.Date(2024, time.January, 10, 8, 0, 0, 0, time.UTC),
+			expectedNextRun: time.Date(2024, time.January, 10, 8, 30, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scheduledApp := newScheduledApp(tc.nextRun)
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(scheduledApp).
+				WithStatusSubresource(scheduledApp).
+				Build()
+			r := NewReconciler(scheme, c, record.NewFakeRecorder(10), clocktesting.NewFakeClock(tc.now), Options{})
+
+			_, err := r.Reconcile(context.TODO(), req)
+			assert.Nil(t, err)
+			assert.Nil(t, c.Get(context.TODO(), req.NamespacedName, scheduledApp))
+			assert.True(t, tc.expectedLastRun.Equal(scheduledApp.Status.LastRun.Time), "expected last run %s, got %s", tc.expectedLastRun, scheduledApp.Status.LastRun.Time)
+			assert.True(t, tc.expectedNextRun.Equal(scheduledApp.Status.NextRun.Time), "expected next run %s, got %s", tc.expectedNextRun, scheduledApp.Status.NextRun.Time)
+			assert.Equal(t, int64(2), scheduledApp.Status.ObservedGeneration)
+		})
+	}
+}
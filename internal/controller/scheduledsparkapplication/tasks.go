@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduledsparkapplication
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// createTaskSparkApplications creates the SparkApplications of the tasks of the given run of the given
+// ScheduledSparkApplication. They are owned by the SparkApplication of the run so that they are deleted with it.
+func (r *Reconciler) createTaskSparkApplications(
+	scheduledApp *v1beta2.ScheduledSparkApplication,
+	run *v1beta2.SparkApplication,
+	t time.Time,
+	previousSuccessfulRunTime time.Time,
+) error {
+	for _, task := range scheduledApp.Spec.Tasks {
+		labels := map[string]string{}
+		for key, value := range scheduledApp.Labels {
+			labels[key] = value
+		}
+		labels[common.LabelScheduledRunName] = run.Name
+		labels[common.LabelScheduledTime] = formatScheduleTime(t)
+		app := &v1beta2.SparkApplication{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      util.GetScheduledTaskName(run.Name, task.Name),
+				Namespace: run.Namespace,
+				Labels:    labels,
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion:         v1beta2.SchemeGroupVersion.String(),
+					Kind:               reflect.TypeOf(v1beta2.SparkApplication{}).Name(),
+					Name:               run.Name,
+					UID:                run.UID,
+					BlockOwnerDeletion: util.BoolPtr(true),
+				}},
+			},
+			Spec: *task.Template.DeepCopy(),
+		}
+		if err := prepareRunSpec(scheduledApp, app, run.Name, t, previousSuccessfulRunTime); err != nil {
+			return fmt.Errorf("failed to prepare task %s: %v", task.Name, err)
+		}
+		if _, err := r.createOrGetSparkApplication(app); err != nil {
+			return fmt.Errorf("failed to create task %s: %v", task.Name, err)
+		}
+	}
+	return nil
+}
+
+// resolveRunDependencies returns the names of the SparkApplications of the given dependencies within the given
+// run. Dependencies on the ScheduledSparkApplication refer to the SparkApplication created from its template,
+// and dependencies on its tasks to their SparkApplications. Other dependencies are kept as they are.
+func resolveRunDependencies(scheduledApp *v1beta2.ScheduledSparkApplication, runName string, dependsOn []string) []string {
+	if len(dependsOn) == 0 {
+		return dependsOn
+	}
+
+	tasks := make(map[string]bool)
+	for _, task := range scheduledApp.Spec.Tasks {
+		tasks[task.Name] = true
+	}
+	resolved := make([]string, 0, len(dependsOn))
+	for _, name := range dependsOn {
+		switch {
+		case name == scheduledApp.Name:
+			resolved = append(resolved, runName)
+		case tasks[name]:
+			resolved = append(resolved, util.GetScheduledTaskName(runName, name))
+		default:
+			resolved = append(resolved, name)
+		}
+	}
+	return resolved
+}
+
+// hasRunFinished tells whether the given run and all of its tasks have terminated.
+func (r *Reconciler) hasRunFinished(run *v1beta2.SparkApplication) (bool, error) {
+	if !util.IsTerminated(run) {
+		return false, nil
+	}
+
+	tasks := &v1beta2.SparkApplicationList{}
+	if err := r.client.List(
+		context.TODO(),
+		tasks,
+		client.InNamespace(run.Namespace),
+		client.MatchingLabels{common.LabelScheduledRunName: run.Name},
+	); err != nil {
+		return false, fmt.Errorf("failed to list tasks of run %s: %v", run.Name, err)
+	}
+	for i := range tasks.Items {
+		if !util.IsTerminated(&tasks.Items[i]) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduledsparkapplication
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+func TestCreateSparkApplicationWithTasks(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.Nil(t, v1beta2.AddToScheme(scheme))
+	scheduledApp := &v1beta2.ScheduledSparkApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "etl", Namespace: "default"},
+		Spec: v1beta2.ScheduledSparkApplicationSpec{
+			Schedule: "0 2 * * *",
+			Tasks: []v1beta2.ScheduledSparkApplicationTask{
+				{Name: "transform", Template: v1beta2.SparkApplicationSpec{DependsOn: []string{"etl"}}},
+				{Name: "report", Template: v1beta2.SparkApplicationSpec{DependsOn: []string{"transform", "reference-data"}}},
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(scheduledApp).Build()
+	r := NewReconciler(scheme, c, record.NewFakeRecorder(10), clocktesting.NewFakeClock(time.Now()), Options{})
+
+	scheduleTime := time.Date(2024, time.March, 1, 2, 0, 0, 0, time.UTC)
+	run, err := r.createSparkApplication(scheduledApp, util.GetScheduledRunName(scheduledApp.Name, scheduleTime), scheduleTime)
+	assert.Nil(t, err)
+	assert.Equal(t, "etl-1709258400000000000", run.Name)
+
+	report := &v1beta2.SparkApplication{}
+	assert.Nil(t, c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "etl-1709258400000000000-report"}, report))
+	assert.Equal(t, []string{"etl-1709258400000000000-transform", "reference-data"}, report.Spec.DependsOn)
+	assert.Equal(t, run.Name, report.Labels[common.LabelScheduledRunName])
+	assert.NotContains(t, report.Labels, common.LabelScheduledSparkAppName)
+	assert.Equal(t, run.Name, report.OwnerReferences[0].Name)
+
+	transform := &v1beta2.SparkApplication{}
+	assert.Nil(t, c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "etl-1709258400000000000-transform"}, transform))
+	assert.Equal(t, []string{run.Name}, transform.Spec.DependsOn)
+
+	// The run only finishes once all of its tasks have terminated.
+	run.Status.AppState.State = v1beta2.ApplicationStateCompleted
+	finished, err := r.hasRunFinished(run)
+	assert.Nil(t, err)
+	assert.False(t, finished)
+
+	for _, task := range []*v1beta2.SparkApplication{report, transform} {
+		task.Status.AppState.State = v1beta2.ApplicationStateSkipped
+		assert.Nil(t, c.Update(context.TODO(), task))
+	}
+	finished, err = r.hasRunFinished(run)
+	assert.Nil(t, err)
+	assert.True(t, finished)
+}
@@ -0,0 +1,535 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/internal/scheduler"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/resourceusage"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// admissionRetryInterval is the interval at which queued SparkApplications are checked for admission.
+const admissionRetryInterval = 10 * time.Second
+
+// requestSparkApplicationAdmission is the stage of starting a SparkApplication once its upstreams have
+// completed. It moves the application to the Queued state to wait for its admission if the admission queue is
+// enabled or it has a batch scheduler to be admitted by, or enqueues its submission otherwise.
+func (r *Reconciler) requestSparkApplicationAdmission(ctx context.Context, app *v1beta2.SparkApplication) {
+	if needScheduling, _ := r.shouldDoBatchScheduling(app); !r.options.EnableAdmissionQueue && !needScheduling {
+		r.enqueueSparkApplicationSubmission(ctx, app)
+		return
+	}
+
+	app.Status.AppState = v1beta2.ApplicationState{
+		State: v1beta2.ApplicationStateQueued,
+	}
+	r.recordSparkApplicationEvent(app)
+}
+
+// admitSparkApplicationByBatchScheduler requests the admission of the given queued SparkApplication from its
+// batch scheduler, if any, and records the admission status of the application. An application rejected by its
+// batch scheduler fails its submission, so that it is retried according to its restart policy. Its admission is
+// withdrawn from the batch scheduler, so that a retry requests a new one instead of being rejected again.
+func (r *Reconciler) admitSparkApplicationByBatchScheduler(app *v1beta2.SparkApplication) (bool, string, error) {
+	needScheduling, batchScheduler := r.shouldDoBatchScheduling(app)
+	if !needScheduling {
+		return true, "", nil
+	}
+
+	phase, reason, err := batchScheduler.Admit(app)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to request admission from batch scheduler %s: %v", batchScheduler.Name(), err)
+	}
+	r.updateBatchSchedulerStatus(app, batchScheduler.Name(), phase, reason)
+
+	if phase == v1beta2.BatchSchedulerAdmissionRejected {
+		if err := batchScheduler.Cleanup(app); err != nil {
+			return false, "", fmt.Errorf("failed to clean up batch scheduler resources: %v", err)
+		}
+		app.Status.AppState = v1beta2.ApplicationState{
+			State:        v1beta2.ApplicationStateFailedSubmission,
+			ErrorMessage: fmt.Sprintf("rejected by batch scheduler %s: %s", batchScheduler.Name(), reason),
+		}
+		app.Status.FailureReason = v1beta2.FailureReasonSubmissionError
+		app.Status.SubmissionAttempts++
+		app.Status.LastSubmissionAttemptTime = metav1.Now()
+		r.recordSparkApplicationEvent(app)
+		return false, reason, nil
+	}
+	return phase == v1beta2.BatchSchedulerAdmissionAdmitted, reason, nil
+}
+
+// updateBatchSchedulerStatus records the admission status of the given SparkApplication by its batch scheduler,
+// and records an event whenever the phase of the admission changes.
+func (r *Reconciler) updateBatchSchedulerStatus(app *v1beta2.SparkApplication, name string, phase v1beta2.BatchSchedulerAdmissionPhase, reason string) {
+	if status := app.Status.BatchSchedulerStatus; status != nil && status.Name == name && status.Phase == phase {
+		status.Reason = reason
+		return
+	}
+	app.Status.BatchSchedulerStatus = &v1beta2.BatchSchedulerStatus{
+		Name:               name,
+		Phase:              phase,
+		Reason:             reason,
+		LastTransitionTime: metav1.Now(),
+	}
+
+	switch phase {
+	case v1beta2.BatchSchedulerAdmissionPending:
+		r.recorder.Eventf(
+			app,
+			corev1.EventTypeNormal,
+			common.EventSparkApplicationBatchSchedulerPending,
+			"SparkApplication %s is pending admission by batch scheduler %s: %s",
+			app.Name,
+			name,
+			reason,
+		)
+	case v1beta2.BatchSchedulerAdmissionAdmitted:
+		r.recorder.Eventf(
+			app,
+			corev1.EventTypeNormal,
+			common.EventSparkApplicationBatchSchedulerAdmitted,
+			"SparkApplication %s was admitted by batch scheduler %s",
+			app.Name,
+			name,
+		)
+	case v1beta2.BatchSchedulerAdmissionRejected:
+		r.recorder.Eventf(
+			app,
+			corev1.EventTypeWarning,
+			common.EventSparkApplicationBatchSchedulerRejected,
+			"SparkApplication %s was rejected by batch scheduler %s: %s",
+			app.Name,
+			name,
+			reason,
+		)
+	}
+}
+
+// checkSparkApplicationEviction checks whether the given submitted SparkApplication has been evicted by its
+// batch scheduler, e.g. to make room for one with a higher priority. An evicted application is torn down and
+// moved back to the Queued state to wait for its admission again.
+func (r *Reconciler) checkSparkApplicationEviction(ctx context.Context, app *v1beta2.SparkApplication) (bool, error) {
+	needScheduling, batchScheduler := r.shouldDoBatchScheduling(app)
+	if !needScheduling {
+		return false, nil
+	}
+	evictor, ok := batchScheduler.(scheduler.Evictor)
+	if !ok {
+		return false, nil
+	}
+	evicted, message, err := evictor.Evicted(app)
+	if err != nil {
+		return false, fmt.Errorf("failed to check eviction by batch scheduler: %v", err)
+	}
+	if !evicted {
+		return false, nil
+	}
+
+	logger.Info("SparkApplication was evicted by batch scheduler", "name", app.Name, "namespace", app.Namespace, "message", message)
+	if err := r.deleteSparkResources(ctx, app); err != nil {
+		return false, fmt.Errorf("failed to delete resources associated with SparkApplication: %v", err)
+	}
+	// The admission of the application is withdrawn so that it is requested again.
+	if err := batchScheduler.Cleanup(app); err != nil {
+		return false, fmt.Errorf("failed to clean up batch scheduler resources: %v", err)
+	}
+	r.recorder.Eventf(
+		app,
+		corev1.EventTypeWarning,
+		common.EventSparkApplicationEvicted,
+		"SparkApplication %s was evicted by batch scheduler and queued again: %s",
+		app.Name,
+		message,
+	)
+	app.Status.AppState.State = v1beta2.ApplicationStateQueued
+	r.resetSparkApplicationStatus(app)
+	app.Status.AppState.Reason = v1beta2.ApplicationStateReasonEvicted
+	app.Status.AppState.ErrorMessage = message
+	return true, nil
+}
+
+// admitSparkApplication checks whether the given queued SparkApplication can be submitted. Queued applications
+// of a namespace are admitted one at a time in priority and FIFO order, and only if the application fits both
+// the ResourceQuotas and the budget of the namespace. If the application is not admitted, the returned message
+// tells what it is waiting for.
+func (r *Reconciler) admitSparkApplication(ctx context.Context, app *v1beta2.SparkApplication) (bool, string, error) {
+	apps := &v1beta2.SparkApplicationList{}
+	if err := r.client.List(ctx, apps, client.InNamespace(app.Namespace)); err != nil {
+		return false, "", fmt.Errorf("failed to list SparkApplications: %v", err)
+	}
+
+	head, err := r.getAdmissionQueueHead(ctx, apps.Items)
+	if err != nil {
+		return false, "", err
+	}
+	if head != nil && head.Name != app.Name {
+		return false, fmt.Sprintf("waiting for SparkApplication %s to be admitted first", head.Name), nil
+	}
+
+	usage, err := resourceusage.Calculate(app)
+	if err != nil {
+		// The resource requests of the application are invalid, which fails its submission anyway.
+		logger.Error(err, "Failed to calculate resource requests of SparkApplication, admitting it", "name", app.Name, "namespace", app.Namespace)
+		return true, "", nil
+	}
+	requests := usage.ResourceQuotaList()
+
+	if len(r.options.NamespaceBudget) > 0 {
+		var consumers []budgetConsumer
+		for i := range apps.Items {
+			other := &apps.Items[i]
+			if other.Name == app.Name {
+				continue
+			}
+			if !consumesNamespaceBudget(other) {
+				terminating, err := r.isPreemptedDriverTerminating(ctx, other)
+				if err != nil {
+					return false, "", err
+				}
+				if !terminating {
+					continue
+				}
+			}
+			otherUsage, err := resourceusage.Calculate(other)
+			if err != nil {
+				logger.Error(err, "Failed to calculate resource requests of SparkApplication", "name", other.Name, "namespace", other.Namespace)
+				continue
+			}
+			consumers = append(consumers, budgetConsumer{app: other, requests: otherUsage.ResourceQuotaList()})
+		}
+		if name, exceeded := r.exceedsNamespaceBudget(consumers, requests); exceeded {
+			budget := r.options.NamespaceBudget[name]
+			message := fmt.Sprintf("waiting for %s within the namespace budget of %s", name, budget.String())
+			if r.options.EnablePreemption {
+				preempting, err := r.preemptForSparkApplication(ctx, app, consumers, requests)
+				if err != nil {
+					return false, "", err
+				}
+				if len(preempting) > 0 {
+					message = fmt.Sprintf("waiting for preempted SparkApplications %s to terminate", strings.Join(preempting, ", "))
+				}
+			}
+			return false, message, nil
+		}
+	}
+
+	resourceQuotas := &corev1.ResourceQuotaList{}
+	if err := r.client.List(ctx, resourceQuotas, client.InNamespace(app.Namespace)); err != nil {
+		return false, "", fmt.Errorf("failed to list resource quotas: %v", err)
+	}
+	for _, resourceQuota := range resourceQuotas.Items {
+		// Scope selectors are not supported, ignore any ResourceQuota that does not match everything.
+		if resourceQuota.Spec.ScopeSelector != nil || len(resourceQuota.Spec.Scopes) > 0 {
+			continue
+		}
+		if !util.ValidateResourceQuota(requests, resourceQuota) {
+			return false, fmt.Sprintf("waiting for capacity in resource quota \"%s/%s\"", resourceQuota.Namespace, resourceQuota.Name), nil
+		}
+	}
+
+	return true, "", nil
+}
+
+// getAdmissionQueueHead returns the queued SparkApplication to be admitted next among the given applications,
+// i.e. the one with the highest priority that has been created first.
+func (r *Reconciler) getAdmissionQueueHead(ctx context.Context, apps []v1beta2.SparkApplication) (*v1beta2.SparkApplication, error) {
+	var queued []*v1beta2.SparkApplication
+	priorities := make(map[types.UID]int32)
+	for i := range apps {
+		app := &apps[i]
+		if app.Status.AppState.State != v1beta2.ApplicationStateQueued || !app.DeletionTimestamp.IsZero() {
+			continue
+		}
+		priority, err := r.getSparkApplicationPriority(ctx, app)
+		if err != nil {
+			return nil, err
+		}
+		queued = append(queued, app)
+		priorities[app.UID] = priority
+	}
+	if len(queued) == 0 {
+		return nil, nil
+	}
+
+	sort.SliceStable(queued, func(i, j int) bool {
+		if priorities[queued[i].UID] != priorities[queued[j].UID] {
+			return priorities[queued[i].UID] > priorities[queued[j].UID]
+		}
+		if !queued[i].CreationTimestamp.Equal(&queued[j].CreationTimestamp) {
+			return queued[i].CreationTimestamp.Before(&queued[j].CreationTimestamp)
+		}
+		return queued[i].Name < queued[j].Name
+	})
+	return queued[0], nil
+}
+
+// getSparkApplicationPriority returns the priority of the given SparkApplication, which is its own priority if
+// set, or else the value of the PriorityClass of its driver, or of its batch scheduler options if the driver
+// does not have one.
+func (r *Reconciler) getSparkApplicationPriority(ctx context.Context, app *v1beta2.SparkApplication) (int32, error) {
+	if app.Spec.Priority != nil {
+		return *app.Spec.Priority, nil
+	}
+
+	var priorityClassName string
+	if app.Spec.Driver.PriorityClassName != nil {
+		priorityClassName = *app.Spec.Driver.PriorityClassName
+	} else if app.Spec.BatchSchedulerOptions != nil && app.Spec.BatchSchedulerOptions.PriorityClassName != nil {
+		priorityClassName = *app.Spec.BatchSchedulerOptions.PriorityClassName
+	}
+	if priorityClassName == "" {
+		return 0, nil
+	}
+
+	priorityClass := &schedulingv1.PriorityClass{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: priorityClassName}, priorityClass); err != nil {
+		if errors.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to get PriorityClass %s: %v", priorityClassName, err)
+	}
+	return priorityClass.Value, nil
+}
+
+// consumesNamespaceBudget tells whether the given SparkApplication has been admitted and not yet terminated,
+// so that its resources count against the budget of its namespace.
+func consumesNamespaceBudget(app *v1beta2.SparkApplication) bool {
+	switch app.Status.AppState.State {
+	case v1beta2.ApplicationStateNew,
+		v1beta2.ApplicationStateWaiting,
+		v1beta2.ApplicationStateQueued,
+		v1beta2.ApplicationStateFailedSubmission,
+		v1beta2.ApplicationStateCompleted,
+		v1beta2.ApplicationStateFailed,
+		v1beta2.ApplicationStateSkipped,
+		v1beta2.ApplicationStateSuspended:
+		return false
+	}
+	return true
+}
+
+// isPreemptedDriverTerminating tells whether the given SparkApplication has been preempted and its driver pod,
+// which is given a grace period to terminate, still exists. The resources of such an application keep counting
+// against the budget of its namespace even once it has failed, until they are actually released.
+func (r *Reconciler) isPreemptedDriverTerminating(ctx context.Context, app *v1beta2.SparkApplication) (bool, error) {
+	if app.Status.FailureReason != v1beta2.FailureReasonPreempted {
+		return false, nil
+	}
+	podName := app.Status.DriverInfo.PodName
+	if podName == "" {
+		podName = util.GetDriverPodName(app)
+	}
+	key := types.NamespacedName{Namespace: app.Namespace, Name: podName}
+	if err := r.client.Get(ctx, key, &corev1.Pod{}); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get driver pod %s: %v", podName, err)
+	}
+	return true, nil
+}
+
+// budgetConsumer is a SparkApplication whose resource requests count against the budget of its namespace.
+type budgetConsumer struct {
+	app      *v1beta2.SparkApplication
+	requests corev1.ResourceList
+}
+
+// exceedsNamespaceBudget tells whether the given resource requests do not fit the budget of the namespace
+// next to those of the given consumers, and returns the name of a resource whose budget is exceeded if so.
+func (r *Reconciler) exceedsNamespaceBudget(consumers []budgetConsumer, requests corev1.ResourceList) (corev1.ResourceName, bool) {
+	lists := []corev1.ResourceList{requests}
+	for _, consumer := range consumers {
+		lists = append(lists, consumer.requests)
+	}
+	total := util.SumResourceList(lists)
+	for name, budget := range r.options.NamespaceBudget {
+		if quantity, ok := total[name]; ok && quantity.Cmp(budget) > 0 {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// preemptForSparkApplication preempts running SparkApplications with a lower priority than the given queued
+// SparkApplication, so that it fits the budget of its namespace once they have terminated. The applications
+// with the lowest priority that have been created last are preempted first, and none is preempted if the
+// application would not fit even then. Applications already being preempted are expected to release their
+// resources soon, so no more are preempted if that is enough. The names of the applications being preempted
+// are returned.
+func (r *Reconciler) preemptForSparkApplication(
+	ctx context.Context,
+	app *v1beta2.SparkApplication,
+	consumers []budgetConsumer,
+	requests corev1.ResourceList,
+) ([]string, error) {
+	priority, err := r.getSparkApplicationPriority(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+
+	var preempting []string
+	var remaining []budgetConsumer
+	var candidates []budgetConsumer
+	priorities := make(map[types.UID]int32)
+	for _, consumer := range consumers {
+		if consumer.app.Status.AppState.Reason == v1beta2.ApplicationStateReasonPreempted {
+			preempting = append(preempting, consumer.app.Name)
+			continue
+		}
+		remaining = append(remaining, consumer)
+		if !isPreemptible(consumer.app) {
+			continue
+		}
+		consumerPriority, err := r.getSparkApplicationPriority(ctx, consumer.app)
+		if err != nil {
+			return nil, err
+		}
+		if consumerPriority < priority {
+			candidates = append(candidates, consumer)
+			priorities[consumer.app.UID] = consumerPriority
+		}
+	}
+	if _, exceeded := r.exceedsNamespaceBudget(remaining, requests); !exceeded {
+		return preempting, nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if priorities[candidates[i].app.UID] != priorities[candidates[j].app.UID] {
+			return priorities[candidates[i].app.UID] < priorities[candidates[j].app.UID]
+		}
+		if !candidates[i].app.CreationTimestamp.Equal(&candidates[j].app.CreationTimestamp) {
+			return candidates[j].app.CreationTimestamp.Before(&candidates[i].app.CreationTimestamp)
+		}
+		return candidates[i].app.Name < candidates[j].app.Name
+	})
+
+	victims := make(map[types.UID]bool)
+	for _, candidate := range candidates {
+		victims[candidate.app.UID] = true
+		var kept []budgetConsumer
+		for _, consumer := range remaining {
+			if !victims[consumer.app.UID] {
+				kept = append(kept, consumer)
+			}
+		}
+		if _, exceeded := r.exceedsNamespaceBudget(kept, requests); exceeded {
+			continue
+		}
+
+		for _, victim := range candidates {
+			if !victims[victim.app.UID] {
+				continue
+			}
+			if err := r.preemptSparkApplication(ctx, victim.app, app); err != nil {
+				return nil, err
+			}
+			preempting = append(preempting, victim.app.Name)
+		}
+		return preempting, nil
+	}
+	return preempting, nil
+}
+
+// preemptSparkApplication preempts the given running SparkApplication in favor of the given preemptor. The
+// application is moved to the Failing state with the Preempted reason, so that it is retried according to its
+// restart policy, and its driver is given the configured grace period to terminate.
+func (r *Reconciler) preemptSparkApplication(ctx context.Context, victim *v1beta2.SparkApplication, preemptor *v1beta2.SparkApplication) error {
+	key := types.NamespacedName{Namespace: victim.Namespace, Name: victim.Name}
+	var preempted *v1beta2.SparkApplication
+	retryErr := retry.RetryOnConflict(
+		retry.DefaultRetry,
+		func() error {
+			old, err := r.getSparkApplication(key)
+			if err != nil {
+				return err
+			}
+			if !isPreemptible(old) || old.Status.AppState.Reason == v1beta2.ApplicationStateReasonPreempted {
+				return nil
+			}
+			app := old.DeepCopy()
+			app.Status.AppState = v1beta2.ApplicationState{
+				State:        v1beta2.ApplicationStateFailing,
+				Reason:       v1beta2.ApplicationStateReasonPreempted,
+				ErrorMessage: fmt.Sprintf("preempted by SparkApplication %s with a higher priority", preemptor.Name),
+			}
+			app.Status.FailureReason = v1beta2.FailureReasonPreempted
+			app.Status.TerminationTime = metav1.Now()
+			if err := r.updateSparkApplicationStatus(ctx, app); err != nil {
+				return err
+			}
+			preempted = app
+			return nil
+		},
+	)
+	if retryErr != nil {
+		return fmt.Errorf("failed to preempt SparkApplication %s: %v", victim.Name, retryErr)
+	}
+	if preempted == nil {
+		return nil
+	}
+
+	logger.Info("Preempting SparkApplication", "name", preempted.Name, "namespace", preempted.Namespace, "preemptor", preemptor.Name)
+	gracePeriodSeconds := int64(r.options.PreemptionGracePeriod / time.Second)
+	if err := r.deleteDriverPod(ctx, preempted, client.GracePeriodSeconds(gracePeriodSeconds)); err != nil {
+		return fmt.Errorf("failed to delete driver pod of preempted SparkApplication %s: %v", preempted.Name, err)
+	}
+	r.recorder.Eventf(
+		preempted,
+		corev1.EventTypeWarning,
+		common.EventSparkApplicationPreempted,
+		"SparkApplication %s was preempted by SparkApplication %s with a higher priority",
+		preempted.Name,
+		preemptor.Name,
+	)
+	r.recorder.Eventf(
+		preemptor,
+		corev1.EventTypeNormal,
+		common.EventSparkApplicationPreempting,
+		"SparkApplication %s preempted SparkApplication %s to fit the budget of namespace %s",
+		preemptor.Name,
+		preempted.Name,
+		preemptor.Namespace,
+	)
+	return nil
+}
+
+// isPreemptible tells whether the given SparkApplication has been submitted and can be preempted.
+func isPreemptible(app *v1beta2.SparkApplication) bool {
+	switch app.Status.AppState.State {
+	case v1beta2.ApplicationStateSubmitted, v1beta2.ApplicationStateRunning:
+		return app.DeletionTimestamp.IsZero()
+	}
+	return false
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+func newAdmissionTestReconciler(t *testing.T, options Options, objects ...client.Object) *Reconciler {
+	scheme := runtime.NewScheme()
+	assert.Nil(t, clientgoscheme.AddToScheme(scheme))
+	assert.Nil(t, v1beta2.AddToScheme(scheme))
+	return &Reconciler{
+		client:  fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).WithStatusSubresource(&v1beta2.SparkApplication{}).Build(),
+		options: options,
+	}
+}
+
+func newAdmissionTestApp(name string, state v1beta2.ApplicationStateType, creationTime time.Time) *v1beta2.SparkApplication {
+	return &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			UID:               types.UID("uid-" + name),
+			CreationTimestamp: metav1.NewTime(creationTime),
+		},
+		Spec: v1beta2.SparkApplicationSpec{
+			Type: v1beta2.SparkApplicationTypeScala,
+			Driver: v1beta2.DriverSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{Cores: util.Int32Ptr(1), Memory: util.StringPtr("1g")},
+			},
+			Executor: v1beta2.ExecutorSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{Cores: util.Int32Ptr(1), Memory: util.StringPtr("1g")},
+				Instances:    util.Int32Ptr(1),
+			},
+		},
+		Status: v1beta2.SparkApplicationStatus{
+			AppState: v1beta2.ApplicationState{State: state},
+		},
+	}
+}
+
+func TestAdmitSparkApplicationOrder(t *testing.T) {
+	now := time.Now()
+	first := newAdmissionTestApp("first", v1beta2.ApplicationStateQueued, now.Add(-2*time.Minute))
+	second := newAdmissionTestApp("second", v1beta2.ApplicationStateQueued, now.Add(-time.Minute))
+	urgent := newAdmissionTestApp("urgent", v1beta2.ApplicationStateQueued, now)
+	urgent.Spec.Driver.PriorityClassName = util.StringPtr("high")
+	highPriority := &schedulingv1.PriorityClass{ObjectMeta: metav1.ObjectMeta{Name: "high"}, Value: 1000}
+
+	r := newAdmissionTestReconciler(t, Options{EnableAdmissionQueue: true}, first, second, urgent, highPriority)
+
+	admitted, message, err := r.admitSparkApplication(context.TODO(), first)
+	assert.Nil(t, err)
+	assert.False(t, admitted)
+	assert.Equal(t, "waiting for SparkApplication urgent to be admitted first", message)
+
+	admitted, _, err = r.admitSparkApplication(context.TODO(), urgent)
+	assert.Nil(t, err)
+	assert.True(t, admitted)
+}
+
+func TestAdmitSparkApplicationNamespaceBudget(t *testing.T) {
+	running := newAdmissionTestApp("running", v1beta2.ApplicationStateRunning, time.Now())
+	completed := newAdmissionTestApp("completed", v1beta2.ApplicationStateCompleted, time.Now())
+	queued := newAdmissionTestApp("queued", v1beta2.ApplicationStateQueued, time.Now())
+
+	options := Options{
+		EnableAdmissionQueue: true,
+		NamespaceBudget:      corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("3")},
+	}
+	r := newAdmissionTestReconciler(t, options, running, completed, queued)
+	admitted, message, err := r.admitSparkApplication(context.TODO(), queued)
+	assert.Nil(t, err)
+	assert.False(t, admitted)
+	assert.Equal(t, "waiting for cpu within the namespace budget of 3", message)
+
+	options.NamespaceBudget[corev1.ResourceCPU] = resource.MustParse("4")
+	r = newAdmissionTestReconciler(t, options, running, completed, queued)
+	admitted, _, err = r.admitSparkApplication(context.TODO(), queued)
+	assert.Nil(t, err)
+	assert.True(t, admitted)
+}
+
+func TestAdmitSparkApplicationResourceQuota(t *testing.T) {
+	queued := newAdmissionTestApp("queued", v1beta2.ApplicationStateQueued, time.Now())
+	resourceQuota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: "default"},
+		Spec: corev1.ResourceQuotaSpec{
+			Hard: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("4")},
+		},
+		Status: corev1.ResourceQuotaStatus{
+			Hard: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("4")},
+			Used: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("3")},
+		},
+	}
+
+	r := newAdmissionTestReconciler(t, Options{EnableAdmissionQueue: true}, queued, resourceQuota)
+	admitted, message, err := r.admitSparkApplication(context.TODO(), queued)
+	assert.Nil(t, err)
+	assert.False(t, admitted)
+	assert.Equal(t, "waiting for capacity in resource quota \"default/quota\"", message)
+}
+
+func TestAdmitSparkApplicationPriority(t *testing.T) {
+	now := time.Now()
+	first := newAdmissionTestApp("first", v1beta2.ApplicationStateQueued, now.Add(-time.Minute))
+	first.Spec.Driver.PriorityClassName = util.StringPtr("high")
+	second := newAdmissionTestApp("second", v1beta2.ApplicationStateQueued, now)
+	// The priority of the application takes precedence over the value of its PriorityClass.
+	second.Spec.Priority = util.Int32Ptr(2000)
+	second.Spec.Driver.PriorityClassName = util.StringPtr("high")
+	highPriority := &schedulingv1.PriorityClass{ObjectMeta: metav1.ObjectMeta{Name: "high"}, Value: 1000}
+
+	r := newAdmissionTestReconciler(t, Options{EnableAdmissionQueue: true}, first, second, highPriority)
+
+	admitted, message, err := r.admitSparkApplication(context.TODO(), first)
+	assert.Nil(t, err)
+	assert.False(t, admitted)
+	assert.Equal(t, "waiting for SparkApplication second to be admitted first", message)
+}
+
+func TestAdmitSparkApplicationPreemption(t *testing.T) {
+	now := time.Now()
+	low := newAdmissionTestApp("low", v1beta2.ApplicationStateRunning, now.Add(-2*time.Minute))
+	low.Spec.Priority = util.Int32Ptr(1)
+	low.Spec.Driver.PriorityClassName = util.StringPtr("high")
+	lowDriver := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: util.GetDriverPodName(low), Namespace: "default"}}
+	medium := newAdmissionTestApp("medium", v1beta2.ApplicationStateRunning, now.Add(-time.Minute))
+	medium.Spec.Priority = util.Int32Ptr(5)
+	high := newAdmissionTestApp("high", v1beta2.ApplicationStateQueued, now)
+	high.Spec.Priority = util.Int32Ptr(10)
+
+	options := Options{
+		EnableAdmissionQueue:  true,
+		NamespaceBudget:       corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+		EnablePreemption:      true,
+		PreemptionGracePeriod: 30 * time.Second,
+	}
+	r := newAdmissionTestReconciler(t, options, low, lowDriver, medium, high)
+	recorder := record.NewFakeRecorder(10)
+	r.recorder = recorder
+
+	// Preempting the application with the lowest priority is enough to fit the budget.
+	admitted, message, err := r.admitSparkApplication(context.TODO(), high)
+	assert.Nil(t, err)
+	assert.False(t, admitted)
+	assert.Equal(t, "waiting for preempted SparkApplications low to terminate", message)
+
+	preempted := &v1beta2.SparkApplication{}
+	assert.Nil(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "low"}, preempted))
+	assert.Equal(t, v1beta2.ApplicationStateFailing, preempted.Status.AppState.State)
+	assert.Equal(t, v1beta2.ApplicationStateReasonPreempted, preempted.Status.AppState.Reason)
+	assert.Equal(t, v1beta2.FailureReasonPreempted, preempted.Status.FailureReason)
+	assert.True(t, errors.IsNotFound(r.client.Get(context.TODO(), client.ObjectKeyFromObject(lowDriver), &corev1.Pod{})))
+	assert.Len(t, recorder.Events, 2)
+
+	notPreempted := &v1beta2.SparkApplication{}
+	assert.Nil(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "medium"}, notPreempted))
+	assert.Equal(t, v1beta2.ApplicationStateRunning, notPreempted.Status.AppState.State)
+
+	// No more applications are preempted while the preempted one is terminating.
+	admitted, message, err = r.admitSparkApplication(context.TODO(), high)
+	assert.Nil(t, err)
+	assert.False(t, admitted)
+	assert.Equal(t, "waiting for preempted SparkApplications low to terminate", message)
+	assert.Len(t, recorder.Events, 2)
+}
+
+func TestAdmitSparkApplicationPreemptedDriverTerminating(t *testing.T) {
+	now := time.Now()
+	preempted := newAdmissionTestApp("preempted", v1beta2.ApplicationStateFailed, now.Add(-time.Minute))
+	preempted.Status.AppState.Reason = v1beta2.ApplicationStateReasonPreempted
+	preempted.Status.FailureReason = v1beta2.FailureReasonPreempted
+	preemptedDriver := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: util.GetDriverPodName(preempted), Namespace: "default"}}
+	queued := newAdmissionTestApp("queued", v1beta2.ApplicationStateQueued, now)
+
+	options := Options{
+		EnableAdmissionQueue: true,
+		NamespaceBudget:      corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("3")},
+	}
+	r := newAdmissionTestReconciler(t, options, preempted, preemptedDriver, queued)
+
+	// The preempted application keeps counting against the budget while its driver pod is terminating.
+	admitted, message, err := r.admitSparkApplication(context.TODO(), queued)
+	assert.Nil(t, err)
+	assert.False(t, admitted)
+	assert.Equal(t, "waiting for cpu within the namespace budget of 3", message)
+
+	assert.Nil(t, r.client.Delete(context.TODO(), preemptedDriver))
+	admitted, _, err = r.admitSparkApplication(context.TODO(), queued)
+	assert.Nil(t, err)
+	assert.True(t, admitted)
+}
+
+func TestAdmitSparkApplicationWithoutPreemption(t *testing.T) {
+	now := time.Now()
+	running := newAdmissionTestApp("running", v1beta2.ApplicationStateRunning, now.Add(-time.Minute))
+	running.Spec.Priority = util.Int32Ptr(10)
+	queued := newAdmissionTestApp("queued", v1beta2.ApplicationStateQueued, now)
+	queued.Spec.Priority = util.Int32Ptr(10)
+
+	options := Options{
+		EnableAdmissionQueue: true,
+		NamespaceBudget:      corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("3")},
+		EnablePreemption:     true,
+	}
+	r := newAdmissionTestReconciler(t, options, running, queued)
+
+	// Applications with the same priority are not preempted.
+	admitted, message, err := r.admitSparkApplication(context.TODO(), queued)
+	assert.Nil(t, err)
+	assert.False(t, admitted)
+	assert.Equal(t, "waiting for cpu within the namespace budget of 3", message)
+
+	notPreempted := &v1beta2.SparkApplication{}
+	assert.Nil(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "running"}, notPreempted))
+	assert.Equal(t, v1beta2.ApplicationStateRunning, notPreempted.Status.AppState.State)
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// maxAttemptErrorMessageLength is the maximum length of the error message of an attempt, which only summarizes
+// the error, so that the attempt history does not grow the status by the full error of every attempt.
+const maxAttemptErrorMessageLength = 256
+
+// recordSparkApplicationAttempt records the current attempt of the given SparkApplication in its attempt history,
+// which keeps the details of past attempts after the status is reset for the next one. Attempts are identified
+// by their submission ID and time, and only the most recent ones are kept.
+func (r *Reconciler) recordSparkApplicationAttempt(app *v1beta2.SparkApplication) {
+	status := &app.Status
+	if r.options.MaxAttemptHistory <= 0 || status.SubmissionID == "" {
+		return
+	}
+
+	switch status.AppState.State {
+	case v1beta2.ApplicationStateSubmitted,
+		v1beta2.ApplicationStateRunning,
+		v1beta2.ApplicationStateUnknown,
+		v1beta2.ApplicationStateSucceeding,
+		v1beta2.ApplicationStateCompleted,
+		v1beta2.ApplicationStateFailing,
+		v1beta2.ApplicationStateFailed,
+		v1beta2.ApplicationStateFailedSubmission:
+		attempt := findSparkApplicationAttempt(status.Attempts, status.SubmissionID, status.LastSubmissionAttemptTime)
+		if attempt == nil {
+			attempt = r.addSparkApplicationAttempt(app)
+		}
+		updateSparkApplicationAttempt(attempt, status)
+	default:
+		// The attempt was interrupted before it terminated, e.g. as the application was invalidated, or it
+		// terminated already. Its details may have been reset, so only its end is recorded.
+		attempt := findSparkApplicationAttempt(status.Attempts, status.SubmissionID, metav1.Time{})
+		if attempt != nil && attempt.EndTime.IsZero() {
+			attempt.EndTime = metav1.Now()
+			attempt.Reason = status.AppState.Reason
+			if status.FailureReason != "" {
+				attempt.FailureReason = status.FailureReason
+				attempt.ErrorMessage = util.TruncateString(status.AppState.ErrorMessage, maxAttemptErrorMessageLength)
+			}
+		}
+	}
+}
+
+// addSparkApplicationAttempt appends a new attempt to the attempt history of the given SparkApplication, dropping
+// the oldest attempts beyond the maximum history size, and returns it.
+func (r *Reconciler) addSparkApplicationAttempt(app *v1beta2.SparkApplication) *v1beta2.AttemptInfo {
+	status := &app.Status
+	number := int32(1)
+	if n := len(status.Attempts); n > 0 {
+		number = status.Attempts[n-1].Attempt + 1
+	}
+	status.Attempts = append(status.Attempts, v1beta2.AttemptInfo{
+		Attempt:      number,
+		SubmissionID: status.SubmissionID,
+		StartTime:    status.LastSubmissionAttemptTime,
+	})
+	if excess := len(status.Attempts) - r.options.MaxAttemptHistory; excess > 0 {
+		status.Attempts = append([]v1beta2.AttemptInfo(nil), status.Attempts[excess:]...)
+	}
+	return &status.Attempts[len(status.Attempts)-1]
+}
+
+// findSparkApplicationAttempt returns the most recent attempt with the given submission ID, and start time if it
+// is not zero, or nil if there is none.
+func findSparkApplicationAttempt(attempts []v1beta2.AttemptInfo, submissionID string, startTime metav1.Time) *v1beta2.AttemptInfo {
+	for i := len(attempts) - 1; i >= 0; i-- {
+		if attempts[i].SubmissionID != submissionID {
+			continue
+		}
+		if startTime.IsZero() || attempts[i].StartTime.Equal(&startTime) {
+			return &attempts[i]
+		}
+	}
+	return nil
+}
+
+// updateSparkApplicationAttempt updates the given attempt from the status of the SparkApplication.
+func updateSparkApplicationAttempt(attempt *v1beta2.AttemptInfo, status *v1beta2.SparkApplicationStatus) {
+	if status.SparkApplicationID != "" {
+		attempt.SparkApplicationID = status.SparkApplicationID
+	}
+	attempt.DriverPodName = status.DriverInfo.PodName
+	attempt.Reason = status.AppState.Reason
+	attempt.FailureReason = status.FailureReason
+	attempt.ErrorMessage = util.TruncateString(status.AppState.ErrorMessage, maxAttemptErrorMessageLength)
+	if len(status.ExecutorState) > 0 {
+		attempt.Executors = getExecutorSummary(status.ExecutorState)
+	}
+
+	// The attempt terminated already if the application is succeeding or failing, whether it is retried or not.
+	switch status.AppState.State {
+	case v1beta2.ApplicationStateSucceeding, v1beta2.ApplicationStateCompleted:
+		attempt.State = v1beta2.ApplicationStateCompleted
+	case v1beta2.ApplicationStateFailing, v1beta2.ApplicationStateFailed:
+		attempt.State = v1beta2.ApplicationStateFailed
+	default:
+		attempt.State = status.AppState.State
+	}
+	switch attempt.State {
+	case v1beta2.ApplicationStateFailedSubmission:
+		attempt.EndTime = status.LastSubmissionAttemptTime
+	case v1beta2.ApplicationStateCompleted, v1beta2.ApplicationStateFailed:
+		if attempt.EndTime.IsZero() {
+			// The termination time may be left over from a previous attempt.
+			attempt.EndTime = status.TerminationTime
+			if attempt.EndTime.Before(&attempt.StartTime) {
+				attempt.EndTime = metav1.Now()
+			}
+		}
+	}
+}
+
+// getExecutorSummary counts the given executors by state.
+func getExecutorSummary(executorState map[string]v1beta2.ExecutorState) v1beta2.ExecutorSummary {
+	summary := v1beta2.ExecutorSummary{Total: int32(len(executorState))}
+	for _, state := range executorState {
+		switch state {
+		case v1beta2.ExecutorStateRunning:
+			summary.Running++
+		case v1beta2.ExecutorStateCompleted:
+			summary.Completed++
+		case v1beta2.ExecutorStateFailed:
+			summary.Failed++
+		}
+	}
+	return summary
+}
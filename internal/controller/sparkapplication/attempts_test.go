@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+)
+
+// submitAttemptTestApp sets the status of the given SparkApplication as if a new attempt was submitted.
+func submitAttemptTestApp(app *v1beta2.SparkApplication, submissionID string) {
+	app.Status.SubmissionID = submissionID
+	app.Status.LastSubmissionAttemptTime = metav1.NewTime(time.Now().Add(-time.Minute))
+	app.Status.SubmissionAttempts++
+	app.Status.DriverInfo.PodName = "app-driver-" + submissionID
+	app.Status.AppState = v1beta2.ApplicationState{State: v1beta2.ApplicationStateSubmitted}
+}
+
+func TestRecordSparkApplicationAttempt(t *testing.T) {
+	r := &Reconciler{options: Options{MaxAttemptHistory: 10}}
+	app := newAdmissionTestApp("app", v1beta2.ApplicationStateNew, time.Now())
+
+	// The first attempt fails and is retried.
+	submitAttemptTestApp(app, "first")
+	r.recordSparkApplicationAttempt(app)
+	app.Status.AppState.State = v1beta2.ApplicationStateRunning
+	app.Status.SparkApplicationID = "spark-first"
+	app.Status.ExecutorState = map[string]v1beta2.ExecutorState{"exec-1": v1beta2.ExecutorStateRunning, "exec-2": v1beta2.ExecutorStateRunning}
+	r.recordSparkApplicationAttempt(app)
+	app.Status.AppState = v1beta2.ApplicationState{State: v1beta2.ApplicationStateFailing, ErrorMessage: "driver container failed"}
+	app.Status.FailureReason = v1beta2.FailureReasonDriverOOMKilled
+	app.Status.TerminationTime = metav1.Now()
+	app.Status.ExecutorState["exec-2"] = v1beta2.ExecutorStateFailed
+	r.recordSparkApplicationAttempt(app)
+	markPendingRerun(app, v1beta2.ApplicationStateReasonRetryingFailure)
+	r.recordSparkApplicationAttempt(app)
+	r.resetSparkApplicationStatus(app)
+	r.recordSparkApplicationAttempt(app)
+
+	// The second attempt completes.
+	submitAttemptTestApp(app, "second")
+	r.recordSparkApplicationAttempt(app)
+	app.Status.AppState.State = v1beta2.ApplicationStateCompleted
+	app.Status.TerminationTime = metav1.Now()
+	r.recordSparkApplicationAttempt(app)
+
+	assert.Len(t, app.Status.Attempts, 2)
+	first := app.Status.Attempts[0]
+	assert.Equal(t, int32(1), first.Attempt)
+	assert.Equal(t, "first", first.SubmissionID)
+	assert.Equal(t, "spark-first", first.SparkApplicationID)
+	assert.Equal(t, "app-driver-first", first.DriverPodName)
+	assert.Equal(t, v1beta2.ApplicationStateFailed, first.State)
+	assert.Equal(t, v1beta2.FailureReasonDriverOOMKilled, first.FailureReason)
+	assert.Equal(t, "driver container failed", first.ErrorMessage)
+	assert.Equal(t, v1beta2.ExecutorSummary{Total: 2, Running: 1, Failed: 1}, first.Executors)
+	assert.False(t, first.StartTime.IsZero())
+	assert.False(t, first.EndTime.IsZero())
+
+	second := app.Status.Attempts[1]
+	assert.Equal(t, int32(2), second.Attempt)
+	assert.Equal(t, "app-driver-second", second.DriverPodName)
+	assert.Equal(t, v1beta2.ApplicationStateCompleted, second.State)
+	assert.Empty(t, second.FailureReason)
+	assert.False(t, second.EndTime.Before(&second.StartTime))
+}
+
+func TestRecordSparkApplicationAttemptInterrupted(t *testing.T) {
+	r := &Reconciler{options: Options{MaxAttemptHistory: 10}}
+	app := newAdmissionTestApp("app", v1beta2.ApplicationStateNew, time.Now())
+	submitAttemptTestApp(app, "first")
+	app.Status.AppState.State = v1beta2.ApplicationStateRunning
+	r.recordSparkApplicationAttempt(app)
+
+	// The application is invalidated as its spec was updated.
+	app.Status.AppState.State = v1beta2.ApplicationStateInvalidating
+	r.resetSparkApplicationStatus(app)
+	markPendingRerun(app, v1beta2.ApplicationStateReasonSpecUpdated)
+	r.recordSparkApplicationAttempt(app)
+
+	assert.Len(t, app.Status.Attempts, 1)
+	attempt := app.Status.Attempts[0]
+	assert.Equal(t, v1beta2.ApplicationStateRunning, attempt.State)
+	assert.Equal(t, v1beta2.ApplicationStateReasonSpecUpdated, attempt.Reason)
+	assert.False(t, attempt.EndTime.IsZero())
+}
+
+func TestRecordSparkApplicationAttemptFailedSubmissions(t *testing.T) {
+	r := &Reconciler{options: Options{MaxAttemptHistory: 2}}
+	app := newAdmissionTestApp("app", v1beta2.ApplicationStateNew, time.Now())
+	for i, submissionID := range []string{"first", "second", "third"} {
+		submitAttemptTestApp(app, submissionID)
+		app.Status.LastSubmissionAttemptTime = metav1.NewTime(time.Now().Add(time.Duration(i) * time.Second))
+		app.Status.AppState = v1beta2.ApplicationState{State: v1beta2.ApplicationStateFailedSubmission, ErrorMessage: "failed to run spark-submit"}
+		app.Status.FailureReason = v1beta2.FailureReasonSubmissionError
+		r.recordSparkApplicationAttempt(app)
+	}
+
+	// Only the most recent attempts are kept.
+	assert.Len(t, app.Status.Attempts, 2)
+	assert.Equal(t, int32(2), app.Status.Attempts[0].Attempt)
+	assert.Equal(t, "second", app.Status.Attempts[0].SubmissionID)
+	assert.Equal(t, int32(3), app.Status.Attempts[1].Attempt)
+	assert.Equal(t, v1beta2.ApplicationStateFailedSubmission, app.Status.Attempts[1].State)
+	assert.Equal(t, app.Status.LastSubmissionAttemptTime, app.Status.Attempts[1].EndTime)
+}
+
+func TestRecordSparkApplicationAttemptLongErrorMessage(t *testing.T) {
+	r := &Reconciler{options: Options{MaxAttemptHistory: 10}}
+	app := newAdmissionTestApp("app", v1beta2.ApplicationStateNew, time.Now())
+	submitAttemptTestApp(app, "first")
+	app.Status.AppState = v1beta2.ApplicationState{
+		State:        v1beta2.ApplicationStateFailedSubmission,
+		ErrorMessage: "failed to run spark-submit: " + strings.Repeat("x", 32*1024),
+	}
+	r.recordSparkApplicationAttempt(app)
+
+	// The attempt only keeps the beginning of the error message, while the status keeps all of it.
+	assert.Len(t, app.Status.Attempts, 1)
+	assert.LessOrEqual(t, len(app.Status.Attempts[0].ErrorMessage), maxAttemptErrorMessageLength)
+	assert.True(t, strings.HasPrefix(app.Status.Attempts[0].ErrorMessage, "failed to run spark-submit: xxx"))
+	assert.Greater(t, len(app.Status.AppState.ErrorMessage), 32*1024)
+}
+
+func TestRecordSparkApplicationAttemptDisabled(t *testing.T) {
+	r := &Reconciler{}
+	app := newAdmissionTestApp("app", v1beta2.ApplicationStateNew, time.Now())
+	submitAttemptTestApp(app, "first")
+	r.recordSparkApplicationAttempt(app)
+	assert.Empty(t, app.Status.Attempts)
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// maxConditionMessageLength is the maximum length of the messages of the conditions, which is well below the
+// limit of the API server so that long error messages, e.g. the output of spark-submit, do not fail the update.
+const maxConditionMessageLength = 1024
+
+// setSparkApplicationConditions sets the conditions of the given SparkApplication according to its state. The
+// Ready condition depends on the driver pod and is set by setSparkApplicationReadyCondition, so it is only
+// initialized here while the application is running, and reset once it is no longer. The error message of the
+// application is only set on the conditions that are true.
+func setSparkApplicationConditions(app *v1beta2.SparkApplication) {
+	state := app.Status.AppState.State
+	reason := getConditionReason(app)
+	message := app.Status.AppState.ErrorMessage
+
+	set := func(conditionType string, status bool, reason string) {
+		if status {
+			setSparkApplicationCondition(app, conditionType, true, reason, message)
+		} else {
+			setSparkApplicationCondition(app, conditionType, false, reason, "")
+		}
+	}
+
+	set(v1beta2.SparkApplicationConditionSubmitted, isSubmitted(app), reason)
+	set(v1beta2.SparkApplicationConditionRunning, state == v1beta2.ApplicationStateRunning, reason)
+	if state != v1beta2.ApplicationStateRunning || meta.FindStatusCondition(app.Status.Conditions, v1beta2.SparkApplicationConditionReady) == nil {
+		set(v1beta2.SparkApplicationConditionReady, false, reason)
+	}
+	set(v1beta2.SparkApplicationConditionSucceeded, state == v1beta2.ApplicationStateCompleted, reason)
+	failedReason := reason
+	if state == v1beta2.ApplicationStateFailed {
+		failedReason = string(util.GetFailureReason(app))
+	}
+	set(v1beta2.SparkApplicationConditionFailed, state == v1beta2.ApplicationStateFailed, failedReason)
+	set(v1beta2.SparkApplicationConditionSuspended, state == v1beta2.ApplicationStateSuspended, reason)
+}
+
+// setSparkApplicationReadyCondition sets the Ready condition of the given running SparkApplication, which tells
+// whether its driver pod, which serves the web UI, is ready.
+func setSparkApplicationReadyCondition(app *v1beta2.SparkApplication, driverPod *corev1.Pod) {
+	if app.Status.AppState.State != v1beta2.ApplicationStateRunning {
+		return
+	}
+
+	if !util.IsPodReady(driverPod) {
+		setSparkApplicationCondition(app, v1beta2.SparkApplicationConditionReady, false, "DriverNotReady", fmt.Sprintf("driver pod %s is not ready", driverPod.Name))
+		return
+	}
+	message := fmt.Sprintf("driver pod %s is ready", driverPod.Name)
+	if app.Status.DriverInfo.WebUIAddress != "" {
+		message = fmt.Sprintf("%s, web UI is available at %s", message, app.Status.DriverInfo.WebUIAddress)
+	}
+	setSparkApplicationCondition(app, v1beta2.SparkApplicationConditionReady, true, "DriverReady", message)
+}
+
+func setSparkApplicationCondition(app *v1beta2.SparkApplication, conditionType string, status bool, reason, message string) {
+	conditionStatus := metav1.ConditionFalse
+	if status {
+		conditionStatus = metav1.ConditionTrue
+	}
+	meta.SetStatusCondition(&app.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             conditionStatus,
+		ObservedGeneration: app.Generation,
+		Reason:             reason,
+		Message:            util.TruncateString(message, maxConditionMessageLength),
+	})
+}
+
+// isSubmitted returns whether the current attempt of the given SparkApplication was submitted.
+func isSubmitted(app *v1beta2.SparkApplication) bool {
+	switch app.Status.AppState.State {
+	case v1beta2.ApplicationStateSubmitted,
+		v1beta2.ApplicationStateRunning,
+		v1beta2.ApplicationStateSucceeding,
+		v1beta2.ApplicationStateCompleted,
+		v1beta2.ApplicationStateUnknown:
+		return true
+	case v1beta2.ApplicationStateFailing, v1beta2.ApplicationStateFailed:
+		// Applications also fail without being submitted, e.g. if their submission or their upstreams failed.
+		switch app.Status.FailureReason {
+		case v1beta2.FailureReasonSubmissionError, v1beta2.FailureReasonUpstreamFailed:
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// getConditionReason returns the reason of the conditions of the given SparkApplication, which is its state
+// reason if any, and its state otherwise, in CamelCase, e.g. PendingRerun.
+func getConditionReason(app *v1beta2.SparkApplication) string {
+	reason := string(app.Status.AppState.Reason)
+	if reason == "" {
+		reason = string(app.Status.AppState.State)
+	}
+	if reason == "" {
+		return "New"
+	}
+
+	var b strings.Builder
+	for _, word := range strings.Split(strings.ToLower(reason), "_") {
+		if word == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
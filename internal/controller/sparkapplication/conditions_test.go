@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+)
+
+func TestSetSparkApplicationConditions(t *testing.T) {
+	testCases := []struct {
+		name          string
+		state         v1beta2.ApplicationStateType
+		failureReason v1beta2.FailureReason
+		wantTrue      []string
+		wantReason    string
+	}{
+		{name: "new", state: v1beta2.ApplicationStateNew, wantReason: "New"},
+		{name: "submitted", state: v1beta2.ApplicationStateSubmitted, wantTrue: []string{v1beta2.SparkApplicationConditionSubmitted}, wantReason: "Submitted"},
+		{name: "running", state: v1beta2.ApplicationStateRunning, wantTrue: []string{v1beta2.SparkApplicationConditionSubmitted, v1beta2.SparkApplicationConditionRunning}, wantReason: "Running"},
+		{name: "completed", state: v1beta2.ApplicationStateCompleted, wantTrue: []string{v1beta2.SparkApplicationConditionSubmitted, v1beta2.SparkApplicationConditionSucceeded}, wantReason: "Completed"},
+		{name: "failed", state: v1beta2.ApplicationStateFailed, failureReason: v1beta2.FailureReasonDriverOOMKilled, wantTrue: []string{v1beta2.SparkApplicationConditionSubmitted, v1beta2.SparkApplicationConditionFailed}, wantReason: "Failed"},
+		{name: "failed submission", state: v1beta2.ApplicationStateFailed, failureReason: v1beta2.FailureReasonSubmissionError, wantTrue: []string{v1beta2.SparkApplicationConditionFailed}, wantReason: "Failed"},
+		{name: "suspended", state: v1beta2.ApplicationStateSuspended, wantTrue: []string{v1beta2.SparkApplicationConditionSuspended}, wantReason: "Suspended"},
+		{name: "pending rerun", state: v1beta2.ApplicationStatePendingRerun, wantReason: "PendingRerun"},
+	}
+
+	conditionTypes := []string{
+		v1beta2.SparkApplicationConditionSubmitted,
+		v1beta2.SparkApplicationConditionRunning,
+		v1beta2.SparkApplicationConditionReady,
+		v1beta2.SparkApplicationConditionSucceeded,
+		v1beta2.SparkApplicationConditionFailed,
+		v1beta2.SparkApplicationConditionSuspended,
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := newAdmissionTestApp("app", tc.state, time.Now())
+			app.Generation = 3
+			app.Status.FailureReason = tc.failureReason
+
+			setSparkApplicationConditions(app)
+			assert.Len(t, app.Status.Conditions, len(conditionTypes))
+			for _, conditionType := range conditionTypes {
+				condition := meta.FindStatusCondition(app.Status.Conditions, conditionType)
+				assert.NotNil(t, condition, conditionType)
+				wantStatus := metav1.ConditionFalse
+				for _, trueType := range tc.wantTrue {
+					if trueType == conditionType {
+						wantStatus = metav1.ConditionTrue
+					}
+				}
+				assert.Equal(t, wantStatus, condition.Status, conditionType)
+				assert.Equal(t, int64(3), condition.ObservedGeneration)
+				if conditionType == v1beta2.SparkApplicationConditionFailed && tc.state == v1beta2.ApplicationStateFailed {
+					assert.Equal(t, string(tc.failureReason), condition.Reason)
+				} else {
+					assert.Equal(t, tc.wantReason, condition.Reason, conditionType)
+				}
+			}
+		})
+	}
+}
+
+func TestSetSparkApplicationConditionsTransitionTime(t *testing.T) {
+	app := newAdmissionTestApp("app", v1beta2.ApplicationStateRunning, time.Now())
+	setSparkApplicationConditions(app)
+	running := meta.FindStatusCondition(app.Status.Conditions, v1beta2.SparkApplicationConditionRunning)
+	running.LastTransitionTime = metav1.NewTime(time.Now().Add(-time.Hour))
+	transitionTime := running.LastTransitionTime
+
+	// The transition time is kept as long as the status of the condition does not change.
+	app.Status.AppState.Reason = v1beta2.ApplicationStateReasonStuckPending
+	setSparkApplicationConditions(app)
+	running = meta.FindStatusCondition(app.Status.Conditions, v1beta2.SparkApplicationConditionRunning)
+	assert.Equal(t, transitionTime, running.LastTransitionTime)
+	assert.Equal(t, "StuckPending", running.Reason)
+
+	app.Status.AppState = v1beta2.ApplicationState{State: v1beta2.ApplicationStateSucceeding}
+	setSparkApplicationConditions(app)
+	running = meta.FindStatusCondition(app.Status.Conditions, v1beta2.SparkApplicationConditionRunning)
+	assert.Equal(t, metav1.ConditionFalse, running.Status)
+	assert.True(t, running.LastTransitionTime.After(transitionTime.Time))
+}
+
+func TestSetSparkApplicationReadyCondition(t *testing.T) {
+	app := newAdmissionTestApp("app", v1beta2.ApplicationStateRunning, time.Now())
+	app.Status.DriverInfo.WebUIAddress = "10.0.0.1:4040"
+	driverPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-driver"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+
+	setSparkApplicationReadyCondition(app, driverPod)
+	assert.True(t, meta.IsStatusConditionFalse(app.Status.Conditions, v1beta2.SparkApplicationConditionReady))
+
+	driverPod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	setSparkApplicationReadyCondition(app, driverPod)
+	ready := meta.FindStatusCondition(app.Status.Conditions, v1beta2.SparkApplicationConditionReady)
+	assert.Equal(t, metav1.ConditionTrue, ready.Status)
+	assert.Equal(t, "driver pod app-driver is ready, web UI is available at 10.0.0.1:4040", ready.Message)
+
+	// The Ready condition is reset once the application is no longer running.
+	app.Status.AppState.State = v1beta2.ApplicationStateSucceeding
+	setSparkApplicationConditions(app)
+	assert.True(t, meta.IsStatusConditionFalse(app.Status.Conditions, v1beta2.SparkApplicationConditionReady))
+}
+
+func TestSetSparkApplicationConditionsLongErrorMessage(t *testing.T) {
+	app := newAdmissionTestApp("app", v1beta2.ApplicationStateFailed, time.Now())
+	app.Status.FailureReason = v1beta2.FailureReasonSubmissionError
+	app.Status.AppState.ErrorMessage = "failed to run spark-submit: " + strings.Repeat("x", 32*1024)
+	setSparkApplicationConditions(app)
+
+	for _, condition := range app.Status.Conditions {
+		assert.LessOrEqual(t, len(condition.Message), maxConditionMessageLength, condition.Type)
+		if condition.Type == v1beta2.SparkApplicationConditionFailed {
+			assert.True(t, strings.HasPrefix(condition.Message, "failed to run spark-submit: xxx"))
+		} else {
+			assert.Empty(t, condition.Message, condition.Type)
+		}
+	}
+}
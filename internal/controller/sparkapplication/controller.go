@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/golang/glog"
@@ -28,6 +29,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -45,6 +47,7 @@ import (
 	"github.com/kubeflow/spark-operator/internal/metrics"
 	"github.com/kubeflow/spark-operator/internal/scheduler"
 	"github.com/kubeflow/spark-operator/internal/scheduler/kubescheduler"
+	"github.com/kubeflow/spark-operator/internal/scheduler/kueue"
 	"github.com/kubeflow/spark-operator/internal/scheduler/volcano"
 	"github.com/kubeflow/spark-operator/internal/scheduler/yunikorn"
 	"github.com/kubeflow/spark-operator/pkg/common"
@@ -62,6 +65,7 @@ type Options struct {
 	IngressClassName      string
 	IngressURLFormat      string
 	DefaultBatchScheduler string
+	DefaultSubmitter      string
 
 	KubeSchedulerNames []string
 
@@ -69,6 +73,27 @@ type Options struct {
 	SparkExecutorMetrics    *metrics.SparkExecutorMetrics
 
 	MaxTrackedExecutorPerApp int
+	// MaxAttemptHistory is the maximum number of attempts recorded in the status of each SparkApplication.
+	MaxAttemptHistory int
+
+	// SubmissionWorkers is the number of workers submitting SparkApplications asynchronously.
+	// SparkApplications are submitted synchronously by the reconcile workers if it is zero.
+	SubmissionWorkers int
+	// SubmissionTimeout is the default maximum duration of a single submission.
+	SubmissionTimeout time.Duration
+	SubmissionMetrics *metrics.SubmissionMetrics
+
+	// EnableAdmissionQueue enables holding SparkApplications in the Queued state until they fit the
+	// ResourceQuotas and the budget of their namespace.
+	EnableAdmissionQueue bool
+	// NamespaceBudget is the maximum amount of resources requested by the admitted SparkApplications
+	// of each namespace.
+	NamespaceBudget corev1.ResourceList
+	// EnablePreemption enables preempting running SparkApplications with a lower priority when a queued
+	// SparkApplication does not fit the budget of its namespace.
+	EnablePreemption bool
+	// PreemptionGracePeriod is the grace period given to the driver of a preempted SparkApplication to terminate.
+	PreemptionGracePeriod time.Duration
 }
 
 // Reconciler reconciles a SparkApplication object.
@@ -79,6 +104,8 @@ type Reconciler struct {
 	recorder record.EventRecorder
 	options  Options
 	registry *scheduler.Registry
+
+	submissionPool *submissionPool
 }
 
 // Reconciler implements reconcile.Reconciler.
@@ -105,13 +132,16 @@ func NewReconciler(
 
 // +kubebuilder:rbac:groups=,resources=pods,verbs=get;list;watch;create;update;patch;delete;deletecollection
 // +kubebuilder:rbac:groups=,resources=configmaps,verbs=get;list;create;update;patch;delete
-// +kubebuilder:rbac:groups=,resources=services,verbs=get;create;delete
+// +kubebuilder:rbac:groups=,resources=services,verbs=get;create;update;delete
 // +kubebuilder:rbac:groups=,resources=nodes,verbs=get
 // +kubebuilder:rbac:groups=,resources=events,verbs=create;update;patch
 // +kubebuilder:rbac:groups=,resources=resourcequotas,verbs=get;list;watch
+// +kubebuilder:rbac:groups=scheduling.k8s.io,resources=priorityclasses,verbs=get;list;watch
 // +kubebuilder:rbac:groups=extensions,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get
+// +kubebuilder:rbac:groups=kueue.x-k8s.io,resources=workloads,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=kueue.x-k8s.io,resources=resourceflavors,verbs=get;list;watch
 // +kubebuilder:rbac:groups=sparkoperator.k8s.io,resources=sparkapplications,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=sparkoperator.k8s.io,resources=sparkapplications/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=sparkoperator.k8s.io,resources=sparkapplications/finalizers,verbs=update
@@ -127,42 +157,52 @@ func NewReconciler(
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.18.2/pkg/reconcile
 
 // Reconcile handles Create, Update and Delete events of the custom resource.
+// A new or rerun application is started in stages: it waits for its upstream applications in the Waiting
+// state, for capacity in its namespace in the Queued state when the admission queue is enabled, and for a
+// submission worker in the Pending Submission state when asynchronous submission is enabled. A stage that
+// does not apply is skipped, e.g. an application without upstreams goes from New straight to Queued.
+// An application that has not terminated moves to Suspended when it is suspended, and to Invalidating when
+// its spec changes.
 // State Machine for SparkApplication:
-// +--------------------------------------------------------------------------------------------------------------------+
-// |        +---------------------------------------------------------------------------------------------+             |
-// |        |       +----------+                                                                          |             |
-// |        |       |          |                                                                          |             |
-// |        |       |          |                                                                          |             |
-// |        |       |Submission|                                                                          |             |
-// |        |  +---->  Failed  +----+------------------------------------------------------------------+  |             |
-// |        |  |    |          |    |                                                                  |  |             |
-// |        |  |    |          |    |                                                                  |  |             |
-// |        |  |    +----^-----+    |  +-----------------------------------------+                     |  |             |
-// |        |  |         |          |  |                                         |                     |  |             |
-// |        |  |         |          |  |                                         |                     |  |             |
-// |      +-+--+----+    |    +-----v--+-+          +----------+           +-----v-----+          +----v--v--+          |
-// |      |         |    |    |          |          |          |           |           |          |          |          |
-// |      |         |    |    |          |          |          |           |           |          |          |          |
-// |      |   New   +---------> Submitted+----------> Running  +----------->  Failing  +---------->  Failed  |          |
-// |      |         |    |    |          |          |          |           |           |          |          |          |
-// |      |         |    |    |          |          |          |           |           |          |          |          |
-// |      |         |    |    |          |          |          |           |           |          |          |          |
-// |      +---------+    |    +----^-----+          +-----+----+           +-----+-----+          +----------+          |
-// |                     |         |                      |                      |                                      |
-// |                     |         |                      |                      |                                      |
-// |    +------------+   |         |             +-------------------------------+                                      |
-// |    |            |   |   +-----+-----+       |        |                +-----------+          +----------+          |
-// |    |            |   |   |  Pending  |       |        |                |           |          |          |          |
-// |    |            |   +---+   Rerun   <-------+        +---------------->Succeeding +---------->Completed |          |
-// |    |Invalidating|       |           <-------+                         |           |          |          |          |
-// |    |            +------->           |       |                         |           |          |          |          |
-// |    |            |       |           |       |                         |           |          |          |          |
-// |    |            |       +-----------+       |                         +-----+-----+          +----------+          |
-// |    +------------+                           |                               |                                      |
-// |                                             |                               |                                      |
-// |                                             +-------------------------------+                                      |
-// |                                                                                                                    |
-// +--------------------------------------------------------------------------------------------------------------------+
+//
+//	               +--------------+
+//	               |     New      |
+//	               +------+-------+
+//	                      |
+//	               +------v-------+   upstream failed   +--------------+
+//	+------------->|   Waiting    +-------------------->|   Skipped    |
+//	|              +------+-------+                     |  or Failed   |
+//	|                     | upstreams completed         +--------------+
+//	|              +------v-------+      retried
+//	|      +------>|    Queued    |<------------------------------------------+
+//	|      |       +------+-------+                                           |
+//	|      |              | admitted                                          |
+//	|      |       +------v-------+  spark-submit failed  +--------------+    |
+//	|      |       |   Pending    +---------------------->|  Submission  +----+
+//	|      |       |  Submission  |                       |    Failed    +-------------> Failed
+//	|      |       +------+-------+                       +--------------+
+//	|      |              | submitted
+//	|      |       +------v-------+                       +--------------+
+//	|      +-------+  Submitted   +---------------------->|   Failing    +-------------> Failed
+//	|      |       +------+-------+            +--------->|              |
+//	|      |evicted       |                    |          +------+-------+
+//	|      |       +------v-------+            |                 | retried
+//	|      +-------+   Running    +------------+                 |
+//	|              +------+-------+                              |
+//	|                     |                                      |
+//	|              +------v-------+      restarted        +------v-------+
+//	|              |  Succeeding  +---------------------->|              |
+//	|              +------+-------+                       |              |
+//	|                     |                               |   Pending    |
+//	|              +------v-------+                       |    Rerun     |
+//	|              |  Completed   |    +--------------+   |              |
+//	|              +--------------+    | Invalidating +-->|              |
+//	|                                  +--------------+   |              |
+//	|                                  +--------------+   |              |
+//	|                                  |  Suspended   +-->|              |
+//	|                                  +--------------+   +------+-------+
+//	|                                                            |
+//	+------------------------------------------------------------+
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	key := req.NamespacedName
 	app, err := r.getSparkApplication(key)
@@ -182,6 +222,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	switch app.Status.AppState.State {
 	case v1beta2.ApplicationStateNew:
 		return r.reconcileNewSparkApplication(ctx, req)
+	case v1beta2.ApplicationStateWaiting:
+		return r.reconcileWaitingSparkApplication(ctx, req)
+	case v1beta2.ApplicationStateQueued:
+		return r.reconcileQueuedSparkApplication(ctx, req)
+	case v1beta2.ApplicationStatePendingSubmission:
+		return r.reconcilePendingSubmissionSparkApplication(ctx, req)
 	case v1beta2.ApplicationStateSubmitted:
 		return r.reconcileSubmittedSparkApplication(ctx, req)
 	case v1beta2.ApplicationStateFailedSubmission:
@@ -200,14 +246,25 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return r.reconcileCompletedSparkApplication(ctx, req)
 	case v1beta2.ApplicationStateFailed:
 		return r.reconcileFailedSparkApplication(ctx, req)
+	case v1beta2.ApplicationStateSkipped:
+		return r.reconcileSkippedSparkApplication(ctx, req)
 	case v1beta2.ApplicationStateUnknown:
 		return r.reconcileUnknownSparkApplication(ctx, req)
+	case v1beta2.ApplicationStateSuspended:
+		return r.reconcileSuspendedSparkApplication(ctx, req)
 	}
 	return ctrl.Result{}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
+	if r.options.SubmissionWorkers > 0 {
+		r.submissionPool = newSubmissionPool(r, mgr.GetAPIReader(), r.options.SubmissionWorkers, r.options.SubmissionMetrics)
+		if err := mgr.Add(r.submissionPool); err != nil {
+			return fmt.Errorf("failed to add submission pool: %v", err)
+		}
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named("spark-application-controller").
 		Watches(
@@ -261,7 +318,178 @@ func (r *Reconciler) reconcileNewSparkApplication(ctx context.Context, req ctrl.
 			}
 			app := old.DeepCopy()
 
-			_ = r.submitSparkApplication(app)
+			if util.IsSuspended(app) {
+				r.suspendSparkApplication(app)
+			} else {
+				r.startSparkApplication(ctx, app)
+			}
+			if err := r.updateSparkApplicationStatus(ctx, app); err != nil {
+				return err
+			}
+			return nil
+		},
+	)
+	if retryErr != nil {
+		logger.Error(retryErr, "Failed to reconcile SparkApplication", "name", key.Name, "namespace", key.Namespace)
+		return ctrl.Result{Requeue: true}, retryErr
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *Reconciler) reconcileWaitingSparkApplication(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	key := req.NamespacedName
+	waiting := false
+	retryErr := retry.RetryOnConflict(
+		retry.DefaultRetry,
+		func() error {
+			old, err := r.getSparkApplication(key)
+			if err != nil {
+				return err
+			}
+			if old.Status.AppState.State != v1beta2.ApplicationStateWaiting {
+				return nil
+			}
+			app := old.DeepCopy()
+
+			upstreamState, message, err := r.getUpstreamState(ctx, app)
+			if err != nil {
+				return err
+			}
+			waiting = false
+			switch upstreamState {
+			case v1beta2.ApplicationStateCompleted:
+				logger.Info("Upstream SparkApplications completed", "name", app.Name, "namespace", app.Namespace)
+				r.recorder.Eventf(
+					app,
+					corev1.EventTypeNormal,
+					common.EventSparkApplicationUpstreamCompleted,
+					"All upstream SparkApplications of SparkApplication %s completed",
+					app.Name,
+				)
+				r.requestSparkApplicationAdmission(ctx, app)
+			case v1beta2.ApplicationStateFailed:
+				state := v1beta2.ApplicationStateFailed
+				if app.Spec.UpstreamFailurePolicy == v1beta2.UpstreamFailurePolicySkip {
+					state = v1beta2.ApplicationStateSkipped
+				}
+				app.Status.AppState = v1beta2.ApplicationState{
+					State:        state,
+					ErrorMessage: message,
+				}
+				if state == v1beta2.ApplicationStateFailed {
+					app.Status.FailureReason = v1beta2.FailureReasonUpstreamFailed
+				}
+				app.Status.TerminationTime = metav1.Now()
+				r.recordSparkApplicationEvent(app)
+			default:
+				waiting = true
+				if app.Status.AppState.ErrorMessage == message {
+					return nil
+				}
+				app.Status.AppState.ErrorMessage = message
+			}
+			return r.updateSparkApplicationStatus(ctx, app)
+		},
+	)
+	if retryErr != nil {
+		logger.Error(retryErr, "Failed to reconcile SparkApplication", "name", key.Name, "namespace", key.Namespace)
+		return ctrl.Result{Requeue: true}, retryErr
+	}
+	if waiting {
+		return ctrl.Result{RequeueAfter: dependencyRetryInterval}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *Reconciler) reconcileQueuedSparkApplication(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	key := req.NamespacedName
+	waiting := false
+	retryErr := retry.RetryOnConflict(
+		retry.DefaultRetry,
+		func() error {
+			old, err := r.getSparkApplication(key)
+			if err != nil {
+				return err
+			}
+			if old.Status.AppState.State != v1beta2.ApplicationStateQueued {
+				return nil
+			}
+			app := old.DeepCopy()
+
+			// The application is admitted directly if the admission queue has been disabled since it was queued.
+			admitted := true
+			var message string
+			if r.options.EnableAdmissionQueue {
+				admitted, message, err = r.admitSparkApplication(ctx, app)
+				if err != nil {
+					return err
+				}
+			}
+			if admitted {
+				admitted, message, err = r.admitSparkApplicationByBatchScheduler(app)
+				if err != nil {
+					return err
+				}
+			}
+			if app.Status.AppState.State != v1beta2.ApplicationStateQueued {
+				// The application was rejected by its batch scheduler.
+				return r.updateSparkApplicationStatus(ctx, app)
+			}
+			waiting = !admitted
+			if !admitted {
+				app.Status.AppState.ErrorMessage = message
+				if equality.Semantic.DeepEqual(old.Status, app.Status) {
+					return nil
+				}
+				return r.updateSparkApplicationStatus(ctx, app)
+			}
+
+			logger.Info("Admitted SparkApplication", "name", app.Name, "namespace", app.Namespace)
+			r.recorder.Eventf(
+				app,
+				corev1.EventTypeNormal,
+				common.EventSparkApplicationAdmitted,
+				"SparkApplication %s was admitted for submission",
+				app.Name,
+			)
+			r.enqueueSparkApplicationSubmission(ctx, app)
+			return r.updateSparkApplicationStatus(ctx, app)
+		},
+	)
+	if retryErr != nil {
+		logger.Error(retryErr, "Failed to reconcile SparkApplication", "name", key.Name, "namespace", key.Namespace)
+		return ctrl.Result{Requeue: true}, retryErr
+	}
+	if waiting {
+		return ctrl.Result{RequeueAfter: admissionRetryInterval}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *Reconciler) reconcilePendingSubmissionSparkApplication(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	key := req.NamespacedName
+
+	// The application is submitted by the submission workers, so it only needs to be (re-)enqueued here,
+	// e.g. after the operator restarts.
+	if r.submissionPool != nil {
+		r.submissionPool.enqueue(key)
+		return ctrl.Result{}, nil
+	}
+
+	// Asynchronous submission has been disabled since the application was enqueued, so submit it directly.
+	retryErr := retry.RetryOnConflict(
+		retry.DefaultRetry,
+		func() error {
+			old, err := r.getSparkApplication(key)
+			if err != nil {
+				return err
+			}
+			if old.Status.AppState.State != v1beta2.ApplicationStatePendingSubmission {
+				return nil
+			}
+			app := old.DeepCopy()
+
+			_ = r.submitSparkApplication(ctx, app)
 			if err := r.updateSparkApplicationStatus(ctx, app); err != nil {
 				return err
 			}
@@ -277,6 +505,9 @@ func (r *Reconciler) reconcileNewSparkApplication(ctx context.Context, req ctrl.
 
 func (r *Reconciler) reconcileSubmittedSparkApplication(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	key := req.NamespacedName
+
+	var result ctrl.Result
+
 	retryErr := retry.RetryOnConflict(
 		retry.DefaultRetry,
 		func() error {
@@ -289,9 +520,36 @@ func (r *Reconciler) reconcileSubmittedSparkApplication(ctx context.Context, req
 			}
 			app := old.DeepCopy()
 
+			evicted, err := r.checkSparkApplicationEviction(ctx, app)
+			if err != nil {
+				return err
+			}
+			if evicted {
+				return r.updateSparkApplicationStatus(ctx, app)
+			}
+
+			exceeded, timeUntilDeadline, err := r.checkSparkApplicationDeadline(ctx, app)
+			if err != nil {
+				return err
+			}
+			if exceeded {
+				return r.updateSparkApplicationStatus(ctx, app)
+			}
+			// Nothing else triggers a reconciliation when the deadline is reached, so we need to requeue.
+			result.RequeueAfter = timeUntilDeadline
+
 			if err := r.updateSparkApplicationState(ctx, app); err != nil {
 				return err
 			}
+
+			stuck, timeUntilAction, err := r.checkSparkApplicationStuckPending(ctx, app)
+			if err != nil {
+				return err
+			}
+			if !stuck {
+				result.RequeueAfter = minRequeueAfter(result.RequeueAfter, timeUntilAction)
+			}
+
 			if err := r.updateSparkApplicationStatus(ctx, app); err != nil {
 				return err
 			}
@@ -300,9 +558,9 @@ func (r *Reconciler) reconcileSubmittedSparkApplication(ctx context.Context, req
 	)
 	if retryErr != nil {
 		logger.Error(retryErr, "Failed to reconcile SparkApplication", "name", key.Name, "namespace", key.Namespace)
-		return ctrl.Result{}, retryErr
+		return result, retryErr
 	}
-	return ctrl.Result{}, nil
+	return result, nil
 }
 
 func (r *Reconciler) reconcileFailedSubmissionSparkApplication(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -323,13 +581,14 @@ func (r *Reconciler) reconcileFailedSubmissionSparkApplication(ctx context.Conte
 			app := old.DeepCopy()
 
 			if util.ShouldRetry(app) {
-				timeUntilNextRetryDue, err := util.TimeUntilNextRetryDue(app)
+				timeUntilNextRetryDue, err := r.scheduleRetry(app)
 				if err != nil {
 					return err
 				}
 				if timeUntilNextRetryDue <= 0 {
+					app.Status.NextRetryTime = metav1.Time{}
 					if r.validateSparkResourceDeletion(ctx, app) {
-						_ = r.submitSparkApplication(app)
+						r.requestSparkApplicationAdmission(ctx, app)
 					} else {
 						if err := r.deleteSparkResources(ctx, app); err != nil {
 							logger.Error(err, "failed to delete resources associated with SparkApplication", "name", app.Name, "namespace", app.Namespace)
@@ -361,6 +620,9 @@ func (r *Reconciler) reconcileFailedSubmissionSparkApplication(ctx context.Conte
 
 func (r *Reconciler) reconcileRunningSparkApplication(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	key := req.NamespacedName
+
+	var result ctrl.Result
+
 	retryErr := retry.RetryOnConflict(
 		retry.DefaultRetry,
 		func() error {
@@ -373,10 +635,42 @@ func (r *Reconciler) reconcileRunningSparkApplication(ctx context.Context, req c
 			}
 			app := old.DeepCopy()
 
+			evicted, err := r.checkSparkApplicationEviction(ctx, app)
+			if err != nil {
+				return err
+			}
+			if evicted {
+				return r.updateSparkApplicationStatus(ctx, app)
+			}
+
+			exceeded, timeUntilDeadline, err := r.checkSparkApplicationDeadline(ctx, app)
+			if err != nil {
+				return err
+			}
+			if exceeded {
+				return r.updateSparkApplicationStatus(ctx, app)
+			}
+			// Nothing else triggers a reconciliation when the deadline is reached, so we need to requeue.
+			result.RequeueAfter = timeUntilDeadline
+
 			if err := r.updateSparkApplicationState(ctx, app); err != nil {
 				return err
 			}
 
+			if util.IsDriverRunning(app) {
+				if err := r.limitSparkApplicationExecutors(ctx, app); err != nil {
+					return err
+				}
+			}
+
+			stuck, timeUntilAction, err := r.checkSparkApplicationStuckPending(ctx, app)
+			if err != nil {
+				return err
+			}
+			if !stuck {
+				result.RequeueAfter = minRequeueAfter(result.RequeueAfter, timeUntilAction)
+			}
+
 			if err := r.updateSparkApplicationStatus(ctx, app); err != nil {
 				return err
 			}
@@ -386,9 +680,9 @@ func (r *Reconciler) reconcileRunningSparkApplication(ctx context.Context, req c
 	)
 	if retryErr != nil {
 		logger.Error(retryErr, "Failed to reconcile SparkApplication", "name", key.Name, "namespace", key.Namespace)
-		return ctrl.Result{}, retryErr
+		return result, retryErr
 	}
-	return ctrl.Result{}, nil
+	return result, nil
 }
 
 func (r *Reconciler) reconcilePendingRerunSparkApplication(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -410,7 +704,7 @@ func (r *Reconciler) reconcilePendingRerunSparkApplication(ctx context.Context,
 				logger.Info("Successfully deleted resources associated with SparkApplication", "name", app.Name, "namespace", app.Namespace, "state", app.Status.AppState.State)
 				r.recordSparkApplicationEvent(app)
 				r.resetSparkApplicationStatus(app)
-				_ = r.submitSparkApplication(app)
+				r.startSparkApplication(ctx, app)
 			}
 			if err := r.updateSparkApplicationStatus(ctx, app); err != nil {
 				return err
@@ -442,9 +736,12 @@ func (r *Reconciler) reconcileInvalidatingSparkApplication(ctx context.Context,
 			// Invalidate the current run and enqueue the SparkApplication for re-execution.
 			if err := r.deleteSparkResources(ctx, app); err != nil {
 				logger.Error(err, "Failed to delete resources associated with SparkApplication", "name", app.Name, "namespace", app.Namespace)
+			} else if util.IsSuspended(app) {
+				r.resetSparkApplicationStatus(app)
+				r.suspendSparkApplication(app)
 			} else {
 				r.resetSparkApplicationStatus(app)
-				app.Status.AppState.State = v1beta2.ApplicationStatePendingRerun
+				markPendingRerun(app, v1beta2.ApplicationStateReasonSpecUpdated)
 			}
 			if err := r.updateSparkApplicationStatus(ctx, app); err != nil {
 				return err
@@ -478,7 +775,8 @@ func (r *Reconciler) reconcileSucceedingSparkApplication(ctx context.Context, re
 					logger.Error(err, "failed to delete spark resources", "name", app.Name, "namespace", app.Namespace)
 					return err
 				}
-				app.Status.AppState.State = v1beta2.ApplicationStatePendingRerun
+				r.recordServerRestart(app)
+				markPendingRerun(app, v1beta2.ApplicationStateReasonRestartingSuccess)
 			} else {
 				app.Status.AppState.State = v1beta2.ApplicationStateCompleted
 			}
@@ -513,16 +811,18 @@ func (r *Reconciler) reconcileFailingSparkApplication(ctx context.Context, req c
 			app := old.DeepCopy()
 
 			if util.ShouldRetry(app) {
-				timeUntilNextRetryDue, err := util.TimeUntilNextRetryDue(app)
+				timeUntilNextRetryDue, err := r.scheduleRetry(app)
 				if err != nil {
 					return err
 				}
 				if timeUntilNextRetryDue <= 0 {
+					app.Status.NextRetryTime = metav1.Time{}
 					if err := r.deleteSparkResources(ctx, app); err != nil {
 						logger.Error(err, "failed to delete spark resources", "name", app.Name, "namespace", app.Namespace)
 						return err
 					}
-					app.Status.AppState.State = v1beta2.ApplicationStatePendingRerun
+					r.recordServerRestart(app)
+					markPendingRerun(app, v1beta2.ApplicationStateReasonRetryingFailure)
 				} else {
 					// If we're waiting before retrying then reconcile will not modify anything, so we need to requeue.
 					result.RequeueAfter = timeUntilNextRetryDue
@@ -551,6 +851,10 @@ func (r *Reconciler) reconcileFailedSparkApplication(ctx context.Context, req ct
 	return r.reconcileTerminatedSparkApplication(ctx, req)
 }
 
+func (r *Reconciler) reconcileSkippedSparkApplication(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return r.reconcileTerminatedSparkApplication(ctx, req)
+}
+
 func (r *Reconciler) reconcileTerminatedSparkApplication(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	key := req.NamespacedName
 	old, err := r.getSparkApplication(key)
@@ -641,8 +945,23 @@ func (r *Reconciler) getSparkApplication(key types.NamespacedName) (*v1beta2.Spa
 	return app, nil
 }
 
-// submitSparkApplication creates a new submission for the given SparkApplication and submits it using spark-submit.
-func (r *Reconciler) submitSparkApplication(app *v1beta2.SparkApplication) (submitErr error) {
+// enqueueSparkApplicationSubmission is the last stage of starting an admitted SparkApplication. It moves the
+// application to the PendingSubmission state to be submitted by the submission workers, or submits it right
+// away if SparkApplications are submitted synchronously.
+func (r *Reconciler) enqueueSparkApplicationSubmission(ctx context.Context, app *v1beta2.SparkApplication) {
+	if r.submissionPool == nil {
+		_ = r.submitSparkApplication(ctx, app)
+		return
+	}
+
+	app.Status.AppState = v1beta2.ApplicationState{
+		State: v1beta2.ApplicationStatePendingSubmission,
+	}
+	r.recordSparkApplicationEvent(app)
+}
+
+// submitSparkApplication creates a new submission for the given SparkApplication and submits it using the configured submitter.
+func (r *Reconciler) submitSparkApplication(ctx context.Context, app *v1beta2.SparkApplication) (submitErr error) {
 	logger.Info("Submitting SparkApplication", "name", app.Name, "namespace", app.Namespace, "state", app.Status.AppState.State)
 
 	// SubmissionID must be set before creating any resources to ensure all the resources are labeled.
@@ -650,6 +969,11 @@ func (r *Reconciler) submitSparkApplication(app *v1beta2.SparkApplication) (subm
 	app.Status.DriverInfo.PodName = util.GetDriverPodName(app)
 	app.Status.LastSubmissionAttemptTime = metav1.Now()
 	app.Status.SubmissionAttempts = app.Status.SubmissionAttempts + 1
+	app.Status.FailureReason = ""
+	app.Status.LastSubmissionAttempt = &v1beta2.SubmissionAttemptInfo{
+		Attempt:   app.Status.SubmissionAttempts,
+		Submitter: r.getSubmitterType(app),
+	}
 
 	defer func() {
 		if submitErr == nil {
@@ -663,6 +987,7 @@ func (r *Reconciler) submitSparkApplication(app *v1beta2.SparkApplication) (subm
 				State:        v1beta2.ApplicationStateFailedSubmission,
 				ErrorMessage: submitErr.Error(),
 			}
+			app.Status.FailureReason = v1beta2.FailureReasonSubmissionError
 		}
 		r.recordSparkApplicationEvent(app)
 	}()
@@ -739,26 +1064,49 @@ func (r *Reconciler) submitSparkApplication(app *v1beta2.SparkApplication) (subm
 		}
 	}
 
+	if app.Spec.Server != nil {
+		if err := r.createServerResources(ctx, app); err != nil {
+			return err
+		}
+	}
+
 	defer func() {
 		if err := r.cleanUpPodTemplateFiles(app); err != nil {
 			logger.Error(fmt.Errorf("failed to clean up pod template files: %v", err), "name", app.Name, "namespace", app.Namespace)
 		}
 	}()
 
-	sparkSubmitArgs, err := buildSparkSubmitArgs(app)
+	submitter, err := r.getSubmitter(app)
 	if err != nil {
-		return fmt.Errorf("failed to build spark-submit arguments: %v", err)
+		return err
+	}
+
+	timeout := r.getSubmissionTimeout(app)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
 	}
 
-	// Try submitting the application by running spark-submit.
-	logger.Info("Running spark-submit for SparkApplication", "name", app.Name, "namespace", app.Namespace, "arguments", sparkSubmitArgs)
-	if err := runSparkSubmit(newSubmission(sparkSubmitArgs, app)); err != nil {
+	if err := submitter.Submit(ctx, app); err != nil {
 		r.recordSparkApplicationEvent(app)
-		return fmt.Errorf("failed to run spark-submit: %v", err)
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("submission timed out after %v: %v", timeout, err)
+		}
+		return err
 	}
 	return nil
 }
 
+// getSubmissionTimeout returns the submission timeout of the given SparkApplication. The timeout specified
+// in the application spec takes precedence over the default submission timeout of the controller.
+func (r *Reconciler) getSubmissionTimeout(app *v1beta2.SparkApplication) time.Duration {
+	if app.Spec.SubmissionTimeoutSeconds != nil {
+		return time.Duration(*app.Spec.SubmissionTimeoutSeconds) * time.Second
+	}
+	return r.options.SubmissionTimeout
+}
+
 // updateDriverState finds the driver pod of the application
 // and updates the driver state based on the current phase of the pod.
 func (r *Reconciler) updateDriverState(_ context.Context, app *v1beta2.SparkApplication) error {
@@ -772,9 +1120,12 @@ func (r *Reconciler) updateDriverState(_ context.Context, app *v1beta2.SparkAppl
 		return err
 	}
 
+	r.updateServerHealth(app, driverPod)
+
 	if driverPod == nil {
 		app.Status.AppState.State = v1beta2.ApplicationStateFailing
 		app.Status.AppState.ErrorMessage = "driver pod not found"
+		app.Status.FailureReason = v1beta2.FailureReasonUnknown
 		app.Status.TerminationTime = metav1.Now()
 		return nil
 	}
@@ -786,6 +1137,7 @@ func (r *Reconciler) updateDriverState(_ context.Context, app *v1beta2.SparkAppl
 			app.Status.TerminationTime = metav1.Now()
 		}
 		if driverState == v1beta2.DriverStateFailed {
+			app.Status.FailureReason = util.GetDriverFailureReason(app, driverPod)
 			if state := util.GetDriverContainerTerminatedState(driverPod); state != nil {
 				if state.ExitCode != 0 {
 					app.Status.AppState.ErrorMessage = fmt.Sprintf("driver container failed with ExitCode: %d, Reason: %s", state.ExitCode, state.Reason)
@@ -802,6 +1154,7 @@ func (r *Reconciler) updateDriverState(_ context.Context, app *v1beta2.SparkAppl
 		r.recordDriverEvent(app, driverState, driverPod.Name)
 		app.Status.AppState.State = newState
 	}
+	setSparkApplicationReadyCondition(app, driverPod)
 
 	return nil
 }
@@ -881,6 +1234,9 @@ func (r *Reconciler) updateExecutorState(_ context.Context, app *v1beta2.SparkAp
 		}
 	}
 
+	app.Status.ExecutorReplicas = util.GetExecutorReplicas(app)
+	app.Status.ExecutorSelector = util.GetExecutorSelector(app)
+
 	return nil
 }
 
@@ -924,6 +1280,10 @@ func (r *Reconciler) updateSparkApplicationState(ctx context.Context, app *v1bet
 
 // updateSparkApplicationStatus updates the status of the SparkApplication.
 func (r *Reconciler) updateSparkApplicationStatus(ctx context.Context, app *v1beta2.SparkApplication) error {
+	// The conditions and the attempt history are set here, as all the state transitions of the application are
+	// persisted through here.
+	setSparkApplicationConditions(app)
+	r.recordSparkApplicationAttempt(app)
 	if err := r.client.Status().Update(ctx, app); err != nil {
 		return err
 	}
@@ -947,7 +1307,7 @@ func (r *Reconciler) deleteSparkResources(ctx context.Context, app *v1beta2.Spar
 	return nil
 }
 
-func (r *Reconciler) deleteDriverPod(ctx context.Context, app *v1beta2.SparkApplication) error {
+func (r *Reconciler) deleteDriverPod(ctx context.Context, app *v1beta2.SparkApplication, opts ...client.DeleteOption) error {
 	podName := app.Status.DriverInfo.PodName
 	// Derive the driver pod name in case the driver pod name was not recorded in the status,
 	// which could happen if the status update right after submission failed.
@@ -964,6 +1324,7 @@ func (r *Reconciler) deleteDriverPod(ctx context.Context, app *v1beta2.SparkAppl
 				Namespace: app.Namespace,
 			},
 		},
+		opts...,
 	); err != nil && !errors.IsNotFound(err) {
 		return err
 	}
@@ -1081,6 +1442,32 @@ func (r *Reconciler) recordSparkApplicationEvent(app *v1beta2.SparkApplication)
 			"SparkApplication %s was added, enqueuing it for submission",
 			app.Name,
 		)
+	case v1beta2.ApplicationStateWaiting:
+		r.recorder.Eventf(
+			app,
+			corev1.EventTypeNormal,
+			common.EventSparkApplicationWaiting,
+			"SparkApplication %s is waiting for upstream SparkApplications %s to complete",
+			app.Name,
+			strings.Join(app.Spec.DependsOn, ", "),
+		)
+	case v1beta2.ApplicationStateQueued:
+		r.recorder.Eventf(
+			app,
+			corev1.EventTypeNormal,
+			common.EventSparkApplicationQueued,
+			"SparkApplication %s is queued waiting for capacity in namespace %s",
+			app.Name,
+			app.Namespace,
+		)
+	case v1beta2.ApplicationStatePendingSubmission:
+		r.recorder.Eventf(
+			app,
+			corev1.EventTypeNormal,
+			common.EventSparkApplicationPendingSubmission,
+			"SparkApplication %s is queued for submission",
+			app.Name,
+		)
 	case v1beta2.ApplicationStateSubmitted:
 		r.recorder.Eventf(
 			app,
@@ -1115,12 +1502,35 @@ func (r *Reconciler) recordSparkApplicationEvent(app *v1beta2.SparkApplication)
 			app.Name,
 			app.Status.AppState.ErrorMessage,
 		)
-	case v1beta2.ApplicationStatePendingRerun:
+	case v1beta2.ApplicationStateSkipped:
 		r.recorder.Eventf(
+			app,
+			corev1.EventTypeNormal,
+			common.EventSparkApplicationSkipped,
+			"SparkApplication %s was skipped: %s",
+			app.Name,
+			app.Status.AppState.ErrorMessage,
+		)
+	case v1beta2.ApplicationStatePendingRerun:
+		message := fmt.Sprintf("SparkApplication %s is pending rerun", app.Name)
+		if app.Status.AppState.Reason != "" {
+			message += fmt.Sprintf(" (%s)", app.Status.AppState.Reason)
+		}
+		if app.Status.AppState.ErrorMessage != "" {
+			message += ": " + app.Status.AppState.ErrorMessage
+		}
+		r.recorder.Event(
 			app,
 			corev1.EventTypeWarning,
 			common.EventSparkApplicationPendingRerun,
-			"SparkApplication %s is pending rerun",
+			message,
+		)
+	case v1beta2.ApplicationStateSuspended:
+		r.recorder.Eventf(
+			app,
+			corev1.EventTypeNormal,
+			common.EventSparkApplicationSuspended,
+			"SparkApplication %s is suspended",
 			app.Name,
 		)
 	}
@@ -1161,19 +1571,35 @@ func (r *Reconciler) resetSparkApplicationStatus(app *v1beta2.SparkApplication)
 	switch status.AppState.State {
 	case v1beta2.ApplicationStateInvalidating:
 		status.SparkApplicationID = ""
+		status.BatchSchedulerStatus = nil
 		status.SubmissionAttempts = 0
 		status.ExecutionAttempts = 0
 		status.LastSubmissionAttemptTime = metav1.Time{}
+		status.LastSubmissionAttempt = nil
+		status.NextRetryTime = metav1.Time{}
 		status.TerminationTime = metav1.Time{}
+		status.AppState.Reason = ""
 		status.AppState.ErrorMessage = ""
+		status.FailureReason = ""
 		status.ExecutorState = nil
-	case v1beta2.ApplicationStatePendingRerun:
+		status.ExecutorReplicas = 0
+		status.ExecutorSelector = ""
+		status.ExecutorLimit = nil
+	case v1beta2.ApplicationStatePendingRerun, v1beta2.ApplicationStateQueued:
 		status.SparkApplicationID = ""
+		status.BatchSchedulerStatus = nil
 		status.SubmissionAttempts = 0
 		status.LastSubmissionAttemptTime = metav1.Time{}
+		status.LastSubmissionAttempt = nil
+		status.NextRetryTime = metav1.Time{}
 		status.DriverInfo = v1beta2.DriverInfo{}
+		status.AppState.Reason = ""
 		status.AppState.ErrorMessage = ""
+		status.FailureReason = ""
 		status.ExecutorState = nil
+		status.ExecutorReplicas = 0
+		status.ExecutorSelector = ""
+		status.ExecutorLimit = nil
 	}
 }
 
@@ -1204,6 +1630,11 @@ func (r *Reconciler) shouldDoBatchScheduling(app *v1beta2.SparkApplication) (boo
 		scheduler, err = r.registry.GetScheduler(schedulerName, config)
 	case yunikorn.SchedulerName:
 		scheduler, err = r.registry.GetScheduler(schedulerName, nil)
+	case kueue.SchedulerName:
+		config := &kueue.Config{
+			Client: r.manager.GetClient(),
+		}
+		scheduler, err = r.registry.GetScheduler(schedulerName, config)
 	}
 
 	for _, name := range r.options.KubeSchedulerNames {
@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+)
+
+// checkSparkApplicationDeadline checks whether the current attempt of the given submitted or running
+// SparkApplication has exceeded its pending or active deadline, and returns the time left until the next one
+// otherwise, or zero if there is none. An attempt which exceeded its deadline is torn down and moved to the
+// Failing state, from which it is retried only if its restart policy asks for it.
+func (r *Reconciler) checkSparkApplicationDeadline(ctx context.Context, app *v1beta2.SparkApplication) (bool, time.Duration, error) {
+	submissionTime := app.Status.LastSubmissionAttemptTime
+	if submissionTime.IsZero() {
+		return false, 0, nil
+	}
+
+	var message string
+	var timeUntilDeadline time.Duration
+	checkDeadline := func(seconds *int64, format string) {
+		if seconds == nil || message != "" {
+			return
+		}
+		remaining := time.Until(submissionTime.Add(time.Duration(*seconds) * time.Second))
+		if remaining <= 0 {
+			message = fmt.Sprintf(format, *seconds)
+		} else if timeUntilDeadline == 0 || remaining < timeUntilDeadline {
+			timeUntilDeadline = remaining
+		}
+	}
+	if app.Status.AppState.State == v1beta2.ApplicationStateSubmitted {
+		checkDeadline(app.Spec.PendingDeadlineSeconds, "driver did not start running within the pending deadline of %d seconds")
+	}
+	checkDeadline(app.Spec.ActiveDeadlineSeconds, "application did not complete within the active deadline of %d seconds")
+	if message == "" {
+		return false, timeUntilDeadline, nil
+	}
+
+	logger.Info("SparkApplication exceeded its deadline", "name", app.Name, "namespace", app.Namespace, "message", message)
+	if err := r.deleteSparkResources(ctx, app); err != nil {
+		return false, 0, fmt.Errorf("failed to delete resources associated with SparkApplication: %v", err)
+	}
+	r.recorder.Eventf(
+		app,
+		corev1.EventTypeWarning,
+		common.EventSparkApplicationDeadlineExceeded,
+		"SparkApplication %s was killed: %s",
+		app.Name,
+		message,
+	)
+	app.Status.AppState = v1beta2.ApplicationState{
+		State:        v1beta2.ApplicationStateFailing,
+		Reason:       v1beta2.ApplicationStateReasonDeadlineExceeded,
+		ErrorMessage: message,
+	}
+	app.Status.FailureReason = v1beta2.FailureReasonDeadlineExceeded
+	app.Status.TerminationTime = metav1.Now()
+	return true, 0, nil
+}
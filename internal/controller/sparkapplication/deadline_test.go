@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+func TestCheckSparkApplicationDeadline(t *testing.T) {
+	testCases := []struct {
+		name            string
+		state           v1beta2.ApplicationStateType
+		activeDeadline  *int64
+		pendingDeadline *int64
+		exceeded        bool
+		requeueAtMost   time.Duration
+		requeueAtLeast  time.Duration
+	}{
+		{name: "no deadline", state: v1beta2.ApplicationStateRunning},
+		{name: "active deadline not exceeded", state: v1beta2.ApplicationStateRunning, activeDeadline: util.Int64Ptr(600), requeueAtLeast: 500 * time.Second, requeueAtMost: 540 * time.Second},
+		{name: "active deadline exceeded", state: v1beta2.ApplicationStateRunning, activeDeadline: util.Int64Ptr(30), exceeded: true},
+		{name: "pending deadline exceeded", state: v1beta2.ApplicationStateSubmitted, activeDeadline: util.Int64Ptr(600), pendingDeadline: util.Int64Ptr(30), exceeded: true},
+		{name: "pending deadline of running application", state: v1beta2.ApplicationStateRunning, pendingDeadline: util.Int64Ptr(30)},
+		{name: "earliest deadline", state: v1beta2.ApplicationStateSubmitted, activeDeadline: util.Int64Ptr(600), pendingDeadline: util.Int64Ptr(120), requeueAtLeast: 30 * time.Second, requeueAtMost: 60 * time.Second},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := newAdmissionTestApp("app", tc.state, time.Now())
+			app.Spec.ActiveDeadlineSeconds = tc.activeDeadline
+			app.Spec.PendingDeadlineSeconds = tc.pendingDeadline
+			app.Status.LastSubmissionAttemptTime = metav1.NewTime(time.Now().Add(-time.Minute))
+			r := newAdmissionTestReconciler(t, Options{})
+			r.recorder = record.NewFakeRecorder(10)
+
+			exceeded, timeUntilDeadline, err := r.checkSparkApplicationDeadline(context.TODO(), app)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.exceeded, exceeded)
+			if tc.exceeded {
+				assert.Equal(t, v1beta2.ApplicationStateFailing, app.Status.AppState.State)
+				assert.Equal(t, v1beta2.ApplicationStateReasonDeadlineExceeded, app.Status.AppState.Reason)
+				assert.Equal(t, v1beta2.FailureReasonDeadlineExceeded, app.Status.FailureReason)
+				assert.False(t, app.Status.TerminationTime.IsZero())
+				return
+			}
+			assert.Equal(t, tc.state, app.Status.AppState.State)
+			assert.GreaterOrEqual(t, timeUntilDeadline, tc.requeueAtLeast)
+			assert.LessOrEqual(t, timeUntilDeadline, tc.requeueAtMost)
+		})
+	}
+}
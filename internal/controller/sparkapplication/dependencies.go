@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+)
+
+const (
+	// dependencyRetryInterval is the interval at which the upstream SparkApplications of waiting SparkApplications
+	// are checked.
+	dependencyRetryInterval = 10 * time.Second
+
+	// missingUpstreamTimeout is how long after its creation a SparkApplication waits for its missing upstream
+	// SparkApplications to be created, e.g. by the same ScheduledSparkApplication run, before it fails.
+	missingUpstreamTimeout = 5 * time.Minute
+)
+
+// startSparkApplication is the first stage of starting a new or rerun SparkApplication. It moves the
+// application to the Waiting state if it depends on other applications, or requests its admission otherwise.
+func (r *Reconciler) startSparkApplication(ctx context.Context, app *v1beta2.SparkApplication) {
+	if len(app.Spec.DependsOn) == 0 {
+		r.requestSparkApplicationAdmission(ctx, app)
+		return
+	}
+
+	app.Status.AppState = v1beta2.ApplicationState{
+		State: v1beta2.ApplicationStateWaiting,
+	}
+	r.recordSparkApplicationEvent(app)
+}
+
+// getUpstreamState returns the aggregated state of the SparkApplications the given SparkApplication depends on,
+// along with a message describing it. The state is COMPLETED if all of them have completed, FAILED if one of
+// them has failed or has been skipped, or if some of them still do not exist missingUpstreamTimeout after the
+// creation of the application, and WAITING otherwise.
+func (r *Reconciler) getUpstreamState(ctx context.Context, app *v1beta2.SparkApplication) (v1beta2.ApplicationStateType, string, error) {
+	var pending []string
+	var missing []string
+	for _, name := range app.Spec.DependsOn {
+		upstream := &v1beta2.SparkApplication{}
+		if err := r.client.Get(ctx, types.NamespacedName{Namespace: app.Namespace, Name: name}, upstream); err != nil {
+			if errors.IsNotFound(err) {
+				missing = append(missing, name)
+				continue
+			}
+			return "", "", fmt.Errorf("failed to get upstream SparkApplication %s: %v", name, err)
+		}
+
+		switch upstream.Status.AppState.State {
+		case v1beta2.ApplicationStateCompleted:
+		case v1beta2.ApplicationStateFailed, v1beta2.ApplicationStateSkipped:
+			return v1beta2.ApplicationStateFailed, fmt.Sprintf("upstream SparkApplication %s is in state %s", name, upstream.Status.AppState.State), nil
+		default:
+			pending = append(pending, name)
+		}
+	}
+
+	if len(missing) > 0 && time.Since(app.CreationTimestamp.Time) > missingUpstreamTimeout {
+		return v1beta2.ApplicationStateFailed, fmt.Sprintf("upstream SparkApplications %s do not exist", strings.Join(missing, ", ")), nil
+	}
+
+	var messages []string
+	if len(pending) > 0 {
+		messages = append(messages, fmt.Sprintf("waiting for upstream SparkApplications %s to complete", strings.Join(pending, ", ")))
+	}
+	if len(missing) > 0 {
+		messages = append(messages, fmt.Sprintf("waiting for upstream SparkApplications %s to be created", strings.Join(missing, ", ")))
+	}
+	if len(messages) > 0 {
+		return v1beta2.ApplicationStateWaiting, strings.Join(messages, ", "), nil
+	}
+	return v1beta2.ApplicationStateCompleted, "", nil
+}
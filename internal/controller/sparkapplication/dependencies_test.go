@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+)
+
+func TestGetUpstreamState(t *testing.T) {
+	extract := newAdmissionTestApp("extract", v1beta2.ApplicationStateCompleted, time.Now())
+	enrich := newAdmissionTestApp("enrich", v1beta2.ApplicationStateRunning, time.Now())
+	report := newAdmissionTestApp("report", v1beta2.ApplicationStateWaiting, time.Now())
+	report.Spec.DependsOn = []string{"extract", "enrich", "reference-data"}
+
+	r := newAdmissionTestReconciler(t, Options{}, extract, enrich, report)
+	state, message, err := r.getUpstreamState(context.TODO(), report)
+	assert.Nil(t, err)
+	assert.Equal(t, v1beta2.ApplicationStateWaiting, state)
+	assert.Equal(t, "waiting for upstream SparkApplications enrich to complete, waiting for upstream SparkApplications reference-data to be created", message)
+
+	enrich.Status.AppState.State = v1beta2.ApplicationStateCompleted
+	report.Spec.DependsOn = []string{"extract", "enrich"}
+	r = newAdmissionTestReconciler(t, Options{}, extract, enrich, report)
+	state, _, err = r.getUpstreamState(context.TODO(), report)
+	assert.Nil(t, err)
+	assert.Equal(t, v1beta2.ApplicationStateCompleted, state)
+
+	enrich.Status.AppState.State = v1beta2.ApplicationStateSkipped
+	r = newAdmissionTestReconciler(t, Options{}, extract, enrich, report)
+	state, message, err = r.getUpstreamState(context.TODO(), report)
+	assert.Nil(t, err)
+	assert.Equal(t, v1beta2.ApplicationStateFailed, state)
+	assert.Equal(t, "upstream SparkApplication enrich is in state SKIPPED", message)
+}
+
+func TestGetUpstreamStateMissingUpstream(t *testing.T) {
+	extract := newAdmissionTestApp("extract", v1beta2.ApplicationStateCompleted, time.Now())
+	report := newAdmissionTestApp("report", v1beta2.ApplicationStateWaiting, time.Now().Add(-time.Minute))
+	report.Spec.DependsOn = []string{"extract", "reference-data"}
+
+	// Missing upstreams may still be created shortly after the application.
+	r := newAdmissionTestReconciler(t, Options{}, extract, report)
+	state, message, err := r.getUpstreamState(context.TODO(), report)
+	assert.Nil(t, err)
+	assert.Equal(t, v1beta2.ApplicationStateWaiting, state)
+	assert.Equal(t, "waiting for upstream SparkApplications reference-data to be created", message)
+
+	report.CreationTimestamp.Time = time.Now().Add(-missingUpstreamTimeout - time.Minute)
+	r = newAdmissionTestReconciler(t, Options{}, extract, report)
+	state, message, err = r.getUpstreamState(context.TODO(), report)
+	assert.Nil(t, err)
+	assert.Equal(t, v1beta2.ApplicationStateFailed, state)
+	assert.Equal(t, "upstream SparkApplications reference-data do not exist", message)
+}
@@ -18,6 +18,8 @@ package sparkapplication
 
 import (
 	"context"
+	"slices"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -28,6 +30,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
 	"github.com/kubeflow/spark-operator/pkg/util"
 )
 
@@ -77,13 +80,22 @@ func (f *sparkPodEventFilter) Update(e event.UpdateEvent) bool {
 		return false
 	}
 
-	if newPod.Status.Phase == oldPod.Status.Phase {
+	if !sparkPodStatusChanged(oldPod, newPod) {
 		return false
 	}
 
 	return f.filter(newPod)
 }
 
+// sparkPodStatusChanged tells whether the status of a Spark pod has changed in a way the SparkApplication
+// controller needs to react to, i.e. its phase, or the readiness of the driver, which tells the health of servers.
+func sparkPodStatusChanged(oldPod, newPod *corev1.Pod) bool {
+	if newPod.Status.Phase != oldPod.Status.Phase {
+		return true
+	}
+	return util.IsDriverPod(newPod) && util.IsPodReady(newPod) != util.IsPodReady(oldPod)
+}
+
 // Delete implements predicate.Predicate.
 func (f *sparkPodEventFilter) Delete(e event.DeleteEvent) bool {
 	pod, ok := e.Object.(*corev1.Pod)
@@ -170,9 +182,16 @@ func (f *EventFilter) Update(e event.UpdateEvent) bool {
 	// The spec has changed. This is currently best effort as we can potentially miss updates
 	// and end up in an inconsistent state.
 	if !equality.Semantic.DeepEqual(oldApp.Spec, newApp.Spec) {
+		restartingChanges := util.GetRestartingSpecChanges(oldApp, newApp)
+		if len(restartingChanges) == 0 {
+			f.applyLiveSpecChanges(oldApp, newApp)
+			return true
+		}
+
 		// Force-set the application status to Invalidating which handles clean-up and application re-run.
 		newApp.Status.AppState.State = v1beta2.ApplicationStateInvalidating
-		logger.Info("Updating SparkApplication status", "name", newApp.Name, "namespace", newApp.Namespace, " oldState", oldApp.Status.AppState.State, "newState", newApp.Status.AppState.State)
+		setSparkApplicationConditions(newApp)
+		logger.Info("Updating SparkApplication status", "name", newApp.Name, "namespace", newApp.Namespace, " oldState", oldApp.Status.AppState.State, "newState", newApp.Status.AppState.State, "changes", restartingChanges)
 		if err := f.client.Status().Update(context.TODO(), newApp); err != nil {
 			logger.Error(err, "Failed to update application status", "application", newApp.Name)
 			f.recorder.Eventf(
@@ -190,6 +209,80 @@ func (f *EventFilter) Update(e event.UpdateEvent) bool {
 	return true
 }
 
+// applyLiveSpecChanges applies the changes of the spec of the given SparkApplication which do not require a
+// restart, without invalidating the application.
+func (f *EventFilter) applyLiveSpecChanges(oldApp, newApp *v1beta2.SparkApplication) {
+	changes := util.GetSpecChanges(oldApp, newApp)
+	logger.Info("Applying SparkApplication spec changes in place", "name", newApp.Name, "namespace", newApp.Namespace, "changes", changes)
+	if err := applyLiveSpecChanges(context.TODO(), f.client, oldApp, newApp); err != nil {
+		logger.Error(err, "Failed to apply spec changes", "application", newApp.Name)
+		f.recorder.Eventf(
+			newApp,
+			corev1.EventTypeWarning,
+			"SparkApplicationSpecUpdateFailed",
+			"Failed to update spec for SparkApplication %s: %v",
+			newApp.Name,
+			err,
+		)
+		return
+	}
+	f.recorder.Eventf(
+		newApp,
+		corev1.EventTypeNormal,
+		common.EventSparkApplicationSpecUpdated,
+		"SparkApplication %s was updated in place: %s",
+		newApp.Name,
+		strings.Join(changes, ", "),
+	)
+	if slices.Contains(changes, "spec.executor.instances") {
+		f.scaleExecutors(newApp)
+	}
+}
+
+// scaleExecutors scales the executors of the given running SparkApplication to its new executor instance count.
+// If the executors can be scaled live, their limit is recorded in the status of the application, which the
+// controller and the pod webhook then enforce. Otherwise, the new count takes effect from the next attempt.
+func (f *EventFilter) scaleExecutors(app *v1beta2.SparkApplication) {
+	if app.Spec.Executor.Instances == nil || !util.IsDriverRunning(app) {
+		return
+	}
+
+	if !util.IsLiveExecutorScaling(app) {
+		f.recorder.Eventf(
+			app,
+			corev1.EventTypeNormal,
+			common.EventSparkApplicationExecutorScalingDeferred,
+			"SparkApplication %s cannot be scaled live, it will run %d executors from its next attempt",
+			app.Name,
+			*app.Spec.Executor.Instances,
+		)
+		return
+	}
+
+	app.Status.ExecutorLimit = util.Int32Ptr(util.GetExecutorLimit(app))
+	logger.Info("Scaling SparkApplication executors", "name", app.Name, "namespace", app.Namespace, "limit", *app.Status.ExecutorLimit)
+	if err := f.client.Status().Update(context.TODO(), app); err != nil {
+		logger.Error(err, "Failed to update application status", "application", app.Name)
+		f.recorder.Eventf(
+			app,
+			corev1.EventTypeWarning,
+			"SparkApplicationSpecUpdateFailed",
+			"Failed to update spec for SparkApplication %s: %v",
+			app.Name,
+			err,
+		)
+		return
+	}
+	f.recorder.Eventf(
+		app,
+		corev1.EventTypeNormal,
+		common.EventSparkApplicationExecutorsScaled,
+		"SparkApplication %s was scaled to %d executors",
+		app.Name,
+		*app.Status.ExecutorLimit,
+	)
+}
+
 // Delete implements predicate.Predicate.
 func (f *EventFilter) Delete(e event.DeleteEvent) bool {
 	app, ok := e.Object.(*v1beta2.SparkApplication)
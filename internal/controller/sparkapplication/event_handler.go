@@ -77,7 +77,7 @@ func (h *SparkPodEventHandler) Update(ctx context.Context, event event.UpdateEve
 		return
 	}
 
-	if newPod.Status.Phase == oldPod.Status.Phase {
+	if !sparkPodStatusChanged(oldPod, newPod) {
 		return
 	}
 
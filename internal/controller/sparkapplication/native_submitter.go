@@ -0,0 +1,705 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/resourceusage"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+const (
+	// nativeDriverPort is the port the driver listens on for RPC connections from the executors.
+	nativeDriverPort = 7078
+	// nativeBlockManagerPort is the port of the block manager of the driver.
+	nativeBlockManagerPort = 7079
+
+	// nativeSparkConfDir is the directory where the Spark properties file is mounted in the driver container.
+	nativeSparkConfDir = "/opt/spark/conf"
+	// nativeSparkPropertiesFileName is the name of the Spark properties file in the driver ConfigMap.
+	nativeSparkPropertiesFileName = "spark.properties"
+	// nativeSparkConfVolumeName is the name of the volume of the driver ConfigMap.
+	nativeSparkConfVolumeName = "spark-conf-volume-driver"
+
+	// nativePodTemplateDir is the directory where the executor pod template is mounted in the driver container.
+	nativePodTemplateDir = "/opt/spark/pod-template"
+	// nativePodTemplateFileName is the name of the executor pod template file in the driver ConfigMap.
+	nativePodTemplateFileName = "pod-spec-template.yml"
+	// nativePodTemplateVolumeName is the name of the volume of the executor pod template.
+	nativePodTemplateVolumeName = "pod-template-volume"
+)
+
+// NativeSubmitter submits SparkApplications by creating the driver pod, the driver ConfigMap and
+// the driver Service directly instead of running spark-submit. The driver is configured from the
+// same set of options used to build the spark-submit arguments.
+type NativeSubmitter struct {
+	client client.Client
+}
+
+// NativeSubmitter implements Submitter.
+var _ Submitter = &NativeSubmitter{}
+
+// NewNativeSubmitter creates a new NativeSubmitter instance.
+func NewNativeSubmitter(client client.Client) *NativeSubmitter {
+	return &NativeSubmitter{
+		client: client,
+	}
+}
+
+// Submit implements Submitter interface.
+func (s *NativeSubmitter) Submit(ctx context.Context, app *v1beta2.SparkApplication) error {
+	sparkSubmitArgs, err := buildSparkSubmitArgs(app)
+	if err != nil {
+		return fmt.Errorf("failed to build spark-submit arguments: %v", err)
+	}
+
+	command, err := parseSparkSubmitArgs(sparkSubmitArgs)
+	if err != nil {
+		return fmt.Errorf("failed to parse spark-submit arguments: %v", err)
+	}
+
+	driver, err := newNativeDriver(app, command)
+	if err != nil {
+		return err
+	}
+
+	configMap, err := driver.configMap()
+	if err != nil {
+		return fmt.Errorf("failed to build driver config map: %v", err)
+	}
+	service := driver.service()
+	pod, err := driver.pod()
+	if err != nil {
+		return fmt.Errorf("failed to build driver pod: %v", err)
+	}
+
+	logger.Info("Creating driver pod for SparkApplication", "name", app.Name, "namespace", app.Namespace, "driverPod", pod.Name)
+	if err := s.client.Create(ctx, pod); err != nil {
+		if errors.IsAlreadyExists(err) {
+			return fmt.Errorf("driver pod already exist")
+		}
+		return fmt.Errorf("failed to create driver pod: %v", err)
+	}
+
+	// The driver ConfigMap and Service are owned by the driver pod so that they are garbage collected with it.
+	ownerReference := metav1.OwnerReference{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Name:       pod.Name,
+		UID:        pod.UID,
+		Controller: util.BoolPtr(true),
+	}
+	for _, obj := range []client.Object{configMap, service} {
+		obj.SetOwnerReferences([]metav1.OwnerReference{ownerReference})
+		if err := s.client.Create(ctx, obj); err != nil && !errors.IsAlreadyExists(err) {
+			// The driver pod must be cleaned up even if the submission has timed out.
+			if deleteErr := s.client.Delete(context.WithoutCancel(ctx), pod); deleteErr != nil && !errors.IsNotFound(deleteErr) {
+				logger.Error(deleteErr, "Failed to delete driver pod", "name", app.Name, "namespace", app.Namespace, "driverPod", pod.Name)
+			}
+			return fmt.Errorf("failed to create driver resource %s: %v", obj.GetName(), err)
+		}
+	}
+
+	return nil
+}
+
+// sparkSubmitCommand is the parsed form of a list of spark-submit arguments.
+type sparkSubmitCommand struct {
+	conf                map[string]string
+	mainClass           string
+	mainApplicationFile string
+	arguments           []string
+	proxyUser           string
+}
+
+// sparkSubmitFlagConfKeys maps the spark-submit flags to their equivalent Spark configuration properties.
+var sparkSubmitFlagConfKeys = map[string]string{
+	"--master":           "spark.master",
+	"--deploy-mode":      "spark.submit.deployMode",
+	"--name":             common.SparkAppName,
+	"--jars":             "spark.jars",
+	"--packages":         "spark.jars.packages",
+	"--exclude-packages": "spark.jars.excludes",
+	"--repositories":     "spark.jars.repositories",
+	"--py-files":         "spark.submit.pyFiles",
+	"--files":            "spark.files",
+	"--archives":         "spark.archives",
+}
+
+// parseSparkSubmitArgs parses the arguments built by buildSparkSubmitArgs. As in spark-submit,
+// values given by the dedicated flags take precedence over the ones given with --conf.
+func parseSparkSubmitArgs(args []string) (*sparkSubmitCommand, error) {
+	command := &sparkSubmitCommand{
+		conf: make(map[string]string),
+	}
+	flagConf := make(map[string]string)
+
+	i := 0
+	for ; i < len(args) && strings.HasPrefix(args[i], "--"); i += 2 {
+		flag := args[i]
+		if i+1 >= len(args) {
+			return nil, fmt.Errorf("missing value for %s", flag)
+		}
+		value := args[i+1]
+
+		switch flag {
+		case "--conf":
+			key, val, found := strings.Cut(value, "=")
+			if !found {
+				return nil, fmt.Errorf("invalid Spark configuration property %q", value)
+			}
+			command.conf[key] = val
+		case "--class":
+			command.mainClass = value
+		case "--proxy-user":
+			command.proxyUser = value
+		default:
+			key, ok := sparkSubmitFlagConfKeys[flag]
+			if !ok {
+				return nil, fmt.Errorf("unsupported spark-submit flag %s", flag)
+			}
+			flagConf[key] = value
+		}
+	}
+
+	for key, value := range flagConf {
+		command.conf[key] = value
+	}
+
+	if i < len(args) {
+		command.mainApplicationFile = args[i]
+		command.arguments = args[i+1:]
+	}
+
+	return command, nil
+}
+
+// nativeDriver builds the Kubernetes resources of the driver of a SparkApplication.
+type nativeDriver struct {
+	app     *v1beta2.SparkApplication
+	command *sparkSubmitCommand
+
+	appID         string
+	podName       string
+	configMapName string
+	serviceName   string
+}
+
+func newNativeDriver(app *v1beta2.SparkApplication, command *sparkSubmitCommand) (*nativeDriver, error) {
+	if deployMode := command.conf["spark.submit.deployMode"]; deployMode != string(v1beta2.DeployModeCluster) {
+		return nil, fmt.Errorf("native submitter does not support deploy mode %q", deployMode)
+	}
+
+	appID := "spark-" + strings.ReplaceAll(uuid.New().String(), "-", "")
+	prefix := getNativeResourceNamePrefix(app)
+	serviceName := prefix + "-driver-svc"
+	if len(serviceName) > 63 {
+		serviceName = fmt.Sprintf("spark-%s-driver-svc", appID[len(appID)-16:])
+	}
+
+	d := &nativeDriver{
+		app:           app,
+		command:       command,
+		appID:         appID,
+		podName:       util.GetDriverPodName(app),
+		configMapName: prefix + "-driver-conf-map",
+		serviceName:   serviceName,
+	}
+	d.conf()
+	return d, nil
+}
+
+// getNativeResourceNamePrefix returns the prefix of the names of the resources created for the
+// current submission of the given SparkApplication.
+func getNativeResourceNamePrefix(app *v1beta2.SparkApplication) string {
+	id := strings.ReplaceAll(app.Status.SubmissionID, "-", "")
+	if len(id) > 8 {
+		id = id[:8]
+	}
+	return fmt.Sprintf("%s-%s", app.Name, id)
+}
+
+// conf adds the Spark configuration properties that spark-submit would set for a driver running in cluster mode.
+func (d *nativeDriver) conf() {
+	conf := d.command.conf
+	conf["spark.app.id"] = d.appID
+	conf["spark.app.submitTime"] = strconv.FormatInt(metav1.Now().UnixMilli(), 10)
+	conf["spark.kubernetes.submitInDriver"] = "true"
+	conf["spark.driver.host"] = fmt.Sprintf("%s.%s.svc", d.serviceName, d.app.Namespace)
+	conf["spark.driver.port"] = strconv.Itoa(nativeDriverPort)
+	conf["spark.driver.blockManager.port"] = strconv.Itoa(nativeBlockManagerPort)
+	conf["spark.kubernetes.resource.type"] = d.resourceType()
+
+	prefix := getNativeResourceNamePrefix(d.app)
+	if _, ok := conf[common.SparkKubernetesExecutorPodNamePrefix]; !ok && len(prefix) <= 47 {
+		conf[common.SparkKubernetesExecutorPodNamePrefix] = prefix
+	}
+
+	// The driver pod template is applied by the submitter, and the executor pod template
+	// is mounted into the driver container from the driver ConfigMap.
+	delete(conf, common.SparkKubernetesDriverPodTemplateFile)
+	delete(conf, common.SparkKubernetesDriverPodTemplateContainerName)
+	if d.app.Spec.Executor.Template != nil {
+		conf[common.SparkKubernetesExecutorPodTemplateFile] = fmt.Sprintf("%s/%s", nativePodTemplateDir, nativePodTemplateFileName)
+	}
+}
+
+func (d *nativeDriver) resourceType() string {
+	switch d.app.Spec.Type {
+	case v1beta2.SparkApplicationTypePython:
+		return "python"
+	case v1beta2.SparkApplicationTypeR:
+		return "r"
+	default:
+		return "java"
+	}
+}
+
+// configMap builds the driver ConfigMap holding the Spark properties file and the executor pod template.
+func (d *nativeDriver) configMap() (*corev1.ConfigMap, error) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      d.configMapName,
+			Namespace: d.app.Namespace,
+			Labels: map[string]string{
+				common.LabelSparkApplicationSelector: d.appID,
+				common.LabelSparkAppName:             d.app.Name,
+				common.LabelSubmissionID:             d.app.Status.SubmissionID,
+			},
+		},
+		Data: map[string]string{
+			nativeSparkPropertiesFileName: formatSparkProperties(d.command.conf),
+		},
+	}
+
+	if d.app.Spec.Executor.Template != nil {
+		data, err := yaml.Marshal(d.app.Spec.Executor.Template)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal executor pod template: %v", err)
+		}
+		configMap.Data[nativePodTemplateFileName] = string(data)
+	}
+
+	return configMap, nil
+}
+
+// service builds the headless Service executors use to connect to the driver.
+func (d *nativeDriver) service() *corev1.Service {
+	labels := getPrefixedConf(d.command.conf, "spark.kubernetes.driver.service.label.")
+	labels[common.LabelSparkAppName] = d.app.Name
+	labels[common.LabelSubmissionID] = d.app.Status.SubmissionID
+
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        d.serviceName,
+			Namespace:   d.app.Namespace,
+			Labels:      labels,
+			Annotations: getPrefixedConf(d.command.conf, "spark.kubernetes.driver.service.annotation."),
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: corev1.ClusterIPNone,
+			Selector: map[string]string{
+				common.LabelSparkApplicationSelector: d.appID,
+				common.LabelSparkRole:                common.SparkRoleDriver,
+			},
+			Ports: []corev1.ServicePort{
+				{
+					Name: "driver-rpc-port",
+					Port: nativeDriverPort,
+				},
+				{
+					Name: "blockmanager",
+					Port: nativeBlockManagerPort,
+				},
+				{
+					Name: "spark-ui",
+					Port: d.uiPort(),
+				},
+			},
+		},
+	}
+}
+
+func (d *nativeDriver) uiPort() int32 {
+	if value, ok := d.command.conf[common.SparkUIPortKey]; ok {
+		if port, err := strconv.ParseInt(value, 10, 32); err == nil {
+			return int32(port)
+		}
+	}
+	return common.DefaultSparkWebUIPort
+}
+
+// pod builds the driver pod. The driver pod template, if any, is used as the base of the pod.
+func (d *nativeDriver) pod() (*corev1.Pod, error) {
+	conf := d.command.conf
+	pod := &corev1.Pod{}
+	if template := d.app.Spec.Driver.Template; template != nil {
+		template = template.DeepCopy()
+		pod.ObjectMeta = template.ObjectMeta
+		pod.Spec = template.Spec
+	}
+	pod.Name = d.podName
+	pod.Namespace = d.app.Namespace
+	pod.Labels = mergeMaps(pod.Labels, getPrefixedConf(conf, "spark.kubernetes.driver.label."))
+	pod.Labels[common.LabelSparkApplicationSelector] = d.appID
+	pod.Labels[common.LabelSparkRole] = common.SparkRoleDriver
+	pod.Annotations = mergeMaps(pod.Annotations, getPrefixedConf(conf, "spark.kubernetes.driver.annotation."))
+	pod.Spec.RestartPolicy = corev1.RestartPolicyNever
+	pod.Spec.NodeSelector = mergeMaps(
+		pod.Spec.NodeSelector,
+		getPrefixedConf(conf, "spark.kubernetes.node.selector."),
+		getPrefixedConf(conf, "spark.kubernetes.driver.node.selector."),
+	)
+	if serviceAccount, ok := conf[common.SparkKubernetesAuthenticateDriverServiceAccountName]; ok {
+		pod.Spec.ServiceAccountName = serviceAccount
+	}
+	if schedulerName, ok := conf[common.SparkKubernetesDriverSchedulerName]; ok {
+		pod.Spec.SchedulerName = schedulerName
+	}
+	if secrets, ok := conf[common.SparkKubernetesContainerImagePullSecrets]; ok {
+		for _, secret := range strings.Split(secrets, ",") {
+			pod.Spec.ImagePullSecrets = append(pod.Spec.ImagePullSecrets, corev1.LocalObjectReference{Name: strings.TrimSpace(secret)})
+		}
+	}
+
+	// Find the driver container from the pod template or add a new one.
+	index := -1
+	for i, c := range pod.Spec.Containers {
+		if c.Name == common.SparkDriverContainerName {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		pod.Spec.Containers = append([]corev1.Container{{Name: common.SparkDriverContainerName}}, pod.Spec.Containers...)
+		index = 0
+	}
+	container := &pod.Spec.Containers[index]
+
+	container.Image = conf[common.SparkKubernetesDriverContainerImage]
+	container.ImagePullPolicy = corev1.PullIfNotPresent
+	if pullPolicy, ok := conf[common.SparkKubernetesContainerImagePullPolicy]; ok {
+		container.ImagePullPolicy = corev1.PullPolicy(pullPolicy)
+	}
+	container.Args = d.containerArgs()
+	container.Ports = append(container.Ports,
+		corev1.ContainerPort{Name: "driver-rpc-port", ContainerPort: nativeDriverPort, Protocol: corev1.ProtocolTCP},
+		corev1.ContainerPort{Name: "blockmanager", ContainerPort: nativeBlockManagerPort, Protocol: corev1.ProtocolTCP},
+		corev1.ContainerPort{Name: "spark-ui", ContainerPort: d.uiPort(), Protocol: corev1.ProtocolTCP},
+	)
+
+	resources, err := d.resources()
+	if err != nil {
+		return nil, err
+	}
+	container.Resources = resources
+
+	container.Env = append(container.Env,
+		corev1.EnvVar{Name: "SPARK_USER", Value: d.sparkUser()},
+		corev1.EnvVar{Name: "SPARK_APPLICATION_ID", Value: d.appID},
+		corev1.EnvVar{
+			Name: "SPARK_DRIVER_BIND_ADDRESS",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{APIVersion: "v1", FieldPath: "status.podIP"},
+			},
+		},
+		corev1.EnvVar{Name: common.EnvSparkConfDir, Value: nativeSparkConfDir},
+	)
+	driverEnv := getPrefixedConf(conf, "spark.kubernetes.driverEnv.")
+	for _, name := range sortedKeys(driverEnv) {
+		container.Env = append(container.Env, corev1.EnvVar{Name: name, Value: driverEnv[name]})
+	}
+	secretKeyRefs := getPrefixedConf(conf, "spark.kubernetes.driver.secretKeyRef.")
+	for _, name := range sortedKeys(secretKeyRefs) {
+		secretName, key, found := strings.Cut(secretKeyRefs[name], ":")
+		if !found {
+			return nil, fmt.Errorf("invalid secret key reference %q for environment variable %s", secretKeyRefs[name], name)
+		}
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+					Key:                  key,
+				},
+			},
+		})
+	}
+
+	// Mount the Spark properties file and the executor pod template.
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		Name: nativeSparkConfVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: d.configMapName},
+				Items:                []corev1.KeyToPath{{Key: nativeSparkPropertiesFileName, Path: nativeSparkPropertiesFileName}},
+			},
+		},
+	})
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: nativeSparkConfVolumeName, MountPath: nativeSparkConfDir})
+	if d.app.Spec.Executor.Template != nil {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name: nativePodTemplateVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: d.configMapName},
+					Items:                []corev1.KeyToPath{{Key: nativePodTemplateFileName, Path: nativePodTemplateFileName}},
+				},
+			},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: nativePodTemplateVolumeName, MountPath: nativePodTemplateDir})
+	}
+
+	// Mount the secrets.
+	secrets := getPrefixedConf(conf, "spark.kubernetes.driver.secrets.")
+	for _, name := range sortedKeys(secrets) {
+		volumeName := name + "-volume"
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name:         volumeName,
+			VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: name}},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: volumeName, MountPath: secrets[name]})
+	}
+
+	// Mount the volumes and set up the local directories.
+	volumes, volumeMounts, err := getNativeDriverVolumes(conf)
+	if err != nil {
+		return nil, err
+	}
+	pod.Spec.Volumes = append(pod.Spec.Volumes, volumes...)
+	container.VolumeMounts = append(container.VolumeMounts, volumeMounts...)
+
+	var localDirs []string
+	for _, volumeMount := range container.VolumeMounts {
+		if strings.HasPrefix(volumeMount.Name, common.SparkLocalDirVolumePrefix) {
+			localDirs = append(localDirs, volumeMount.MountPath)
+		}
+	}
+	if len(localDirs) == 0 {
+		volumeName := common.SparkLocalDirVolumePrefix + "1"
+		localDir := "/var/data/spark-" + uuid.New().String()
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name:         volumeName,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: volumeName, MountPath: localDir})
+		localDirs = append(localDirs, localDir)
+	}
+	container.Env = append(container.Env, corev1.EnvVar{Name: "SPARK_LOCAL_DIRS", Value: strings.Join(localDirs, ",")})
+
+	return pod, nil
+}
+
+// containerArgs returns the arguments of the driver container that are passed to the entrypoint of the Spark image.
+func (d *nativeDriver) containerArgs() []string {
+	args := []string{"driver"}
+	if d.command.proxyUser != "" {
+		args = append(args, "--proxy-user", d.command.proxyUser)
+	}
+	args = append(args, "--properties-file", fmt.Sprintf("%s/%s", nativeSparkConfDir, nativeSparkPropertiesFileName))
+
+	mainClass := d.command.mainClass
+	switch d.app.Spec.Type {
+	case v1beta2.SparkApplicationTypePython:
+		mainClass = "org.apache.spark.deploy.PythonRunner"
+	case v1beta2.SparkApplicationTypeR:
+		mainClass = "org.apache.spark.deploy.RRunner"
+	}
+	if mainClass != "" {
+		args = append(args, "--class", mainClass)
+	}
+
+	if d.command.mainApplicationFile != "" {
+		args = append(args, d.command.mainApplicationFile)
+	}
+	return append(args, d.command.arguments...)
+}
+
+func (d *nativeDriver) sparkUser() string {
+	if d.command.proxyUser != "" {
+		return d.command.proxyUser
+	}
+	return "root"
+}
+
+// resources returns the resource requirements of the driver container computed in the same way as Spark does.
+func (d *nativeDriver) resources() (corev1.ResourceRequirements, error) {
+	// The resources are calculated from the resolved Spark configuration properties, which already
+	// reflect the driver spec, and from the pod template, which may request ephemeral storage.
+	app := &v1beta2.SparkApplication{
+		Spec: v1beta2.SparkApplicationSpec{
+			Type:      d.app.Spec.Type,
+			SparkConf: d.command.conf,
+			Driver: v1beta2.DriverSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{Template: d.app.Spec.Driver.Template},
+			},
+		},
+	}
+	resources, err := resourceusage.DriverResources(app)
+	if err != nil {
+		return corev1.ResourceRequirements{}, err
+	}
+	return corev1.ResourceRequirements{Requests: resources.Requests, Limits: resources.Limits}, nil
+}
+
+// nativeVolume is a volume parsed from the driver volume Spark configuration properties.
+type nativeVolume struct {
+	volumeType string
+	mount      corev1.VolumeMount
+	options    map[string]string
+}
+
+// getNativeDriverVolumes builds the driver volumes and volume mounts from the
+// spark.kubernetes.driver.volumes.[VolumeType].[VolumeName].* configuration properties.
+func getNativeDriverVolumes(conf map[string]string) ([]corev1.Volume, []corev1.VolumeMount, error) {
+	parsed := make(map[string]*nativeVolume)
+	for key, value := range conf {
+		if !strings.HasPrefix(key, common.SparkKubernetesDriverVolumesPrefix) {
+			continue
+		}
+		parts := strings.SplitN(strings.TrimPrefix(key, common.SparkKubernetesDriverVolumesPrefix), ".", 4)
+		if len(parts) < 4 {
+			continue
+		}
+		volumeType, name, kind, option := parts[0], parts[1], parts[2], parts[3]
+		volume, ok := parsed[name]
+		if !ok {
+			volume = &nativeVolume{
+				volumeType: volumeType,
+				mount:      corev1.VolumeMount{Name: name},
+				options:    make(map[string]string),
+			}
+			parsed[name] = volume
+		}
+		switch kind {
+		case "mount":
+			switch option {
+			case "path":
+				volume.mount.MountPath = value
+			case "subPath":
+				volume.mount.SubPath = value
+			case "readOnly":
+				volume.mount.ReadOnly = value == "true"
+			}
+		case "options":
+			volume.options[option] = value
+		}
+	}
+
+	var volumes []corev1.Volume
+	var volumeMounts []corev1.VolumeMount
+	for _, name := range sortedKeys(parsed) {
+		v := parsed[name]
+		volume := corev1.Volume{Name: name}
+		switch v.volumeType {
+		case common.VolumeTypeEmptyDir:
+			volume.EmptyDir = &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMedium(v.options["medium"])}
+			if sizeLimit, ok := v.options["sizeLimit"]; ok {
+				quantity, err := resource.ParseQuantity(sizeLimit)
+				if err != nil {
+					return nil, nil, fmt.Errorf("failed to parse size limit of volume %s: %v", name, err)
+				}
+				volume.EmptyDir.SizeLimit = &quantity
+			}
+		case common.VolumeTypeHostPath:
+			volume.HostPath = &corev1.HostPathVolumeSource{Path: v.options["path"]}
+			if hostPathType, ok := v.options["type"]; ok {
+				volume.HostPath.Type = (*corev1.HostPathType)(&hostPathType)
+			}
+		case common.VolumeTypeNFS:
+			volume.NFS = &corev1.NFSVolumeSource{
+				Path:     v.options["path"],
+				Server:   v.options["server"],
+				ReadOnly: v.options["readOnly"] == "true",
+			}
+		case common.VolumeTypePersistentVolumeClaim:
+			volume.PersistentVolumeClaim = &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: v.options["claimName"],
+				ReadOnly:  v.options["readOnly"] == "true",
+			}
+		default:
+			return nil, nil, fmt.Errorf("unsupported volume type %s", v.volumeType)
+		}
+		volumes = append(volumes, volume)
+		if v.mount.MountPath != "" {
+			volumeMounts = append(volumeMounts, v.mount)
+		}
+	}
+
+	return volumes, volumeMounts, nil
+}
+
+// formatSparkProperties formats the given Spark configuration properties as a Java properties file.
+func formatSparkProperties(conf map[string]string) string {
+	keyEscaper := strings.NewReplacer(`\`, `\\`, " ", `\ `, "=", `\=`, ":", `\:`, "#", `\#`, "!", `\!`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	valueEscaper := strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+	var builder strings.Builder
+	for _, key := range sortedKeys(conf) {
+		value := valueEscaper.Replace(conf[key])
+		if strings.HasPrefix(value, " ") {
+			value = `\` + value
+		}
+		fmt.Fprintf(&builder, "%s=%s\n", keyEscaper.Replace(key), value)
+	}
+	return builder.String()
+}
+
+// getPrefixedConf returns the Spark configuration properties with the given prefix, keyed by the rest of the property name.
+func getPrefixedConf(conf map[string]string, prefix string) map[string]string {
+	result := make(map[string]string)
+	for key, value := range conf {
+		if strings.HasPrefix(key, prefix) {
+			result[strings.TrimPrefix(key, prefix)] = value
+		}
+	}
+	return result
+}
+
+func mergeMaps(maps ...map[string]string) map[string]string {
+	result := make(map[string]string)
+	for _, m := range maps {
+		for key, value := range m {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+func TestParseSparkSubmitArgs(t *testing.T) {
+	args := []string{
+		"--master", "k8s://https://127.0.0.1:443",
+		"--deploy-mode", "cluster",
+		"--class", "org.apache.spark.examples.SparkPi",
+		"--name", "spark-pi",
+		"--jars", "local:///opt/spark/jars/a.jar,local:///opt/spark/jars/b.jar",
+		"--conf", "spark.app.name=ignored",
+		"--conf", "spark.executor.extraJavaOptions=-Dkey=value",
+		"--proxy-user", "alice",
+		"local:///opt/spark/examples/jars/spark-examples.jar",
+		"1000",
+		"--verbose",
+	}
+
+	command, err := parseSparkSubmitArgs(args)
+	assert.Nil(t, err)
+	assert.Equal(t, "org.apache.spark.examples.SparkPi", command.mainClass)
+	assert.Equal(t, "alice", command.proxyUser)
+	assert.Equal(t, "local:///opt/spark/examples/jars/spark-examples.jar", command.mainApplicationFile)
+	assert.Equal(t, []string{"1000", "--verbose"}, command.arguments)
+	assert.Equal(t, map[string]string{
+		"spark.master":                    "k8s://https://127.0.0.1:443",
+		"spark.submit.deployMode":         "cluster",
+		"spark.app.name":                  "spark-pi",
+		"spark.jars":                      "local:///opt/spark/jars/a.jar,local:///opt/spark/jars/b.jar",
+		"spark.executor.extraJavaOptions": "-Dkey=value",
+	}, command.conf)
+}
+
+func TestParseSparkSubmitArgsInvalid(t *testing.T) {
+	invalidArgs := map[string][]string{
+		"missing value":    {"--master"},
+		"invalid conf":     {"--conf", "spark.driver.cores"},
+		"unsupported flag": {"--driver-memory", "1g"},
+	}
+
+	for name, args := range invalidArgs {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseSparkSubmitArgs(args)
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestFormatSparkProperties(t *testing.T) {
+	conf := map[string]string{
+		"spark.driver.extraJavaOptions":     "-Da=b -Dc=d",
+		"spark.app.name":                    "spark-pi",
+		"spark.kubernetes.driver.label.a:b": " value\nwith newline",
+	}
+
+	expected := "spark.app.name=spark-pi\n" +
+		"spark.driver.extraJavaOptions=-Da=b -Dc=d\n" +
+		"spark.kubernetes.driver.label.a\\:b=\\ value\\nwith newline\n"
+	assert.Equal(t, expected, formatSparkProperties(conf))
+}
+
+func TestNativeDriverPod(t *testing.T) {
+	t.Setenv(common.EnvKubernetesServiceHost, "127.0.0.1")
+	t.Setenv(common.EnvKubernetesServicePort, "443")
+
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "spark-pi",
+			Namespace: "default",
+		},
+		Spec: v1beta2.SparkApplicationSpec{
+			Type:                v1beta2.SparkApplicationTypeScala,
+			Mode:                v1beta2.DeployModeCluster,
+			Image:               util.StringPtr("spark:3.5.2"),
+			MainClass:           util.StringPtr("org.apache.spark.examples.SparkPi"),
+			MainApplicationFile: util.StringPtr("local:///opt/spark/examples/jars/spark-examples.jar"),
+			Arguments:           []string{"1000"},
+			Driver: v1beta2.DriverSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{
+					Cores:          util.Int32Ptr(1),
+					Memory:         util.StringPtr("2g"),
+					ServiceAccount: util.StringPtr("spark"),
+					Labels:         map[string]string{"team": "data"},
+					Secrets: []v1beta2.SecretInfo{
+						{Name: "gcp", Path: "/mnt/secrets", Type: v1beta2.SecretTypeGeneric},
+					},
+				},
+			},
+			Executor: v1beta2.ExecutorSpec{
+				Instances: util.Int32Ptr(2),
+			},
+		},
+		Status: v1beta2.SparkApplicationStatus{
+			SubmissionID: "5e2f0b8c-1c9d-4c7e-9a7e-2b6e4d9f1a3b",
+		},
+	}
+
+	args, err := buildSparkSubmitArgs(app)
+	assert.Nil(t, err)
+	command, err := parseSparkSubmitArgs(args)
+	assert.Nil(t, err)
+	driver, err := newNativeDriver(app, command)
+	assert.Nil(t, err)
+
+	pod, err := driver.pod()
+	assert.Nil(t, err)
+	assert.Equal(t, "spark-pi-driver", pod.Name)
+	assert.Equal(t, "spark", pod.Spec.ServiceAccountName)
+	assert.Equal(t, corev1.RestartPolicyNever, pod.Spec.RestartPolicy)
+	assert.Equal(t, "data", pod.Labels["team"])
+	assert.Equal(t, common.SparkRoleDriver, pod.Labels[common.LabelSparkRole])
+	assert.Equal(t, driver.appID, pod.Labels[common.LabelSparkApplicationSelector])
+	assert.Equal(t, app.Status.SubmissionID, pod.Labels[common.LabelSubmissionID])
+
+	assert.Len(t, pod.Spec.Containers, 1)
+	container := pod.Spec.Containers[0]
+	assert.Equal(t, common.SparkDriverContainerName, container.Name)
+	assert.Equal(t, "spark:3.5.2", container.Image)
+	assert.Equal(t, []string{
+		"driver",
+		"--properties-file", "/opt/spark/conf/spark.properties",
+		"--class", "org.apache.spark.examples.SparkPi",
+		"local:///opt/spark/examples/jars/spark-examples.jar",
+		"1000",
+	}, container.Args)
+	// 2g of memory plus the default memory overhead of 384Mi.
+	assert.True(t, resource.MustParse("2432Mi").Equal(container.Resources.Requests[corev1.ResourceMemory]))
+	assert.True(t, resource.MustParse("1").Equal(container.Resources.Requests[corev1.ResourceCPU]))
+
+	var mountPaths []string
+	for _, volumeMount := range container.VolumeMounts {
+		mountPaths = append(mountPaths, volumeMount.MountPath)
+	}
+	assert.Contains(t, mountPaths, "/opt/spark/conf")
+	assert.Contains(t, mountPaths, "/mnt/secrets")
+
+	service := driver.service()
+	assert.Equal(t, "spark-pi-5e2f0b8c-driver-svc", service.Name)
+	assert.Equal(t, corev1.ClusterIPNone, service.Spec.ClusterIP)
+	assert.Equal(t, "spark-pi-5e2f0b8c-driver-svc.default.svc", command.conf["spark.driver.host"])
+	assert.Equal(t, "spark-pi-5e2f0b8c", command.conf[common.SparkKubernetesExecutorPodNamePrefix])
+
+	configMap, err := driver.configMap()
+	assert.Nil(t, err)
+	assert.Contains(t, configMap.Data[nativeSparkPropertiesFileName], "spark.executor.instances=2\n")
+}
+
+func TestNativeDriverClientMode(t *testing.T) {
+	command := &sparkSubmitCommand{
+		conf: map[string]string{"spark.submit.deployMode": string(v1beta2.DeployModeClient)},
+	}
+	_, err := newNativeDriver(&v1beta2.SparkApplication{}, command)
+	assert.NotNil(t, err)
+}
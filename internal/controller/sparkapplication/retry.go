@@ -0,0 +1,60 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// scheduleRetry records in the status of the given failed SparkApplication when it is due to be retried, unless
+// it is recorded already, and returns the time left until then. The retry time is recorded once per failure, so
+// that the jitter of the backoff policy is not drawn again on each reconciliation.
+func (r *Reconciler) scheduleRetry(app *v1beta2.SparkApplication) (time.Duration, error) {
+	if app.Status.NextRetryTime.IsZero() {
+		nextRetryTime, err := util.GetNextRetryTime(app)
+		if err != nil {
+			return -1, err
+		}
+		app.Status.NextRetryTime = metav1.NewTime(nextRetryTime)
+		r.recorder.Eventf(
+			app,
+			corev1.EventTypeNormal,
+			common.EventSparkApplicationRetryScheduled,
+			"SparkApplication %s in state %s will be retried at %s",
+			app.Name,
+			app.Status.AppState.State,
+			nextRetryTime.Format(time.RFC3339),
+		)
+	}
+	return util.TimeUntilNextRetryDue(app)
+}
+
+// markPendingRerun moves the given SparkApplication, whose resources have been deleted, to the PENDING_RERUN
+// state for the given reason. A reason the application is already failing for, such as preemption, is kept.
+func markPendingRerun(app *v1beta2.SparkApplication, reason v1beta2.ApplicationStateReason) {
+	app.Status.AppState.State = v1beta2.ApplicationStatePendingRerun
+	if app.Status.AppState.Reason == "" {
+		app.Status.AppState.Reason = reason
+	}
+}
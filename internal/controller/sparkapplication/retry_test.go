@@ -0,0 +1,107 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+func TestScheduleRetry(t *testing.T) {
+	app := newAdmissionTestApp("app", v1beta2.ApplicationStateFailing, time.Now())
+	app.Spec.RestartPolicy = v1beta2.RestartPolicy{
+		Type:                   v1beta2.RestartPolicyOnFailure,
+		OnFailureRetries:       util.Int32Ptr(3),
+		OnFailureRetryInterval: util.Int64Ptr(60),
+		Backoff:                &v1beta2.RestartBackoff{JitterPercent: util.Int32Ptr(100)},
+	}
+	app.Status.ExecutionAttempts = 1
+	app.Status.SubmissionAttempts = 1
+	app.Status.LastSubmissionAttemptTime = metav1.Now()
+	app.Status.TerminationTime = metav1.Now()
+	recorder := record.NewFakeRecorder(10)
+	r := &Reconciler{recorder: recorder}
+
+	timeUntilNextRetryDue, err := r.scheduleRetry(app)
+	assert.Nil(t, err)
+	assert.True(t, timeUntilNextRetryDue > 0 && timeUntilNextRetryDue <= 2*time.Minute)
+	nextRetryTime := app.Status.NextRetryTime
+	assert.False(t, nextRetryTime.IsZero())
+
+	// The retry time is not drawn again.
+	_, err = r.scheduleRetry(app)
+	assert.Nil(t, err)
+	assert.Equal(t, nextRetryTime, app.Status.NextRetryTime)
+	assert.Len(t, recorder.Events, 1)
+}
+
+func TestMarkPendingRerun(t *testing.T) {
+	app := newAdmissionTestApp("app", v1beta2.ApplicationStateFailing, time.Now())
+	markPendingRerun(app, v1beta2.ApplicationStateReasonRetryingFailure)
+	assert.Equal(t, v1beta2.ApplicationStatePendingRerun, app.Status.AppState.State)
+	assert.Equal(t, v1beta2.ApplicationStateReasonRetryingFailure, app.Status.AppState.Reason)
+
+	// The reason the application was failing for is kept.
+	app = newAdmissionTestApp("app", v1beta2.ApplicationStateFailing, time.Now())
+	app.Status.AppState.Reason = v1beta2.ApplicationStateReasonPreempted
+	markPendingRerun(app, v1beta2.ApplicationStateReasonRetryingFailure)
+	assert.Equal(t, v1beta2.ApplicationStateReasonPreempted, app.Status.AppState.Reason)
+}
+
+func TestReconcileFailingSparkApplicationRetryOnFailureReasons(t *testing.T) {
+	testCases := []struct {
+		name          string
+		failureReason v1beta2.FailureReason
+		wantState     v1beta2.ApplicationStateType
+	}{
+		{name: "selected reason", failureReason: v1beta2.FailureReasonNodeLost, wantState: v1beta2.ApplicationStatePendingRerun},
+		{name: "other reason", failureReason: v1beta2.FailureReasonUserCodeError, wantState: v1beta2.ApplicationStateFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := newAdmissionTestApp("app", v1beta2.ApplicationStateFailing, time.Now())
+			app.Spec.RestartPolicy = v1beta2.RestartPolicy{
+				Type:                   v1beta2.RestartPolicyAlways,
+				OnFailureRetryInterval: util.Int64Ptr(10),
+				RetryOnFailureReasons:  []v1beta2.FailureReason{v1beta2.FailureReasonNodeLost},
+			}
+			app.Status.FailureReason = tc.failureReason
+			app.Status.SubmissionAttempts = 1
+			app.Status.LastSubmissionAttemptTime = metav1.NewTime(time.Now().Add(-time.Hour))
+			app.Status.TerminationTime = metav1.NewTime(time.Now().Add(-time.Minute))
+			r := newAdmissionTestReconciler(t, Options{}, app)
+			r.recorder = record.NewFakeRecorder(10)
+
+			_, err := r.reconcileFailingSparkApplication(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "app"}})
+			assert.Nil(t, err)
+			updated, err := r.getSparkApplication(types.NamespacedName{Namespace: "default", Name: "app"})
+			assert.Nil(t, err)
+			assert.Equal(t, tc.wantState, updated.Status.AppState.State)
+		})
+	}
+}
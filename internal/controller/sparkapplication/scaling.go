@@ -0,0 +1,67 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// limitSparkApplicationExecutors removes the executors of the given running SparkApplication beyond the limit it
+// was scaled to, newest first. The executors are deleted gracefully, so that the driver of the application, which
+// uses dynamic allocation, reschedules their tasks. The pod webhook keeps the driver from replacing them.
+func (r *Reconciler) limitSparkApplicationExecutors(ctx context.Context, app *v1beta2.SparkApplication) error {
+	if app.Status.ExecutorLimit == nil {
+		return nil
+	}
+
+	pods, err := r.getExecutorPods(app)
+	if err != nil {
+		return err
+	}
+	var active []*corev1.Pod
+	for i := range pods.Items {
+		if util.IsPodActive(&pods.Items[i]) {
+			active = append(active, &pods.Items[i])
+		}
+	}
+	excess := len(active) - int(*app.Status.ExecutorLimit)
+	if excess <= 0 {
+		return nil
+	}
+
+	sort.Slice(active, func(i, j int) bool {
+		id1, _ := strconv.Atoi(util.GetSparkExecutorID(active[i]))
+		id2, _ := strconv.Atoi(util.GetSparkExecutorID(active[j]))
+		return id1 > id2
+	})
+	for _, pod := range active[:excess] {
+		logger.Info("Removing executor beyond the limit of SparkApplication", "name", app.Name, "namespace", app.Namespace, "pod", pod.Name, "limit", *app.Status.ExecutorLimit)
+		if err := r.client.Delete(ctx, pod); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete executor pod %s: %v", pod.Name, err)
+		}
+	}
+	return nil
+}
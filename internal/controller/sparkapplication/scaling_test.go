@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+func newScalingTestExecutorPod(app *v1beta2.SparkApplication, id int, phase corev1.PodPhase) *corev1.Pod {
+	labels := util.GetResourceLabels(app)
+	labels[common.LabelSparkRole] = common.SparkRoleExecutor
+	labels[common.LabelSparkExecutorID] = fmt.Sprint(id)
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-exec-%d", app.Name, id),
+			Namespace: app.Namespace,
+			Labels:    labels,
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func TestLimitSparkApplicationExecutors(t *testing.T) {
+	app := newAdmissionTestApp("scaled", v1beta2.ApplicationStateRunning, time.Now())
+	app.Status.SubmissionID = "submission"
+	app.Status.ExecutorLimit = util.Int32Ptr(2)
+	r := newAdmissionTestReconciler(t, Options{},
+		app,
+		newScalingTestExecutorPod(app, 1, corev1.PodRunning),
+		newScalingTestExecutorPod(app, 2, corev1.PodFailed),
+		newScalingTestExecutorPod(app, 3, corev1.PodRunning),
+		newScalingTestExecutorPod(app, 4, corev1.PodPending),
+		newScalingTestExecutorPod(app, 10, corev1.PodRunning),
+	)
+
+	assert.Nil(t, r.limitSparkApplicationExecutors(context.TODO(), app))
+	pods := &corev1.PodList{}
+	assert.Nil(t, r.client.List(context.TODO(), pods, client.InNamespace("default")))
+	var names []string
+	for _, pod := range pods.Items {
+		names = append(names, pod.Name)
+	}
+	// The newest active executors beyond the limit are removed.
+	assert.ElementsMatch(t, []string{"scaled-exec-1", "scaled-exec-2", "scaled-exec-3"}, names)
+
+	// Nothing is removed within the limit or without one.
+	assert.Nil(t, r.limitSparkApplicationExecutors(context.TODO(), app))
+	app.Status.ExecutorLimit = nil
+	assert.Nil(t, r.limitSparkApplicationExecutors(context.TODO(), app))
+	assert.Nil(t, r.client.List(context.TODO(), pods, client.InNamespace("default")))
+	assert.Len(t, pods.Items, 3)
+}
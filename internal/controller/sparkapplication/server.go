@@ -0,0 +1,279 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// createServerResources creates the Service, and the Ingress if configured, exposing the server of the given
+// SparkApplication, and records how to reach the server in its status. The resources are kept when the server
+// is restarted, so that its endpoints stay the same.
+func (r *Reconciler) createServerResources(ctx context.Context, app *v1beta2.SparkApplication) error {
+	service, err := r.createServerService(ctx, app)
+	if err != nil {
+		return fmt.Errorf("failed to create server service: %v", err)
+	}
+
+	status := app.Status.ServerStatus
+	if status == nil {
+		status = &v1beta2.ServerStatus{}
+		app.Status.ServerStatus = status
+	}
+	status.ServiceName = service.Name
+	status.Endpoint = getServerEndpoint(app, fmt.Sprintf("%s.%s.svc", service.Name, service.Namespace), util.GetServerPort(app), false)
+	status.IngressName = ""
+	status.IngressEndpoint = ""
+	setServerHealth(app, v1beta2.ServerHealthUnknown)
+	logger.Info("Created server service for SparkApplication", "name", app.Name, "namespace", app.Namespace, "serviceName", service.Name)
+
+	if app.Spec.Server.Ingress != nil {
+		ingress, err := r.createServerIngress(ctx, app, service)
+		if err != nil {
+			return fmt.Errorf("failed to create server ingress: %v", err)
+		}
+		host := ingress.Spec.Rules[0].Host
+		if len(ingress.Spec.TLS) > 0 {
+			status.IngressEndpoint = getServerEndpoint(app, host, 443, true)
+		} else {
+			status.IngressEndpoint = getServerEndpoint(app, host, 80, false)
+		}
+		status.IngressName = ingress.Name
+		logger.Info("Created server ingress for SparkApplication", "name", app.Name, "namespace", app.Namespace, "ingressName", ingress.Name)
+	}
+	return nil
+}
+
+// createServerService creates the Service exposing the server of the given SparkApplication, or updates it if
+// it already exists.
+func (r *Reconciler) createServerService(ctx context.Context, app *v1beta2.SparkApplication) (*corev1.Service, error) {
+	server := app.Spec.Server
+	port := util.GetServerPort(app)
+	serviceType := corev1.ServiceTypeClusterIP
+	if server.ServiceType != nil {
+		serviceType = *server.ServiceType
+	}
+	labels := util.GetResourceLabels(app)
+	for key, value := range server.ServiceLabels {
+		labels[key] = value
+	}
+
+	service := &corev1.Service{}
+	key := types.NamespacedName{Namespace: app.Namespace, Name: util.GetServerServiceName(app)}
+	if err := r.client.Get(ctx, key, service); err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, err
+		}
+		service = &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            key.Name,
+				Namespace:       key.Namespace,
+				OwnerReferences: []metav1.OwnerReference{util.GetOwnerReference(app)},
+			},
+		}
+	}
+
+	service.Labels = labels
+	service.Annotations = server.ServiceAnnotations
+	service.Spec.Type = serviceType
+	service.Spec.Ports = []corev1.ServicePort{{
+		Name:       util.GetServerPortName(app),
+		Port:       port,
+		TargetPort: intstr.FromInt32(port),
+		Protocol:   corev1.ProtocolTCP,
+	}}
+	service.Spec.Selector = map[string]string{
+		common.LabelSparkAppName: app.Name,
+		common.LabelSparkRole:    common.SparkRoleDriver,
+	}
+
+	if service.ResourceVersion == "" {
+		if err := r.client.Create(ctx, service); err != nil {
+			return nil, err
+		}
+	} else if err := r.client.Update(ctx, service); err != nil {
+		return nil, err
+	}
+	return service, nil
+}
+
+// createServerIngress creates the Ingress exposing the server of the given SparkApplication through the given
+// Service, or updates it if it already exists. Only Spark Connect servers can be exposed through an Ingress, as
+// they serve gRPC over HTTP/2.
+func (r *Reconciler) createServerIngress(ctx context.Context, app *v1beta2.SparkApplication, service *corev1.Service) (*networkingv1.Ingress, error) {
+	if !util.IngressCapabilities.Has("networking.k8s.io/v1") {
+		return nil, fmt.Errorf("server ingresses require the networking.k8s.io/v1 API")
+	}
+
+	config := app.Spec.Server.Ingress
+	host := ingressAppNamespaceURLRegex.ReplaceAllString(ingressAppNameURLRegex.ReplaceAllString(config.Host, app.Name), app.Namespace)
+	annotations := map[string]string{
+		// Spark Connect clients speak gRPC, which the ingress controller needs to proxy as such.
+		"nginx.ingress.kubernetes.io/backend-protocol": "GRPC",
+	}
+	for key, value := range config.IngressAnnotations {
+		annotations[key] = value
+	}
+	ingressClassName := r.options.IngressClassName
+	if config.IngressClassName != nil {
+		ingressClassName = *config.IngressClassName
+	}
+	pathType := networkingv1.PathTypePrefix
+
+	ingress := &networkingv1.Ingress{}
+	key := types.NamespacedName{Namespace: app.Namespace, Name: util.GetServerIngressName(app)}
+	if err := r.client.Get(ctx, key, ingress); err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, err
+		}
+		ingress = &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            key.Name,
+				Namespace:       key.Namespace,
+				OwnerReferences: []metav1.OwnerReference{util.GetOwnerReference(app)},
+			},
+		}
+	}
+
+	ingress.Labels = util.GetResourceLabels(app)
+	ingress.Annotations = annotations
+	ingress.Spec = networkingv1.IngressSpec{
+		Rules: []networkingv1.IngressRule{{
+			Host: host,
+			IngressRuleValue: networkingv1.IngressRuleValue{
+				HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{{
+						Path:     "/",
+						PathType: &pathType,
+						Backend: networkingv1.IngressBackend{
+							Service: &networkingv1.IngressServiceBackend{
+								Name: service.Name,
+								Port: networkingv1.ServiceBackendPort{Number: service.Spec.Ports[0].Port},
+							},
+						},
+					}},
+				},
+			},
+		}},
+		TLS: config.IngressTLS,
+	}
+	if ingressClassName != "" {
+		ingress.Spec.IngressClassName = &ingressClassName
+	}
+
+	if ingress.ResourceVersion == "" {
+		if err := r.client.Create(ctx, ingress); err != nil {
+			return nil, err
+		}
+	} else if err := r.client.Update(ctx, ingress); err != nil {
+		return nil, err
+	}
+	return ingress, nil
+}
+
+// getServerEndpoint returns the endpoint clients of the server of the given SparkApplication connect to
+// at the given host and port.
+func getServerEndpoint(app *v1beta2.SparkApplication, host string, port int32, tls bool) string {
+	if app.Spec.Server.Type == v1beta2.ServerTypeThrift {
+		endpoint := fmt.Sprintf("jdbc:hive2://%s:%d/", host, port)
+		if tls {
+			endpoint += ";ssl=true"
+		}
+		return endpoint
+	}
+	endpoint := fmt.Sprintf("sc://%s:%d", host, port)
+	if tls {
+		endpoint += "/;use_ssl=true"
+	}
+	return endpoint
+}
+
+// updateServerHealth updates the health of the server of the given SparkApplication from the readiness of its
+// driver pod, which is nil if the pod does not exist.
+func (r *Reconciler) updateServerHealth(app *v1beta2.SparkApplication, driverPod *corev1.Pod) {
+	if app.Spec.Server == nil || app.Status.ServerStatus == nil {
+		return
+	}
+
+	health := v1beta2.ServerHealthUnhealthy
+	if driverPod != nil && driverPod.Status.Phase == corev1.PodRunning && util.IsPodReady(driverPod) {
+		health = v1beta2.ServerHealthHealthy
+	}
+	if !setServerHealth(app, health) {
+		return
+	}
+
+	if health == v1beta2.ServerHealthHealthy {
+		r.recorder.Eventf(
+			app,
+			corev1.EventTypeNormal,
+			common.EventSparkApplicationServerHealthy,
+			"Server of SparkApplication %s is ready at %s",
+			app.Name,
+			app.Status.ServerStatus.Endpoint,
+		)
+	} else {
+		r.recorder.Eventf(
+			app,
+			corev1.EventTypeWarning,
+			common.EventSparkApplicationServerUnhealthy,
+			"Server of SparkApplication %s is not ready",
+			app.Name,
+		)
+	}
+}
+
+// setServerHealth sets the health of the server of the given SparkApplication, and tells whether it changed.
+func setServerHealth(app *v1beta2.SparkApplication, health v1beta2.ServerHealth) bool {
+	status := app.Status.ServerStatus
+	if status.Health == health {
+		return false
+	}
+	status.Health = health
+	status.LastHealthTransitionTime = metav1.Now()
+	return true
+}
+
+// recordServerRestart counts a restart of the server of the given SparkApplication, if it is one.
+func (r *Reconciler) recordServerRestart(app *v1beta2.SparkApplication) {
+	if app.Spec.Server == nil || app.Status.ServerStatus == nil {
+		return
+	}
+
+	app.Status.ServerStatus.Restarts++
+	r.recorder.Eventf(
+		app,
+		corev1.EventTypeWarning,
+		common.EventSparkApplicationServerRestarting,
+		"Server of SparkApplication %s is restarting after %s (restart %d)",
+		app.Name,
+		app.Status.AppState.State,
+		app.Status.ServerStatus.Restarts,
+	)
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+func newServerTestApp(serverType v1beta2.ServerType) *v1beta2.SparkApplication {
+	return &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "server",
+			Namespace: "default",
+			UID:       "uid-server",
+		},
+		Spec: v1beta2.SparkApplicationSpec{
+			Type:   v1beta2.SparkApplicationTypeScala,
+			Server: &v1beta2.ServerSpec{Type: serverType},
+		},
+	}
+}
+
+func TestCreateServerResources(t *testing.T) {
+	app := newServerTestApp(v1beta2.ServerTypeConnect)
+	r := newAdmissionTestReconciler(t, Options{})
+
+	assert.Nil(t, r.createServerResources(context.TODO(), app))
+
+	service := &corev1.Service{}
+	assert.Nil(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: util.GetServerServiceName(app)}, service))
+	assert.Equal(t, corev1.ServiceTypeClusterIP, service.Spec.Type)
+	assert.Equal(t, common.DefaultSparkConnectServerPort, service.Spec.Ports[0].Port)
+	assert.Equal(t, "server", service.Spec.Selector[common.LabelSparkAppName])
+	assert.Equal(t, common.SparkRoleDriver, service.Spec.Selector[common.LabelSparkRole])
+
+	status := app.Status.ServerStatus
+	assert.Equal(t, service.Name, status.ServiceName)
+	assert.Equal(t, "sc://server-server-svc.default.svc:15002", status.Endpoint)
+	assert.Equal(t, v1beta2.ServerHealthUnknown, status.Health)
+	assert.Empty(t, status.IngressEndpoint)
+
+	// The service is kept, and updated, when the server is restarted.
+	app.Spec.Server.Port = util.Int32Ptr(15003)
+	assert.Nil(t, r.createServerResources(context.TODO(), app))
+	assert.Nil(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: util.GetServerServiceName(app)}, service))
+	assert.Equal(t, int32(15003), service.Spec.Ports[0].Port)
+	assert.Equal(t, "sc://server-server-svc.default.svc:15003", app.Status.ServerStatus.Endpoint)
+}
+
+func TestCreateServerResourcesThrift(t *testing.T) {
+	app := newServerTestApp(v1beta2.ServerTypeThrift)
+	r := newAdmissionTestReconciler(t, Options{})
+
+	assert.Nil(t, r.createServerResources(context.TODO(), app))
+	assert.Equal(t, "jdbc:hive2://server-server-svc.default.svc:10000/", app.Status.ServerStatus.Endpoint)
+}
+
+func TestCreateServerResourcesIngress(t *testing.T) {
+	util.IngressCapabilities = map[string]bool{"networking.k8s.io/v1": true}
+	defer func() { util.IngressCapabilities = nil }()
+
+	app := newServerTestApp(v1beta2.ServerTypeConnect)
+	app.Spec.Server.Ingress = &v1beta2.ServerIngressConfiguration{
+		Host:       "{{$appName}}.{{$appNamespace}}.example.com",
+		IngressTLS: []networkingv1.IngressTLS{{Hosts: []string{"server.default.example.com"}, SecretName: "tls"}},
+	}
+	r := newAdmissionTestReconciler(t, Options{IngressClassName: "nginx"})
+
+	assert.Nil(t, r.createServerResources(context.TODO(), app))
+
+	ingress := &networkingv1.Ingress{}
+	assert.Nil(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: util.GetServerIngressName(app)}, ingress))
+	assert.Equal(t, "server.default.example.com", ingress.Spec.Rules[0].Host)
+	assert.Equal(t, "nginx", *ingress.Spec.IngressClassName)
+	assert.Equal(t, "GRPC", ingress.Annotations["nginx.ingress.kubernetes.io/backend-protocol"])
+	assert.Equal(t, ingress.Name, app.Status.ServerStatus.IngressName)
+	assert.Equal(t, "sc://server.default.example.com:443/;use_ssl=true", app.Status.ServerStatus.IngressEndpoint)
+}
+
+func TestUpdateServerHealth(t *testing.T) {
+	app := newServerTestApp(v1beta2.ServerTypeConnect)
+	app.Status.ServerStatus = &v1beta2.ServerStatus{Health: v1beta2.ServerHealthUnknown}
+	recorder := record.NewFakeRecorder(10)
+	r := &Reconciler{recorder: recorder}
+
+	pod := &corev1.Pod{
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}},
+		},
+	}
+	r.updateServerHealth(app, pod)
+	assert.Equal(t, v1beta2.ServerHealthUnhealthy, app.Status.ServerStatus.Health)
+
+	pod.Status.Conditions[0].Status = corev1.ConditionTrue
+	r.updateServerHealth(app, pod)
+	assert.Equal(t, v1beta2.ServerHealthHealthy, app.Status.ServerStatus.Health)
+	assert.False(t, app.Status.ServerStatus.LastHealthTransitionTime.IsZero())
+
+	// No event is recorded if the health does not change.
+	r.updateServerHealth(app, pod)
+	assert.Len(t, recorder.Events, 2)
+
+	r.updateServerHealth(app, nil)
+	assert.Equal(t, v1beta2.ServerHealthUnhealthy, app.Status.ServerStatus.Health)
+}
+
+func TestRecordServerRestart(t *testing.T) {
+	app := newServerTestApp(v1beta2.ServerTypeConnect)
+	app.Status.ServerStatus = &v1beta2.ServerStatus{}
+	app.Status.AppState.State = v1beta2.ApplicationStateFailing
+	r := &Reconciler{recorder: record.NewFakeRecorder(10)}
+
+	r.recordServerRestart(app)
+	r.recordServerRestart(app)
+	assert.Equal(t, int32(2), app.Status.ServerStatus.Restarts)
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// applyLiveSpecChanges applies the changes of the spec of the given SparkApplication which do not require a
+// restart to its running pods. Only the labels and annotations of the pods need updating, as the other live
+// changes only affect how the controller handles the application.
+func applyLiveSpecChanges(ctx context.Context, c client.Client, oldApp, newApp *v1beta2.SparkApplication) error {
+	// The application has not been submitted yet.
+	if newApp.Status.SubmissionID == "" {
+		return nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace(newApp.Namespace), client.MatchingLabels(util.GetResourceLabels(newApp))); err != nil {
+		return fmt.Errorf("failed to list pods: %v", err)
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		var oldSpec, newSpec v1beta2.SparkPodSpec
+		switch pod.Labels[common.LabelSparkRole] {
+		case common.SparkRoleDriver:
+			oldSpec, newSpec = oldApp.Spec.Driver.SparkPodSpec, newApp.Spec.Driver.SparkPodSpec
+		case common.SparkRoleExecutor:
+			oldSpec, newSpec = oldApp.Spec.Executor.SparkPodSpec, newApp.Spec.Executor.SparkPodSpec
+		default:
+			continue
+		}
+
+		patched := pod.DeepCopy()
+		patched.Labels = updateStringMap(patched.Labels, oldSpec.Labels, newSpec.Labels)
+		patched.Annotations = updateStringMap(patched.Annotations, oldSpec.Annotations, newSpec.Annotations)
+		if err := c.Patch(ctx, patched, client.MergeFrom(pod)); err != nil {
+			return fmt.Errorf("failed to patch pod %s: %v", pod.Name, err)
+		}
+	}
+	return nil
+}
+
+// updateStringMap sets the entries of newEntries in m, and removes the keys of oldEntries which are no longer
+// in newEntries from m.
+func updateStringMap(m, oldEntries, newEntries map[string]string) map[string]string {
+	if m == nil {
+		m = make(map[string]string)
+	}
+	for key := range oldEntries {
+		if _, ok := newEntries[key]; !ok {
+			delete(m, key)
+		}
+	}
+	for key, value := range newEntries {
+		m[key] = value
+	}
+	return m
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+)
+
+func TestApplyLiveSpecChanges(t *testing.T) {
+	oldApp := newAdmissionTestApp("app", v1beta2.ApplicationStateRunning, time.Now())
+	oldApp.Status.SubmissionID = "submission"
+	oldApp.Spec.Driver.Labels = map[string]string{"team": "data", "tier": "batch"}
+	newApp := oldApp.DeepCopy()
+	newApp.Spec.Driver.Labels = map[string]string{"team": "ml"}
+	newApp.Spec.Executor.Annotations = map[string]string{"owner": "alice"}
+
+	newPod := func(name, role string, labels map[string]string) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					common.LabelSparkAppName: "app",
+					common.LabelSubmissionID: "submission",
+					common.LabelSparkRole:    role,
+				},
+			},
+		}
+		for key, value := range labels {
+			pod.Labels[key] = value
+		}
+		return pod
+	}
+	driverPod := newPod("app-driver", common.SparkRoleDriver, oldApp.Spec.Driver.Labels)
+	executorPod := newPod("app-exec-1", common.SparkRoleExecutor, nil)
+	r := newAdmissionTestReconciler(t, Options{}, driverPod, executorPod)
+
+	assert.Nil(t, applyLiveSpecChanges(context.TODO(), r.client, oldApp, newApp))
+
+	pod := &corev1.Pod{}
+	assert.Nil(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "app-driver"}, pod))
+	assert.Equal(t, "ml", pod.Labels["team"])
+	assert.NotContains(t, pod.Labels, "tier")
+	assert.Equal(t, "app", pod.Labels[common.LabelSparkAppName])
+
+	assert.Nil(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "app-exec-1"}, pod))
+	assert.Equal(t, "alice", pod.Annotations["owner"])
+}
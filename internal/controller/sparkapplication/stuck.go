@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// checkSparkApplicationStuckPending reports in the state of the given submitted or running SparkApplication why
+// its driver, or all its executors, are stuck pending, and takes the action of its stuck pending policy once they
+// have been stuck for longer than the grace period of the policy. It returns whether the action was taken, and
+// the time left until it is due otherwise, or zero if it is not.
+func (r *Reconciler) checkSparkApplicationStuckPending(ctx context.Context, app *v1beta2.SparkApplication) (bool, time.Duration, error) {
+	state := app.Status.AppState
+	if state.State != v1beta2.ApplicationStateSubmitted && state.State != v1beta2.ApplicationStateRunning {
+		// The driver terminated in the meantime, for reasons of its own.
+		if state.Reason == v1beta2.ApplicationStateReasonStuckPending {
+			app.Status.AppState.Reason = ""
+		}
+		return false, 0, nil
+	}
+
+	pod, reason, message, err := r.getStuckPendingPod(app)
+	if err != nil {
+		return false, 0, err
+	}
+	if pod == nil {
+		// The pods are no longer stuck, e.g. as the cluster scaled up.
+		if state.Reason == v1beta2.ApplicationStateReasonStuckPending {
+			app.Status.AppState.Reason = ""
+			app.Status.AppState.ErrorMessage = ""
+		}
+		return false, 0, nil
+	}
+
+	app.Status.AppState.Reason = v1beta2.ApplicationStateReasonStuckPending
+	app.Status.AppState.ErrorMessage = fmt.Sprintf("%s pod %s is stuck pending: %s: %s", pod.Labels[common.LabelSparkRole], pod.Name, reason, message)
+	if state.Reason != v1beta2.ApplicationStateReasonStuckPending {
+		r.recorder.Eventf(
+			app,
+			corev1.EventTypeWarning,
+			common.EventSparkApplicationStuckPending,
+			"SparkApplication %s is stuck: %s",
+			app.Name,
+			app.Status.AppState.ErrorMessage,
+		)
+	}
+
+	policy := app.Spec.StuckPendingPolicy
+	if policy == nil {
+		return false, 0, nil
+	}
+	if remaining := time.Until(pod.CreationTimestamp.Add(time.Duration(policy.GracePeriodSeconds) * time.Second)); remaining > 0 {
+		return false, remaining, nil
+	}
+
+	logger.Info("SparkApplication is stuck pending", "name", app.Name, "namespace", app.Namespace, "action", policy.Action, "message", app.Status.AppState.ErrorMessage)
+	if err := r.deleteSparkResources(ctx, app); err != nil {
+		return false, 0, fmt.Errorf("failed to delete resources associated with SparkApplication: %v", err)
+	}
+	r.recorder.Eventf(
+		app,
+		corev1.EventTypeWarning,
+		common.EventSparkApplicationStuckPending,
+		"SparkApplication %s was stuck pending for more than %d seconds, taking action %s",
+		app.Name,
+		policy.GracePeriodSeconds,
+		policy.Action,
+	)
+	app.Status.FailureReason = util.GetStuckPendingFailureReason(reason)
+	switch policy.Action {
+	case v1beta2.StuckPendingActionResubmit:
+		markPendingRerun(app, v1beta2.ApplicationStateReasonStuckPending)
+	default:
+		app.Status.AppState.State = v1beta2.ApplicationStateFailing
+		app.Status.TerminationTime = metav1.Now()
+	}
+	return true, 0, nil
+}
+
+// getStuckPendingPod returns the pod of the given SparkApplication which is stuck pending, if any, along with the
+// reason why and a message describing it. Executors are only considered stuck if none of them is running.
+func (r *Reconciler) getStuckPendingPod(app *v1beta2.SparkApplication) (*corev1.Pod, string, string, error) {
+	if app.Status.DriverInfo.PodName == "" {
+		return nil, "", "", nil
+	}
+	driverPod, err := r.getDriverPod(app)
+	if err != nil || driverPod == nil {
+		return nil, "", "", err
+	}
+	if reason, message := util.GetPodStuckPendingReason(driverPod); reason != "" {
+		return driverPod, reason, message, nil
+	}
+	if driverPod.Status.Phase != corev1.PodRunning {
+		return nil, "", "", nil
+	}
+
+	pods, err := r.getExecutorPods(app)
+	if err != nil {
+		return nil, "", "", err
+	}
+	var stuckPod *corev1.Pod
+	var stuckReason, stuckMessage string
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase == corev1.PodRunning {
+			return nil, "", "", nil
+		}
+		if reason, message := util.GetPodStuckPendingReason(pod); reason != "" && stuckPod == nil {
+			stuckPod, stuckReason, stuckMessage = pod, reason, message
+		}
+	}
+	return stuckPod, stuckReason, stuckMessage, nil
+}
+
+// minRequeueAfter returns the earliest of the given requeue delays, ignoring zero ones which mean no requeue.
+func minRequeueAfter(a, b time.Duration) time.Duration {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
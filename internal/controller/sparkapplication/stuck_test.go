@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+)
+
+func newStuckTestPod(name, role string, creationTime time.Time, status corev1.PodStatus) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			CreationTimestamp: metav1.NewTime(creationTime),
+			Labels: map[string]string{
+				common.LabelSparkAppName: "app",
+				common.LabelSparkRole:    role,
+			},
+		},
+		Status: status,
+	}
+}
+
+var imagePullBackOffStatus = corev1.PodStatus{
+	Phase: corev1.PodPending,
+	ContainerStatuses: []corev1.ContainerStatus{{
+		Name:  common.SparkDriverContainerName,
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "Back-off pulling image"}},
+	}},
+}
+
+func TestCheckSparkApplicationStuckPending(t *testing.T) {
+	testCases := []struct {
+		name              string
+		policy            *v1beta2.StuckPendingPolicy
+		podAge            time.Duration
+		acted             bool
+		wantState         v1beta2.ApplicationStateType
+		wantFailureReason v1beta2.FailureReason
+		wantRequeue       bool
+	}{
+		{name: "no policy", podAge: time.Hour, wantState: v1beta2.ApplicationStateSubmitted},
+		{name: "within grace period", policy: &v1beta2.StuckPendingPolicy{GracePeriodSeconds: 600, Action: v1beta2.StuckPendingActionFail}, podAge: time.Minute, wantState: v1beta2.ApplicationStateSubmitted, wantRequeue: true},
+		{name: "fail", policy: &v1beta2.StuckPendingPolicy{GracePeriodSeconds: 600, Action: v1beta2.StuckPendingActionFail}, podAge: time.Hour, acted: true, wantState: v1beta2.ApplicationStateFailing, wantFailureReason: v1beta2.FailureReasonImagePullFailure},
+		{name: "resubmit", policy: &v1beta2.StuckPendingPolicy{GracePeriodSeconds: 600, Action: v1beta2.StuckPendingActionResubmit}, podAge: time.Hour, acted: true, wantState: v1beta2.ApplicationStatePendingRerun, wantFailureReason: v1beta2.FailureReasonImagePullFailure},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := newAdmissionTestApp("app", v1beta2.ApplicationStateSubmitted, time.Now())
+			app.Spec.StuckPendingPolicy = tc.policy
+			app.Status.DriverInfo.PodName = "app-driver"
+			driverPod := newStuckTestPod("app-driver", common.SparkRoleDriver, time.Now().Add(-tc.podAge), imagePullBackOffStatus)
+			r := newAdmissionTestReconciler(t, Options{}, driverPod)
+			r.recorder = record.NewFakeRecorder(10)
+
+			acted, timeUntilAction, err := r.checkSparkApplicationStuckPending(context.TODO(), app)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.acted, acted)
+			assert.Equal(t, tc.wantRequeue, timeUntilAction > 0)
+			assert.Equal(t, tc.wantState, app.Status.AppState.State)
+			assert.Equal(t, tc.wantFailureReason, app.Status.FailureReason)
+			assert.Equal(t, v1beta2.ApplicationStateReasonStuckPending, app.Status.AppState.Reason)
+			assert.Equal(t, "driver pod app-driver is stuck pending: ImagePullBackOff: container spark-kubernetes-driver: Back-off pulling image", app.Status.AppState.ErrorMessage)
+		})
+	}
+}
+
+func TestCheckSparkApplicationStuckPendingExecutors(t *testing.T) {
+	app := newAdmissionTestApp("app", v1beta2.ApplicationStateRunning, time.Now())
+	app.Status.DriverInfo.PodName = "app-driver"
+	driverPod := newStuckTestPod("app-driver", common.SparkRoleDriver, time.Now(), corev1.PodStatus{Phase: corev1.PodRunning})
+	executorPod := newStuckTestPod("app-exec-1", common.SparkRoleExecutor, time.Now(), imagePullBackOffStatus)
+	r := newAdmissionTestReconciler(t, Options{}, driverPod, executorPod)
+	r.recorder = record.NewFakeRecorder(10)
+
+	_, _, err := r.checkSparkApplicationStuckPending(context.TODO(), app)
+	assert.Nil(t, err)
+	assert.Equal(t, v1beta2.ApplicationStateReasonStuckPending, app.Status.AppState.Reason)
+	assert.Contains(t, app.Status.AppState.ErrorMessage, "executor pod app-exec-1 is stuck pending")
+
+	// The report is cleared once an executor runs.
+	runningPod := newStuckTestPod("app-exec-2", common.SparkRoleExecutor, time.Now(), corev1.PodStatus{Phase: corev1.PodRunning})
+	assert.Nil(t, r.client.Create(context.TODO(), runningPod))
+	_, _, err = r.checkSparkApplicationStuckPending(context.TODO(), app)
+	assert.Nil(t, err)
+	assert.Empty(t, app.Status.AppState.Reason)
+	assert.Empty(t, app.Status.AppState.ErrorMessage)
+}
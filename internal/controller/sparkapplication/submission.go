@@ -17,17 +17,33 @@ limitations under the License.
 package sparkapplication
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/kubeflow/spark-operator/api/v1beta2"
 	"github.com/kubeflow/spark-operator/pkg/common"
 	"github.com/kubeflow/spark-operator/pkg/util"
 )
 
+const (
+	// sparkSubmitWaitDelay is the time to wait for the output of spark-submit after it has been killed.
+	sparkSubmitWaitDelay = 10 * time.Second
+	// sparkSubmitOutputLimit is the maximum number of bytes of the spark-submit output recorded in the
+	// application status.
+	sparkSubmitOutputLimit = 4 * 1024
+	// sparkSubmitStderrLimit is the maximum number of bytes of the spark-submit standard error included
+	// in the submission error.
+	sparkSubmitStderrLimit = 32 * 1024
+)
+
 // submission includes information of a Spark application to be submitted.
 type submission struct {
 	namespace string
@@ -43,29 +59,100 @@ func newSubmission(args []string, app *v1beta2.SparkApplication) *submission {
 	}
 }
 
-func runSparkSubmit(submission *submission) error {
+// sparkSubmitResult is the result of running spark-submit.
+type sparkSubmitResult struct {
+	// exitCode is the exit code of spark-submit. It is nil if spark-submit did not exit normally.
+	exitCode *int32
+	// output is the tail of the combined standard output and standard error of spark-submit.
+	output string
+}
+
+// runSparkSubmit runs spark-submit with the given submission. The spark-submit process and all of its
+// child processes are killed if the given context is done before spark-submit exits. The returned result
+// is nil if spark-submit could not be started.
+func runSparkSubmit(ctx context.Context, submission *submission) (*sparkSubmitResult, error) {
 	sparkHome, present := os.LookupEnv(common.EnvSparkHome)
 	if !present {
-		return fmt.Errorf("env %s is not specified", common.EnvSparkHome)
+		return nil, fmt.Errorf("env %s is not specified", common.EnvSparkHome)
 	}
 	command := filepath.Join(sparkHome, "bin", "spark-submit")
-	cmd := exec.Command(command, submission.args...)
-	_, err := cmd.Output()
+	cmd := exec.CommandContext(ctx, command, submission.args...)
+	// Run spark-submit in its own process group so that the JVM started by the spark-submit script
+	// is killed together with it.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	// Do not wait forever for the output pipes, which may still be held open by orphaned child processes.
+	cmd.WaitDelay = sparkSubmitWaitDelay
+
+	output := newTailBuffer(sparkSubmitOutputLimit)
+	stderr := newTailBuffer(sparkSubmitStderrLimit)
+	cmd.Stdout = output
+	cmd.Stderr = io.MultiWriter(output, stderr)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start spark-submit: %v", err)
+	}
+	err := cmd.Wait()
+
+	result := &sparkSubmitResult{output: output.String()}
+	if cmd.ProcessState != nil && cmd.ProcessState.ExitCode() >= 0 {
+		exitCode := cmd.ProcessState.ExitCode()
+		result.exitCode = util.Int32Ptr(int32(exitCode))
+	}
 	if err != nil {
-		var errorMsg string
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			errorMsg = string(exitErr.Stderr)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return result, fmt.Errorf("spark-submit was killed: %v", ctxErr)
 		}
+		errorMsg := stderr.String()
 		// The driver pod of the application already exists.
 		if strings.Contains(errorMsg, common.ErrorCodePodAlreadyExists) {
-			return fmt.Errorf("driver pod already exist")
+			return result, fmt.Errorf("driver pod already exist")
 		}
 		if errorMsg != "" {
-			return fmt.Errorf("failed to run spark-submit: %s", errorMsg)
+			return result, fmt.Errorf("failed to run spark-submit: %s", errorMsg)
 		}
-		return fmt.Errorf("failed to run spark-submit: %v", err)
+		return result, fmt.Errorf("failed to run spark-submit: %v", err)
+	}
+	return result, nil
+}
+
+// tailBuffer is an io.Writer that only keeps the last bytes written to it up to a limit.
+// It is safe for concurrent use.
+type tailBuffer struct {
+	mu        sync.Mutex
+	limit     int
+	buf       []byte
+	truncated bool
+}
+
+func newTailBuffer(limit int) *tailBuffer {
+	return &tailBuffer{limit: limit}
+}
+
+// Write implements io.Writer.
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.buf = append(b.buf, p...)
+	if len(b.buf) > b.limit {
+		b.buf = append(b.buf[:0], b.buf[len(b.buf)-b.limit:]...)
+		b.truncated = true
 	}
-	return nil
+	return len(p), nil
+}
+
+// String returns the kept bytes as a valid UTF-8 string, dropping a partial leading character
+// left over from truncation.
+func (b *tailBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.truncated {
+		return string(b.buf)
+	}
+	return strings.ToValidUTF8(string(b.buf), "")
 }
 
 // buildSparkSubmitArgs builds the arguments for spark-submit.
@@ -82,6 +169,7 @@ func buildSparkSubmitArgs(app *v1beta2.SparkApplication) ([]string, error) {
 		memoryOverheadFactorOption,
 		submissionWaitAppCompletionOption,
 		sparkConfOption,
+		serverOption,
 		hadoopConfOption,
 		driverPodTemplateOption,
 		driverPodNameOption,
@@ -137,6 +225,10 @@ func deployModeOption(app *v1beta2.SparkApplication) ([]string, error) {
 
 func mainClassOption(app *v1beta2.SparkApplication) ([]string, error) {
 	if app.Spec.MainClass == nil {
+		// Servers are run by their main class bundled with Spark by default.
+		if app.Spec.Server != nil {
+			return []string{"--class", util.GetServerMainClass(app)}, nil
+		}
 		return nil, nil
 	}
 	args := []string{
@@ -274,6 +366,19 @@ func sparkConfOption(app *v1beta2.SparkApplication) ([]string, error) {
 	return args, nil
 }
 
+// serverOption returns the Spark configuration property of the port the server of the application listens on.
+func serverOption(app *v1beta2.SparkApplication) ([]string, error) {
+	if app.Spec.Server == nil {
+		return nil, nil
+	}
+	key, _ := util.GetServerPortConf(app)
+	args := []string{
+		"--conf",
+		fmt.Sprintf("%s=%d", key, util.GetServerPort(app)),
+	}
+	return args, nil
+}
+
 func hadoopConfOption(app *v1beta2.SparkApplication) ([]string, error) {
 	if app.Spec.HadoopConf == nil {
 		return nil, nil
@@ -1030,6 +1135,9 @@ func proxyUserOption(app *v1beta2.SparkApplication) ([]string, error) {
 
 func mainApplicationFileOption(app *v1beta2.SparkApplication) ([]string, error) {
 	if app.Spec.MainApplicationFile == nil {
+		if app.Spec.Server != nil {
+			return []string{common.SparkInternalResource}, nil
+		}
 		return nil, nil
 	}
 	args := []string{*app.Spec.MainApplicationFile}
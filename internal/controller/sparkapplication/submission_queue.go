@@ -0,0 +1,334 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/internal/metrics"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+const (
+	// submissionRetryBaseDelay and submissionRetryMaxDelay bound the exponential backoff with which an application
+	// that could not be submitted, e.g. as the API server was unavailable, is enqueued again.
+	submissionRetryBaseDelay = 500 * time.Millisecond
+	submissionRetryMaxDelay  = time.Minute
+)
+
+// submissionQueueItem is a SparkApplication waiting in the submission queue.
+type submissionQueueItem struct {
+	key         types.NamespacedName
+	enqueueTime time.Time
+}
+
+// submissionQueue is a queue of SparkApplications waiting to be submitted. Applications of the
+// same namespace are dequeued in FIFO order, while namespaces are served in a round-robin manner
+// so that a burst of applications in one namespace does not starve the other namespaces.
+type submissionQueue struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+
+	// namespaces is the ring of namespaces that have applications waiting.
+	namespaces []string
+	next       int
+	items      map[string][]submissionQueueItem
+	// keys contains the applications that are either waiting or being submitted.
+	keys     map[types.NamespacedName]bool
+	length   int
+	shutdown bool
+
+	metrics *metrics.SubmissionMetrics
+}
+
+func newSubmissionQueue(metrics *metrics.SubmissionMetrics) *submissionQueue {
+	q := &submissionQueue{
+		items:   make(map[string][]submissionQueueItem),
+		keys:    make(map[types.NamespacedName]bool),
+		metrics: metrics,
+	}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// add adds the given application to the queue. It returns false if the application is already
+// waiting or being submitted.
+func (q *submissionQueue) add(key types.NamespacedName) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.shutdown || q.keys[key] {
+		return false
+	}
+	q.keys[key] = true
+
+	if len(q.items[key.Namespace]) == 0 {
+		q.namespaces = append(q.namespaces, key.Namespace)
+	}
+	q.items[key.Namespace] = append(q.items[key.Namespace], submissionQueueItem{key: key, enqueueTime: time.Now()})
+	q.length++
+	q.updateQueueDepth(key.Namespace)
+	q.cond.Signal()
+	return true
+}
+
+// get blocks until an application is available and returns it. The second return value is false
+// if the queue has been shut down.
+func (q *submissionQueue) get() (submissionQueueItem, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for q.length == 0 && !q.shutdown {
+		q.cond.Wait()
+	}
+	if q.shutdown {
+		return submissionQueueItem{}, false
+	}
+
+	if q.next >= len(q.namespaces) {
+		q.next = 0
+	}
+	namespace := q.namespaces[q.next]
+	item := q.items[namespace][0]
+	q.items[namespace] = q.items[namespace][1:]
+	q.length--
+	if len(q.items[namespace]) == 0 {
+		delete(q.items, namespace)
+		q.namespaces = append(q.namespaces[:q.next], q.namespaces[q.next+1:]...)
+	} else {
+		q.next++
+	}
+	q.updateQueueDepth(namespace)
+	return item, true
+}
+
+// done marks the given application as no longer being submitted.
+func (q *submissionQueue) done(key types.NamespacedName) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.keys, key)
+}
+
+// len returns the number of applications waiting in the queue.
+func (q *submissionQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.length
+}
+
+func (q *submissionQueue) shutDown() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.shutdown = true
+	q.cond.Broadcast()
+}
+
+func (q *submissionQueue) updateQueueDepth(namespace string) {
+	if q.metrics != nil {
+		q.metrics.SetQueueDepth(namespace, len(q.items[namespace]))
+	}
+}
+
+// submissionPool submits the SparkApplications in the submission queue with a bounded number of
+// workers, so that slow submissions do not block the reconcile workers of the controller.
+type submissionPool struct {
+	reconciler *Reconciler
+	// reader reads SparkApplications directly from the API server to avoid submitting an application
+	// twice based on a stale cache.
+	reader  client.Reader
+	queue   *submissionQueue
+	workers int
+	metrics *metrics.SubmissionMetrics
+	// rateLimiter delays enqueuing again the applications that could not be submitted.
+	rateLimiter workqueue.RateLimiter
+}
+
+// submissionPool implements manager.LeaderElectionRunnable.
+var _ manager.LeaderElectionRunnable = &submissionPool{}
+
+func newSubmissionPool(reconciler *Reconciler, reader client.Reader, workers int, metrics *metrics.SubmissionMetrics) *submissionPool {
+	return &submissionPool{
+		reconciler:  reconciler,
+		reader:      reader,
+		queue:       newSubmissionQueue(metrics),
+		workers:     workers,
+		metrics:     metrics,
+		rateLimiter: workqueue.NewItemExponentialFailureRateLimiter(submissionRetryBaseDelay, submissionRetryMaxDelay),
+	}
+}
+
+// Start implements manager.Runnable.
+func (p *submissionPool) Start(ctx context.Context) error {
+	logger.Info("Starting submission workers", "workers", p.workers)
+	var wg sync.WaitGroup
+	for i := 0; i < p.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p.processNextItem(ctx) {
+			}
+		}()
+	}
+
+	<-ctx.Done()
+	p.queue.shutDown()
+	wg.Wait()
+	logger.Info("Stopped submission workers")
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (p *submissionPool) NeedLeaderElection() bool {
+	return true
+}
+
+// enqueue adds the given SparkApplication to the submission queue.
+func (p *submissionPool) enqueue(key types.NamespacedName) {
+	if p.queue.add(key) {
+		logger.V(1).Info("Enqueued SparkApplication for submission", "name", key.Name, "namespace", key.Namespace)
+	}
+}
+
+func (p *submissionPool) processNextItem(ctx context.Context) bool {
+	item, ok := p.queue.get()
+	if !ok {
+		return false
+	}
+	if p.metrics != nil {
+		p.metrics.ObserveQueueWait(item.key.Namespace, time.Since(item.enqueueTime))
+	}
+	err := p.submit(ctx, item.key)
+	p.queue.done(item.key)
+	// The application is still pending submission if it could not be read or its status could not be
+	// updated, so it is enqueued again after a backoff to be submitted by the next available worker.
+	if err != nil {
+		delay := p.rateLimiter.When(item.key)
+		logger.V(1).Info("Enqueuing SparkApplication for submission again", "name", item.key.Name, "namespace", item.key.Namespace, "delay", delay)
+		time.AfterFunc(delay, func() { p.enqueue(item.key) })
+	} else {
+		p.rateLimiter.Forget(item.key)
+	}
+	return true
+}
+
+// submit submits the given SparkApplication if it is still pending submission and updates its status.
+// It returns an error if the application should be submitted again.
+func (p *submissionPool) submit(ctx context.Context, key types.NamespacedName) error {
+	r := p.reconciler
+	old := &v1beta2.SparkApplication{}
+	if err := p.reader.Get(ctx, key, old); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		logger.Error(err, "Failed to get SparkApplication", "name", key.Name, "namespace", key.Namespace)
+		return err
+	}
+	if old.Status.AppState.State != v1beta2.ApplicationStatePendingSubmission || !old.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	app := old.DeepCopy()
+
+	startTime := time.Now()
+	submitErr := r.submitSparkApplication(ctx, app)
+	if p.metrics != nil {
+		p.metrics.ObserveSubmission(key.Namespace, time.Since(startTime), submitErr)
+	}
+
+	var latest *v1beta2.SparkApplication
+	discarded := false
+	retryErr := retry.RetryOnConflict(
+		retry.DefaultRetry,
+		func() error {
+			latest = &v1beta2.SparkApplication{}
+			if err := p.reader.Get(ctx, key, latest); err != nil {
+				return err
+			}
+			// The application was changed while being submitted, e.g. its spec was updated and the
+			// application is being invalidated, so the result of this submission is discarded.
+			if latest.Status.AppState.State != v1beta2.ApplicationStatePendingSubmission {
+				logger.Info("Discarding submission result of SparkApplication", "name", key.Name, "namespace", key.Namespace, "state", latest.Status.AppState.State)
+				discarded = true
+				return nil
+			}
+			latest.Status = app.Status
+			return r.updateSparkApplicationStatus(ctx, latest)
+		},
+	)
+	if retryErr != nil {
+		if errors.IsNotFound(retryErr) {
+			latest = nil
+		} else {
+			logger.Error(retryErr, "Failed to update status of SparkApplication after submission", "name", key.Name, "namespace", key.Namespace)
+		}
+	}
+	// The resources created by a submission that is not recorded in the status of the application are
+	// never cleaned up by the reconciler, e.g. if the application was suspended or invalidated while
+	// being submitted, so they are deleted here.
+	if discarded || retryErr != nil {
+		// A different submission ID means that the application has been submitted again since.
+		resubmitted := latest != nil && latest.Status.SubmissionID != old.Status.SubmissionID
+		if err := p.discardSubmission(ctx, app, resubmitted); err != nil {
+			logger.Error(err, "Failed to delete resources of discarded submission", "name", key.Name, "namespace", key.Namespace, "submissionID", app.Status.SubmissionID)
+		}
+	}
+	if retryErr != nil && !errors.IsNotFound(retryErr) {
+		return retryErr
+	}
+	return nil
+}
+
+// discardSubmission deletes the resources created by the submission of the given SparkApplication, whose
+// result has not been recorded in the status of the application. The driver pod is only deleted if it
+// belongs to the discarded submission, while the web UI service and ingress, whose names do not depend on
+// the submission, are only deleted if the application has not been submitted again since.
+func (p *submissionPool) discardSubmission(ctx context.Context, app *v1beta2.SparkApplication, resubmitted bool) error {
+	r := p.reconciler
+	if app.Status.SubmissionID == "" {
+		return nil
+	}
+
+	pod := &corev1.Pod{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: app.Status.DriverInfo.PodName, Namespace: app.Namespace}, pod)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err == nil && pod.Labels[common.LabelSubmissionID] == app.Status.SubmissionID {
+		if err := r.deleteDriverPod(ctx, app, &client.DeleteOptions{GracePeriodSeconds: util.Int64Ptr(0)}); err != nil {
+			return err
+		}
+	}
+
+	if resubmitted {
+		return nil
+	}
+	if err := r.deleteWebUIService(ctx, app); err != nil {
+		return err
+	}
+	return r.deleteWebUIIngress(ctx, app)
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+)
+
+func TestSubmissionQueueFairness(t *testing.T) {
+	q := newSubmissionQueue(nil)
+	keys := []types.NamespacedName{
+		{Namespace: "ns-a", Name: "app-1"},
+		{Namespace: "ns-a", Name: "app-2"},
+		{Namespace: "ns-a", Name: "app-3"},
+		{Namespace: "ns-b", Name: "app-1"},
+		{Namespace: "ns-c", Name: "app-1"},
+		{Namespace: "ns-c", Name: "app-2"},
+	}
+	for _, key := range keys {
+		assert.True(t, q.add(key))
+	}
+	assert.Equal(t, len(keys), q.len())
+
+	expected := []types.NamespacedName{
+		{Namespace: "ns-a", Name: "app-1"},
+		{Namespace: "ns-b", Name: "app-1"},
+		{Namespace: "ns-c", Name: "app-1"},
+		{Namespace: "ns-a", Name: "app-2"},
+		{Namespace: "ns-c", Name: "app-2"},
+		{Namespace: "ns-a", Name: "app-3"},
+	}
+	for _, key := range expected {
+		item, ok := q.get()
+		assert.True(t, ok)
+		assert.Equal(t, key, item.key)
+	}
+	assert.Equal(t, 0, q.len())
+}
+
+func TestSubmissionQueueDeduplication(t *testing.T) {
+	q := newSubmissionQueue(nil)
+	key := types.NamespacedName{Namespace: "default", Name: "spark-pi"}
+
+	assert.True(t, q.add(key))
+	assert.False(t, q.add(key))
+	assert.Equal(t, 1, q.len())
+
+	// The application cannot be enqueued again while being submitted.
+	item, ok := q.get()
+	assert.True(t, ok)
+	assert.False(t, q.add(key))
+
+	q.done(item.key)
+	assert.True(t, q.add(key))
+}
+
+func TestSubmissionQueueShutDown(t *testing.T) {
+	q := newSubmissionQueue(nil)
+	done := make(chan bool)
+	go func() {
+		_, ok := q.get()
+		done <- ok
+	}()
+
+	q.shutDown()
+	assert.False(t, <-done)
+	assert.False(t, q.add(types.NamespacedName{Namespace: "default", Name: "spark-pi"}))
+}
+
+func TestSubmissionPoolDiscardSubmission(t *testing.T) {
+	newDriverPod := func(submissionID string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "spark-pi-driver",
+				Namespace: "default",
+				Labels:    map[string]string{common.LabelSubmissionID: submissionID},
+			},
+		}
+	}
+	newService := func() *corev1.Service {
+		return &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "spark-pi-ui-svc", Namespace: "default"}}
+	}
+	app := newAdmissionTestApp("spark-pi", v1beta2.ApplicationStateSubmitted, time.Now())
+	app.Status.SubmissionID = "discarded"
+	app.Status.DriverInfo.PodName = "spark-pi-driver"
+	app.Status.DriverInfo.WebUIServiceName = "spark-pi-ui-svc"
+
+	testCases := []struct {
+		name             string
+		driverPod        *corev1.Pod
+		resubmitted      bool
+		expectPodDeleted bool
+		expectSvcDeleted bool
+	}{
+		{
+			name:             "driver pod of the discarded submission",
+			driverPod:        newDriverPod("discarded"),
+			expectPodDeleted: true,
+			expectSvcDeleted: true,
+		},
+		{
+			name:             "driver pod of another submission",
+			driverPod:        newDriverPod("other"),
+			resubmitted:      true,
+			expectPodDeleted: false,
+			expectSvcDeleted: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newAdmissionTestReconciler(t, Options{}, tc.driverPod, newService())
+			p := newSubmissionPool(r, r.client, 1, nil)
+			assert.Nil(t, p.discardSubmission(context.TODO(), app, tc.resubmitted))
+
+			err := r.client.Get(context.TODO(), types.NamespacedName{Name: "spark-pi-driver", Namespace: "default"}, &corev1.Pod{})
+			assert.Equal(t, tc.expectPodDeleted, errors.IsNotFound(err))
+			err = r.client.Get(context.TODO(), types.NamespacedName{Name: "spark-pi-ui-svc", Namespace: "default"}, &corev1.Service{})
+			assert.Equal(t, tc.expectSvcDeleted, errors.IsNotFound(err))
+		})
+	}
+}
+
+func TestSubmissionPoolSubmitSkipsNonPendingApplication(t *testing.T) {
+	app := newAdmissionTestApp("spark-pi", v1beta2.ApplicationStateSuspended, time.Now())
+	r := newAdmissionTestReconciler(t, Options{}, app)
+	p := newSubmissionPool(r, r.client, 1, nil)
+	key := types.NamespacedName{Name: "spark-pi", Namespace: "default"}
+	assert.Nil(t, p.submit(context.TODO(), key))
+
+	p.queue.add(key)
+	assert.True(t, p.processNextItem(context.TODO()))
+	assert.Equal(t, 0, p.queue.len())
+}
+
+func TestSubmissionPoolRetryBackoff(t *testing.T) {
+	app := newAdmissionTestApp("spark-pi", v1beta2.ApplicationStateSuspended, time.Now())
+	r := newAdmissionTestReconciler(t, Options{}, app)
+	failing := true
+	reader := interceptor.NewClient(r.client.(client.WithWatch), interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if failing {
+				return errors.NewServiceUnavailable("unavailable")
+			}
+			return c.Get(ctx, key, obj, opts...)
+		},
+	})
+	p := newSubmissionPool(r, reader, 1, nil)
+	p.rateLimiter = workqueue.NewItemExponentialFailureRateLimiter(50*time.Millisecond, time.Second)
+	key := types.NamespacedName{Name: "spark-pi", Namespace: "default"}
+
+	// The application is enqueued again after a backoff rather than immediately.
+	p.queue.add(key)
+	assert.True(t, p.processNextItem(context.TODO()))
+	assert.Equal(t, 0, p.queue.len())
+	assert.Equal(t, 1, p.rateLimiter.NumRequeues(key))
+	assert.Eventually(t, func() bool { return p.queue.len() == 1 }, time.Second, 10*time.Millisecond)
+
+	// The backoff is reset once the application has been handled.
+	failing = false
+	assert.True(t, p.processNextItem(context.TODO()))
+	assert.Equal(t, 0, p.rateLimiter.NumRequeues(key))
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+)
+
+// Submitter launches the driver of a SparkApplication.
+type Submitter interface {
+	// Submit launches the driver of the given SparkApplication. The submission ID and the
+	// driver pod name in the application status must be set before calling it. Details of the
+	// submission, if any, are recorded in the LastSubmissionAttempt of the application status.
+	Submit(ctx context.Context, app *v1beta2.SparkApplication) error
+}
+
+// SparkSubmitter submits SparkApplications by running spark-submit.
+type SparkSubmitter struct{}
+
+// SparkSubmitter implements Submitter.
+var _ Submitter = &SparkSubmitter{}
+
+// NewSparkSubmitter creates a new SparkSubmitter instance.
+func NewSparkSubmitter() *SparkSubmitter {
+	return &SparkSubmitter{}
+}
+
+// Submit implements Submitter interface.
+func (s *SparkSubmitter) Submit(ctx context.Context, app *v1beta2.SparkApplication) error {
+	sparkSubmitArgs, err := buildSparkSubmitArgs(app)
+	if err != nil {
+		return fmt.Errorf("failed to build spark-submit arguments: %v", err)
+	}
+
+	// Try submitting the application by running spark-submit.
+	logger.Info("Running spark-submit for SparkApplication", "name", app.Name, "namespace", app.Namespace, "arguments", sparkSubmitArgs)
+	result, err := runSparkSubmit(ctx, newSubmission(sparkSubmitArgs, app))
+	if result != nil && app.Status.LastSubmissionAttempt != nil {
+		app.Status.LastSubmissionAttempt.ExitCode = result.exitCode
+		app.Status.LastSubmissionAttempt.Output = result.output
+	}
+	if err != nil {
+		return fmt.Errorf("failed to run spark-submit: %v", err)
+	}
+	return nil
+}
+
+// getSubmitterType returns the type of the submitter to use for the given SparkApplication. The submitter
+// specified in the application spec takes precedence over the default submitter of the controller.
+func (r *Reconciler) getSubmitterType(app *v1beta2.SparkApplication) v1beta2.SubmitterType {
+	submitterType := v1beta2.SubmitterTypeSparkSubmit
+	if r.options.DefaultSubmitter != "" {
+		submitterType = v1beta2.SubmitterType(r.options.DefaultSubmitter)
+	}
+	if app.Spec.Submitter != nil && *app.Spec.Submitter != "" {
+		submitterType = *app.Spec.Submitter
+	}
+	return submitterType
+}
+
+// getSubmitter returns the submitter to use for the given SparkApplication.
+func (r *Reconciler) getSubmitter(app *v1beta2.SparkApplication) (Submitter, error) {
+	submitterType := r.getSubmitterType(app)
+	switch submitterType {
+	case v1beta2.SubmitterTypeSparkSubmit:
+		return NewSparkSubmitter(), nil
+	case v1beta2.SubmitterTypeNative:
+		return NewNativeSubmitter(r.client), nil
+	default:
+		return nil, fmt.Errorf("unsupported submitter %q", submitterType)
+	}
+}
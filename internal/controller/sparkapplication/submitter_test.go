@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// fakeSparkHome creates a SPARK_HOME whose spark-submit runs the given shell script.
+func fakeSparkHome(t *testing.T, script string) {
+	sparkHome := t.TempDir()
+	binDir := filepath.Join(sparkHome, "bin")
+	assert.Nil(t, os.MkdirAll(binDir, 0755))
+	assert.Nil(t, os.WriteFile(filepath.Join(binDir, "spark-submit"), []byte("#!/bin/sh\n"+script), 0755))
+	t.Setenv(common.EnvSparkHome, sparkHome)
+}
+
+func TestRunSparkSubmit(t *testing.T) {
+	fakeSparkHome(t, "echo 'submitted'\nexit 0\n")
+	result, err := runSparkSubmit(context.TODO(), &submission{})
+	assert.Nil(t, err)
+	assert.Equal(t, util.Int32Ptr(0), result.exitCode)
+	assert.Equal(t, "submitted\n", result.output)
+}
+
+func TestRunSparkSubmitFailure(t *testing.T) {
+	fakeSparkHome(t, "echo 'resolving dependencies'\necho 'invalid argument' >&2\nexit 1\n")
+	result, err := runSparkSubmit(context.TODO(), &submission{})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "invalid argument")
+	assert.NotContains(t, err.Error(), "resolving dependencies")
+	assert.Equal(t, util.Int32Ptr(1), result.exitCode)
+	// Standard output and standard error are read concurrently, so their order is not guaranteed.
+	assert.Contains(t, result.output, "resolving dependencies\n")
+	assert.Contains(t, result.output, "invalid argument\n")
+}
+
+func TestRunSparkSubmitTimeout(t *testing.T) {
+	// The child process keeps stdout open, so spark-submit only returns early if the whole
+	// process group is killed.
+	fakeSparkHome(t, "sleep 60 &\nwait\n")
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 500*time.Millisecond)
+	defer cancel()
+	startTime := time.Now()
+	result, err := runSparkSubmit(ctx, &submission{})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "spark-submit was killed")
+	assert.Less(t, time.Since(startTime), sparkSubmitWaitDelay)
+	assert.Nil(t, result.exitCode)
+}
+
+func TestSparkSubmitterRecordsOutput(t *testing.T) {
+	t.Setenv(common.EnvKubernetesServiceHost, "127.0.0.1")
+	t.Setenv(common.EnvKubernetesServicePort, "443")
+	fakeSparkHome(t, "echo 'connection refused' >&2\nexit 2\n")
+
+	app := &v1beta2.SparkApplication{
+		Spec: v1beta2.SparkApplicationSpec{
+			Type:                v1beta2.SparkApplicationTypeScala,
+			Mode:                v1beta2.DeployModeCluster,
+			Image:               util.StringPtr("spark:3.5.2"),
+			MainApplicationFile: util.StringPtr("local:///opt/spark/examples/jars/spark-examples.jar"),
+		},
+		Status: v1beta2.SparkApplicationStatus{
+			SubmissionAttempts:    3,
+			LastSubmissionAttempt: &v1beta2.SubmissionAttemptInfo{Attempt: 3},
+		},
+	}
+	err := NewSparkSubmitter().Submit(context.TODO(), app)
+	assert.NotNil(t, err)
+	assert.Equal(t, &v1beta2.SubmissionAttemptInfo{
+		Attempt:  3,
+		ExitCode: util.Int32Ptr(2),
+		Output:   "connection refused\n",
+	}, app.Status.LastSubmissionAttempt)
+}
+
+func TestTailBuffer(t *testing.T) {
+	b := newTailBuffer(8)
+	_, _ = b.Write([]byte("abcd"))
+	assert.Equal(t, "abcd", b.String())
+	_, _ = b.Write([]byte("efghij"))
+	assert.Equal(t, "cdefghij", b.String())
+
+	// A multi-byte character cut by truncation is dropped.
+	b = newTailBuffer(4)
+	_, _ = b.Write([]byte("a\u00e9bcd"))
+	assert.Equal(t, "bcd", b.String())
+}
+
+func TestGetSubmissionTimeout(t *testing.T) {
+	r := &Reconciler{options: Options{SubmissionTimeout: 5 * time.Minute}}
+	app := &v1beta2.SparkApplication{}
+	assert.Equal(t, 5*time.Minute, r.getSubmissionTimeout(app))
+
+	app.Spec.SubmissionTimeoutSeconds = util.Int64Ptr(30)
+	assert.Equal(t, 30*time.Second, r.getSubmissionTimeout(app))
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// reconcileSuspendedSparkApplication resubmits a suspended SparkApplication once it is no longer requested to be
+// suspended. Unsetting Suspend normally invalidates the application already, as for any spec change, so this only
+// catches up on missed spec updates.
+func (r *Reconciler) reconcileSuspendedSparkApplication(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	key := req.NamespacedName
+	retryErr := retry.RetryOnConflict(
+		retry.DefaultRetry,
+		func() error {
+			old, err := r.getSparkApplication(key)
+			if err != nil {
+				return err
+			}
+			if old.Status.AppState.State != v1beta2.ApplicationStateSuspended || util.IsSuspended(old) {
+				return nil
+			}
+			app := old.DeepCopy()
+
+			markPendingRerun(app, v1beta2.ApplicationStateReasonResumed)
+			if err := r.updateSparkApplicationStatus(ctx, app); err != nil {
+				return err
+			}
+			return nil
+		},
+	)
+	if retryErr != nil {
+		logger.Error(retryErr, "Failed to reconcile SparkApplication", "name", key.Name, "namespace", key.Namespace)
+		return ctrl.Result{}, retryErr
+	}
+	return ctrl.Result{}, nil
+}
+
+// suspendSparkApplication moves the given SparkApplication, whose resources have been deleted if it had been
+// submitted already, to the SUSPENDED state.
+func (r *Reconciler) suspendSparkApplication(app *v1beta2.SparkApplication) {
+	app.Status.AppState = v1beta2.ApplicationState{
+		State: v1beta2.ApplicationStateSuspended,
+	}
+	r.recordSparkApplicationEvent(app)
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+func reconcileSuspendTestApp(t *testing.T, r *Reconciler, reconcile func(context.Context, ctrl.Request) (ctrl.Result, error)) *v1beta2.SparkApplication {
+	key := types.NamespacedName{Namespace: "default", Name: "app"}
+	_, err := reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+	assert.Nil(t, err)
+
+	app := &v1beta2.SparkApplication{}
+	assert.Nil(t, r.client.Get(context.TODO(), key, app))
+	return app
+}
+
+func TestReconcileNewSuspendedSparkApplication(t *testing.T) {
+	app := newAdmissionTestApp("app", v1beta2.ApplicationStateNew, time.Now())
+	app.Spec.Suspend = util.BoolPtr(true)
+	r := newAdmissionTestReconciler(t, Options{}, app)
+	r.recorder = record.NewFakeRecorder(10)
+
+	app = reconcileSuspendTestApp(t, r, r.reconcileNewSparkApplication)
+	assert.Equal(t, v1beta2.ApplicationStateSuspended, app.Status.AppState.State)
+	assert.Equal(t, int32(0), app.Status.SubmissionAttempts)
+}
+
+func TestReconcileInvalidatingSuspendedSparkApplication(t *testing.T) {
+	app := newAdmissionTestApp("app", v1beta2.ApplicationStateInvalidating, time.Now())
+	app.Spec.Suspend = util.BoolPtr(true)
+	app.Status.DriverInfo.PodName = "app-driver"
+	app.Status.ExecutionAttempts = 1
+	driverPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app-driver"}}
+	r := newAdmissionTestReconciler(t, Options{}, app, driverPod)
+	r.recorder = record.NewFakeRecorder(10)
+
+	app = reconcileSuspendTestApp(t, r, r.reconcileInvalidatingSparkApplication)
+	assert.Equal(t, v1beta2.ApplicationStateSuspended, app.Status.AppState.State)
+	assert.Equal(t, int32(0), app.Status.ExecutionAttempts)
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "app-driver"}, &corev1.Pod{})
+	assert.True(t, errors.IsNotFound(err))
+}
+
+func TestReconcileSuspendedSparkApplication(t *testing.T) {
+	app := newAdmissionTestApp("app", v1beta2.ApplicationStateSuspended, time.Now())
+	app.Spec.Suspend = util.BoolPtr(true)
+	r := newAdmissionTestReconciler(t, Options{}, app)
+
+	// The application stays suspended as long as it is requested to be.
+	app = reconcileSuspendTestApp(t, r, r.reconcileSuspendedSparkApplication)
+	assert.Equal(t, v1beta2.ApplicationStateSuspended, app.Status.AppState.State)
+
+	app.Spec.Suspend = nil
+	assert.Nil(t, r.client.Update(context.TODO(), app))
+	app = reconcileSuspendTestApp(t, r, r.reconcileSuspendedSparkApplication)
+	assert.Equal(t, v1beta2.ApplicationStatePendingRerun, app.Status.AppState.State)
+}
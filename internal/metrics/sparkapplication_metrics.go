@@ -18,6 +18,7 @@ package metrics
 
 import (
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -31,6 +32,7 @@ import (
 type SparkApplicationMetrics struct {
 	prefix                 string
 	labels                 []string
+	failureLabels          []string
 	jobStartLatencyBuckets []float64
 
 	count                 *prometheus.CounterVec
@@ -54,9 +56,16 @@ func NewSparkApplicationMetrics(prefix string, labels []string, jobStartLatencyB
 		validLabels = append(validLabels, validLabel)
 	}
 
+	// The failure metrics are additionally labeled with the reason of the failures.
+	failureLabels := validLabels
+	if !slices.Contains(validLabels, common.MetricLabelFailureReason) {
+		failureLabels = append(slices.Clip(validLabels), common.MetricLabelFailureReason)
+	}
+
 	return &SparkApplicationMetrics{
 		prefix:                 prefix,
 		labels:                 validLabels,
+		failureLabels:          failureLabels,
 		jobStartLatencyBuckets: jobStartLatencyBuckets,
 
 		count: prometheus.NewCounterVec(
@@ -78,7 +87,7 @@ func NewSparkApplicationMetrics(prefix string, labels []string, jobStartLatencyB
 				Name: util.CreateValidMetricNameLabel(prefix, common.MetricSparkApplicationFailedSubmissionCount),
 				Help: "Total number of failed SparkApplication submission",
 			},
-			validLabels,
+			failureLabels,
 		),
 		runningCount: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
@@ -99,7 +108,7 @@ func NewSparkApplicationMetrics(prefix string, labels []string, jobStartLatencyB
 				Name: util.CreateValidMetricNameLabel(prefix, common.MetricSparkApplicationFailureCount),
 				Help: "Total number of failed SparkApplication",
 			},
-			validLabels,
+			failureLabels,
 		),
 		successExecutionTimeSeconds: prometheus.NewSummaryVec(
 			prometheus.SummaryOpts{
@@ -111,7 +120,7 @@ func NewSparkApplicationMetrics(prefix string, labels []string, jobStartLatencyB
 			prometheus.SummaryOpts{
 				Name: util.CreateValidMetricNameLabel(prefix, common.MetricSparkApplicationFailureExecutionTimeSeconds),
 			},
-			validLabels,
+			failureLabels,
 		),
 		startLatencySeconds: prometheus.NewSummaryVec(
 			prometheus.SummaryOpts{
@@ -248,7 +257,7 @@ func (m *SparkApplicationMetrics) incSubmitCount(app *v1beta2.SparkApplication)
 }
 
 func (m *SparkApplicationMetrics) incFailedSubmissionCount(app *v1beta2.SparkApplication) {
-	labels := m.getMetricLabels(app)
+	labels := m.getFailureMetricLabels(app)
 	counter, err := m.failedSubmissionCount.GetMetricWith(labels)
 	if err != nil {
 		logger.Error(err, "Failed to collect metric for SparkApplication", "name", app.Name, "namespace", app.Namespace, "metric", common.MetricSparkApplicationFailedSubmissionCount, "labels", labels)
@@ -296,7 +305,7 @@ func (m *SparkApplicationMetrics) incSuccessCount(app *v1beta2.SparkApplication)
 }
 
 func (m *SparkApplicationMetrics) incFailureCount(app *v1beta2.SparkApplication) {
-	labels := m.getMetricLabels(app)
+	labels := m.getFailureMetricLabels(app)
 	counter, err := m.failureCount.GetMetricWith(labels)
 	if err != nil {
 		logger.Error(err, "Failed to collect metric for SparkApplication", "name", app.Name, "namespace", app.Namespace, "metric", common.MetricSparkApplicationFailureCount, "labels", labels)
@@ -325,7 +334,7 @@ func (m *SparkApplicationMetrics) observeSuccessExecutionTimeSeconds(app *v1beta
 }
 
 func (m *SparkApplicationMetrics) observeFailureExecutionTimeSeconds(app *v1beta2.SparkApplication) {
-	labels := m.getMetricLabels(app)
+	labels := m.getFailureMetricLabels(app)
 	observer, err := m.failureExecutionTimeSeconds.GetMetricWith(labels)
 	if err != nil {
 		logger.Error(err, "Failed to collect metric for SparkApplication", "name", app.Name, "namespace", app.Namespace, "metric", common.MetricSparkApplicationFailureExecutionTimeSeconds, "labels", labels)
@@ -384,3 +393,11 @@ func (m *SparkApplicationMetrics) getMetricLabels(app *v1beta2.SparkApplication)
 	}
 	return metricLabels
 }
+
+// getFailureMetricLabels returns the labels of the failure metrics of the given SparkApplication, which include
+// the reason of its failure.
+func (m *SparkApplicationMetrics) getFailureMetricLabels(app *v1beta2.SparkApplication) map[string]string {
+	metricLabels := m.getMetricLabels(app)
+	metricLabels[common.MetricLabelFailureReason] = string(util.GetFailureReason(app))
+	return metricLabels
+}
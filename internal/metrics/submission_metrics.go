@@ -0,0 +1,117 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// SubmissionMetrics holds the metrics of the asynchronous submission of SparkApplications.
+type SubmissionMetrics struct {
+	prefix string
+
+	queueDepth         *prometheus.GaugeVec
+	queueWaitSeconds   *prometheus.HistogramVec
+	submissionDuration *prometheus.HistogramVec
+}
+
+func NewSubmissionMetrics(prefix string) *SubmissionMetrics {
+	// Buckets from 1 second to about 8.5 minutes.
+	latencyBuckets := prometheus.ExponentialBuckets(1, 2, 10)
+
+	return &SubmissionMetrics{
+		prefix: prefix,
+
+		queueDepth: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: util.CreateValidMetricNameLabel(prefix, common.MetricSparkApplicationSubmissionQueueDepth),
+				Help: "Number of SparkApplication waiting in the submission queue",
+			},
+			[]string{"namespace"},
+		),
+		queueWaitSeconds: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    util.CreateValidMetricNameLabel(prefix, common.MetricSparkApplicationSubmissionQueueWaitSeconds),
+				Help:    "Time SparkApplication spent in the submission queue before being submitted",
+				Buckets: latencyBuckets,
+			},
+			[]string{"namespace"},
+		),
+		submissionDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    util.CreateValidMetricNameLabel(prefix, common.MetricSparkApplicationSubmissionLatencySeconds),
+				Help:    "Time taken to submit SparkApplication",
+				Buckets: latencyBuckets,
+			},
+			[]string{"namespace", "result"},
+		),
+	}
+}
+
+func (m *SubmissionMetrics) Register() {
+	if err := metrics.Registry.Register(m.queueDepth); err != nil {
+		logger.Error(err, "Failed to register submission metric", "name", common.MetricSparkApplicationSubmissionQueueDepth)
+	}
+	if err := metrics.Registry.Register(m.queueWaitSeconds); err != nil {
+		logger.Error(err, "Failed to register submission metric", "name", common.MetricSparkApplicationSubmissionQueueWaitSeconds)
+	}
+	if err := metrics.Registry.Register(m.submissionDuration); err != nil {
+		logger.Error(err, "Failed to register submission metric", "name", common.MetricSparkApplicationSubmissionLatencySeconds)
+	}
+}
+
+// SetQueueDepth sets the number of SparkApplications waiting in the submission queue of the given namespace.
+func (m *SubmissionMetrics) SetQueueDepth(namespace string, depth int) {
+	gauge, err := m.queueDepth.GetMetricWithLabelValues(namespace)
+	if err != nil {
+		logger.Error(err, "Failed to collect submission metric", "namespace", namespace, "metric", common.MetricSparkApplicationSubmissionQueueDepth)
+		return
+	}
+	gauge.Set(float64(depth))
+}
+
+// ObserveQueueWait records the time a SparkApplication spent in the submission queue.
+func (m *SubmissionMetrics) ObserveQueueWait(namespace string, wait time.Duration) {
+	observer, err := m.queueWaitSeconds.GetMetricWithLabelValues(namespace)
+	if err != nil {
+		logger.Error(err, "Failed to collect submission metric", "namespace", namespace, "metric", common.MetricSparkApplicationSubmissionQueueWaitSeconds)
+		return
+	}
+	observer.Observe(wait.Seconds())
+	logger.V(1).Info("Observed spark application submission queue wait seconds", "namespace", namespace, "metric", common.MetricSparkApplicationSubmissionQueueWaitSeconds, "value", wait.Seconds())
+}
+
+// ObserveSubmission records the time taken to submit a SparkApplication.
+func (m *SubmissionMetrics) ObserveSubmission(namespace string, duration time.Duration, submitErr error) {
+	result := "success"
+	if submitErr != nil {
+		result = "failure"
+	}
+	observer, err := m.submissionDuration.GetMetricWithLabelValues(namespace, result)
+	if err != nil {
+		logger.Error(err, "Failed to collect submission metric", "namespace", namespace, "metric", common.MetricSparkApplicationSubmissionLatencySeconds)
+		return
+	}
+	observer.Observe(duration.Seconds())
+	logger.V(1).Info("Observed spark application submission latency seconds", "namespace", namespace, "metric", common.MetricSparkApplicationSubmissionLatencySeconds, "result", result, "value", duration.Seconds())
+}
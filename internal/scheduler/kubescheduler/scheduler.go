@@ -20,7 +20,6 @@ import (
 	"context"
 	"fmt"
 
-	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -30,7 +29,7 @@ import (
 
 	"github.com/kubeflow/spark-operator/api/v1beta2"
 	"github.com/kubeflow/spark-operator/internal/scheduler"
-	"github.com/kubeflow/spark-operator/pkg/util"
+	"github.com/kubeflow/spark-operator/pkg/resourceusage"
 )
 
 const (
@@ -85,9 +84,19 @@ func (s *Scheduler) ShouldSchedule(app *v1beta2.SparkApplication) bool {
 	return true
 }
 
+// Admit implements scheduler.Interface.
+func (s *Scheduler) Admit(_ *v1beta2.SparkApplication) (v1beta2.BatchSchedulerAdmissionPhase, string, error) {
+	// The coscheduling plugin only gangs the pods of a PodGroup once they have been created,
+	// so the application is admitted directly.
+	return v1beta2.BatchSchedulerAdmissionAdmitted, "", nil
+}
+
 // Schedule implements scheduler.Interface.
 func (s *Scheduler) Schedule(app *v1beta2.SparkApplication) error {
-	minResources := util.SumResourceList([]corev1.ResourceList{util.GetDriverRequestResource(app), util.GetExecutorRequestResource(app)})
+	minResources, err := resourceusage.GangMinResources(app)
+	if err != nil {
+		return fmt.Errorf("failed to calculate minimum resources of pod group: %v", err)
+	}
 	podGroup := &schedulingv1alpha1.PodGroup{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      getPodGroupName(app),
@@ -97,7 +106,7 @@ func (s *Scheduler) Schedule(app *v1beta2.SparkApplication) error {
 			},
 		},
 		Spec: schedulingv1alpha1.PodGroupSpec{
-			MinMember:    1,
+			MinMember:    resourceusage.GangMinMember(app),
 			MinResources: minResources,
 		},
 	}
@@ -139,7 +148,8 @@ func (s *Scheduler) syncPodGroup(podGroup *schedulingv1alpha1.PodGroup) error {
 		Name:      podGroup.Name,
 	}
 
-	if err := s.client.Get(context.TODO(), key, &schedulingv1alpha1.PodGroup{}); err != nil {
+	existing := &schedulingv1alpha1.PodGroup{}
+	if err := s.client.Get(context.TODO(), key, existing); err != nil {
 		if !errors.IsNotFound(err) {
 			return err
 		}
@@ -151,6 +161,7 @@ func (s *Scheduler) syncPodGroup(podGroup *schedulingv1alpha1.PodGroup) error {
 		return nil
 	}
 
+	podGroup.ResourceVersion = existing.ResourceVersion
 	if err := s.client.Update(context.TODO(), podGroup); err != nil {
 		return err
 	}
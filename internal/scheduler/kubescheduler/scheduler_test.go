@@ -0,0 +1,144 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubescheduler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	schedulingv1alpha1 "sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+func newTestScheduler(t *testing.T) *Scheduler {
+	scheme := runtime.NewScheme()
+	assert.Nil(t, schedulingv1alpha1.AddToScheme(scheme))
+	return &Scheduler{name: Name, client: fake.NewClientBuilder().WithScheme(scheme).Build()}
+}
+
+func newTestApp(mode v1beta2.DeployMode, gangPolicy v1beta2.GangPolicy) *v1beta2.SparkApplication {
+	return &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "spark-pi",
+			Namespace: "default",
+		},
+		Spec: v1beta2.SparkApplicationSpec{
+			Type: v1beta2.SparkApplicationTypeScala,
+			Mode: mode,
+			Driver: v1beta2.DriverSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{Cores: util.Int32Ptr(1), Memory: util.StringPtr("1g")},
+			},
+			Executor: v1beta2.ExecutorSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{Cores: util.Int32Ptr(2), Memory: util.StringPtr("2g")},
+			},
+			DynamicAllocation: &v1beta2.DynamicAllocation{
+				Enabled:          true,
+				InitialExecutors: util.Int32Ptr(4),
+				MinExecutors:     util.Int32Ptr(1),
+				MaxExecutors:     util.Int32Ptr(10),
+			},
+			BatchSchedulerOptions: &v1beta2.BatchSchedulerConfiguration{
+				GangPolicy: &gangPolicy,
+			},
+		},
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	testCases := []struct {
+		name              string
+		app               *v1beta2.SparkApplication
+		expectedMinMember int32
+		expectedCPU       string
+		expectedMemory    string
+	}{
+		{
+			name:              "initial executors in cluster mode",
+			app:               newTestApp(v1beta2.DeployModeCluster, v1beta2.GangPolicyInitial),
+			expectedMinMember: 1,
+			expectedCPU:       "9",
+			expectedMemory:    "11136Mi",
+		},
+		{
+			name:              "minimum executors in cluster mode",
+			app:               newTestApp(v1beta2.DeployModeCluster, v1beta2.GangPolicyMinimum),
+			expectedMinMember: 1,
+			expectedCPU:       "3",
+			expectedMemory:    "3840Mi",
+		},
+		{
+			name:              "initial executors in client mode",
+			app:               newTestApp(v1beta2.DeployModeClient, v1beta2.GangPolicyInitial),
+			expectedMinMember: 4,
+			expectedCPU:       "8",
+			expectedMemory:    "9728Mi",
+		},
+		{
+			name:              "minimum executors in client mode",
+			app:               newTestApp(v1beta2.DeployModeClient, v1beta2.GangPolicyMinimum),
+			expectedMinMember: 1,
+			expectedCPU:       "2",
+			expectedMemory:    "2432Mi",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newTestScheduler(t)
+			assert.Nil(t, s.Schedule(tc.app))
+			assert.Equal(t, "spark-pi-pg", tc.app.Labels[schedulingv1alpha1.PodGroupLabel])
+
+			podGroup := &schedulingv1alpha1.PodGroup{}
+			assert.Nil(t, s.client.Get(context.TODO(), types.NamespacedName{Name: "spark-pi-pg", Namespace: "default"}, podGroup))
+			assert.Equal(t, tc.expectedMinMember, podGroup.Spec.MinMember)
+			cpu := podGroup.Spec.MinResources[corev1.ResourceCPU]
+			memory := podGroup.Spec.MinResources[corev1.ResourceMemory]
+			assert.Equal(t, 0, cpu.Cmp(resource.MustParse(tc.expectedCPU)), "expected cpu %s, got %s", tc.expectedCPU, cpu.String())
+			assert.Equal(t, 0, memory.Cmp(resource.MustParse(tc.expectedMemory)), "expected memory %s, got %s", tc.expectedMemory, memory.String())
+		})
+	}
+}
+
+func TestScheduleUpdateAndCleanup(t *testing.T) {
+	s := newTestScheduler(t)
+	app := newTestApp(v1beta2.DeployModeCluster, v1beta2.GangPolicyInitial)
+	assert.Nil(t, s.Schedule(app))
+
+	// The PodGroup of a retried application is updated to its current gang policy.
+	*app.Spec.BatchSchedulerOptions.GangPolicy = v1beta2.GangPolicyMinimum
+	assert.Nil(t, s.Schedule(app))
+	podGroup := &schedulingv1alpha1.PodGroup{}
+	key := types.NamespacedName{Name: "spark-pi-pg", Namespace: "default"}
+	assert.Nil(t, s.client.Get(context.TODO(), key, podGroup))
+	cpu := podGroup.Spec.MinResources[corev1.ResourceCPU]
+	assert.Equal(t, 0, cpu.Cmp(resource.MustParse("3")), "expected cpu 3, got %s", cpu.String())
+
+	assert.Nil(t, s.Cleanup(app))
+	assert.True(t, errors.IsNotFound(s.client.Get(context.TODO(), key, podGroup)))
+	// Cleaning up an application without a PodGroup is a no-op.
+	assert.Nil(t, s.Cleanup(app))
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kueue
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/internal/scheduler"
+)
+
+const (
+	SchedulerName = "kueue"
+)
+
+var (
+	logger = log.Log.WithName("")
+)
+
+// Scheduler is a batch scheduler that queues Spark applications with Kueue. A Workload is created for every
+// application, which is only submitted once Kueue has admitted it.
+// Ref: https://kueue.sigs.k8s.io.
+type Scheduler struct {
+	client client.Client
+}
+
+// Scheduler implements scheduler.Interface.
+var _ scheduler.Interface = &Scheduler{}
+
+// Scheduler implements scheduler.Evictor.
+var _ scheduler.Evictor = &Scheduler{}
+
+// Config defines the configurations of Kueue.
+type Config struct {
+	Client client.Client
+}
+
+// Config implements scheduler.Config.
+var _ scheduler.Config = &Config{}
+
+// Factory creates a new Scheduler instance.
+func Factory(config scheduler.Config) (scheduler.Interface, error) {
+	c, ok := config.(*Config)
+	if !ok {
+		return nil, fmt.Errorf("failed to get kueue config")
+	}
+
+	scheduler := &Scheduler{
+		client: c.Client,
+	}
+	return scheduler, nil
+}
+
+// Name implements scheduler.Interface.
+func (s *Scheduler) Name() string {
+	return SchedulerName
+}
+
+// ShouldSchedule implements scheduler.Interface.
+func (s *Scheduler) ShouldSchedule(_ *v1beta2.SparkApplication) bool {
+	// There is no additional requirements for scheduling.
+	return true
+}
+
+// Schedule implements scheduler.Interface. The application has been admitted, so its pods are constrained to
+// the nodes of the resource flavors Kueue has assigned to them.
+func (s *Scheduler) Schedule(app *v1beta2.SparkApplication) error {
+	wl, err := s.getWorkload(app)
+	if err != nil {
+		return fmt.Errorf("failed to get workload: %v", err)
+	}
+	if wl == nil || wl.Status.Admission == nil {
+		return fmt.Errorf("workload %s has not been admitted", getWorkloadName(app))
+	}
+
+	for _, assignment := range wl.Status.Admission.PodSetAssignments {
+		nodeLabels, err := s.getFlavorNodeLabels(assignment.Flavors)
+		if err != nil {
+			return err
+		}
+		if len(nodeLabels) == 0 {
+			continue
+		}
+		var podSpec *v1beta2.SparkPodSpec
+		switch assignment.Name {
+		case driverPodSetName:
+			podSpec = &app.Spec.Driver.SparkPodSpec
+		case executorPodSetName:
+			podSpec = &app.Spec.Executor.SparkPodSpec
+		default:
+			continue
+		}
+		if podSpec.NodeSelector == nil {
+			podSpec.NodeSelector = make(map[string]string)
+		}
+		for key, value := range nodeLabels {
+			podSpec.NodeSelector[key] = value
+		}
+	}
+	return nil
+}
+
+// Cleanup implements scheduler.Interface.
+func (s *Scheduler) Cleanup(app *v1beta2.SparkApplication) error {
+	wl := &unstructured.Unstructured{}
+	wl.SetGroupVersionKind(workloadGVK)
+	wl.SetNamespace(app.Namespace)
+	wl.SetName(getWorkloadName(app))
+	if err := s.client.Delete(context.TODO(), wl); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	logger.Info("Deleted Workload", "Name", wl.GetName(), "Namespace", wl.GetNamespace())
+	return nil
+}
+
+// Admit implements scheduler.Interface. The Workload of the application is created if it does not exist yet.
+func (s *Scheduler) Admit(app *v1beta2.SparkApplication) (v1beta2.BatchSchedulerAdmissionPhase, string, error) {
+	wl, err := s.getWorkload(app)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get workload: %v", err)
+	}
+	if wl == nil {
+		wl, err = newWorkload(app)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to build workload: %v", err)
+		}
+		obj, err := toUnstructured(wl)
+		if err != nil {
+			return "", "", err
+		}
+		if err := s.client.Create(context.TODO(), obj); err != nil {
+			return "", "", fmt.Errorf("failed to create workload: %v", err)
+		}
+		logger.Info("Created Workload", "Name", wl.Name, "Namespace", wl.Namespace, "queue", wl.Spec.QueueName)
+	}
+
+	if meta.IsStatusConditionTrue(wl.Status.Conditions, workloadAdmitted) {
+		return v1beta2.BatchSchedulerAdmissionAdmitted, "", nil
+	}
+	if wl.Spec.Active != nil && !*wl.Spec.Active {
+		return v1beta2.BatchSchedulerAdmissionRejected, fmt.Sprintf("workload %s was deactivated", wl.Name), nil
+	}
+	reason := fmt.Sprintf("waiting for workload %s to be admitted by Kueue in queue %s", wl.Name, wl.Spec.QueueName)
+	if condition := meta.FindStatusCondition(wl.Status.Conditions, workloadQuotaReserved); condition != nil && condition.Message != "" {
+		reason = fmt.Sprintf("%s: %s", reason, condition.Message)
+	}
+	return v1beta2.BatchSchedulerAdmissionPending, reason, nil
+}
+
+// Evicted implements scheduler.Evictor.
+func (s *Scheduler) Evicted(app *v1beta2.SparkApplication) (bool, string, error) {
+	wl, err := s.getWorkload(app)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to get workload: %v", err)
+	}
+	if wl == nil {
+		return false, "", nil
+	}
+	condition := meta.FindStatusCondition(wl.Status.Conditions, workloadEvicted)
+	if condition == nil || condition.Status != metav1.ConditionTrue {
+		return false, "", nil
+	}
+	return true, fmt.Sprintf("workload %s was evicted by Kueue: %s", wl.Name, condition.Message), nil
+}
+
+// getWorkload returns the Workload of the given application, or nil if it does not exist.
+func (s *Scheduler) getWorkload(app *v1beta2.SparkApplication) (*workload, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(workloadGVK)
+	key := types.NamespacedName{Namespace: app.Namespace, Name: getWorkloadName(app)}
+	if err := s.client.Get(context.TODO(), key, obj); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	// A Workload left over from a previous run of an application with the same name is not reused.
+	if !metav1.IsControlledBy(obj, app) {
+		return nil, fmt.Errorf("workload %s is not owned by SparkApplication %s", key.Name, app.Name)
+	}
+	return fromUnstructured(obj)
+}
+
+// getFlavorNodeLabels returns the node labels of the given resource flavors.
+func (s *Scheduler) getFlavorNodeLabels(flavors map[corev1.ResourceName]string) (map[string]string, error) {
+	nodeLabels := make(map[string]string)
+	for _, name := range flavors {
+		flavor := &unstructured.Unstructured{}
+		flavor.SetGroupVersionKind(resourceFlavorGVK)
+		if err := s.client.Get(context.TODO(), types.NamespacedName{Name: name}, flavor); err != nil {
+			return nil, fmt.Errorf("failed to get resource flavor %s: %v", name, err)
+		}
+		labels, _, err := unstructured.NestedStringMap(flavor.Object, "spec", "nodeLabels")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get node labels of resource flavor %s: %v", name, err)
+		}
+		for key, value := range labels {
+			nodeLabels[key] = value
+		}
+	}
+	return nodeLabels, nil
+}
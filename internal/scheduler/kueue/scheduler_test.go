@@ -0,0 +1,249 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kueue
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+func newTestApp() *v1beta2.SparkApplication {
+	return &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "spark-pi",
+			Namespace: "default",
+			UID:       types.UID("spark-pi-uid"),
+			Labels:    map[string]string{queueNameLabel: "team-a"},
+		},
+		Spec: v1beta2.SparkApplicationSpec{
+			Type: v1beta2.SparkApplicationTypeScala,
+			Driver: v1beta2.DriverSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{
+					Cores:  util.Int32Ptr(1),
+					Memory: util.StringPtr("512m"),
+				},
+			},
+			Executor: v1beta2.ExecutorSpec{
+				Instances: util.Int32Ptr(2),
+				SparkPodSpec: v1beta2.SparkPodSpec{
+					Cores:  util.Int32Ptr(2),
+					Memory: util.StringPtr("1g"),
+				},
+			},
+			BatchSchedulerOptions: &v1beta2.BatchSchedulerConfiguration{
+				PriorityClassName: util.StringPtr("high"),
+			},
+		},
+	}
+}
+
+func newTestScheduler(t *testing.T, objects ...client.Object) *Scheduler {
+	scheme := runtime.NewScheme()
+	assert.Nil(t, clientgoscheme.AddToScheme(scheme))
+	assert.Nil(t, v1beta2.AddToScheme(scheme))
+	return &Scheduler{client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()}
+}
+
+// setWorkloadStatus sets the status of the Workload of the given application.
+func setWorkloadStatus(t *testing.T, s *Scheduler, app *v1beta2.SparkApplication, status workloadStatus) {
+	wl, err := s.getWorkload(app)
+	assert.Nil(t, err)
+	wl.Status = status
+	obj, err := toUnstructured(wl)
+	assert.Nil(t, err)
+	assert.Nil(t, s.client.Update(context.TODO(), obj))
+}
+
+func TestNewWorkload(t *testing.T) {
+	app := newTestApp()
+	wl, err := newWorkload(app)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "sparkapplication-spark-pi", wl.Name)
+	assert.Equal(t, "team-a", wl.Spec.QueueName)
+	assert.Equal(t, "high", wl.Spec.PriorityClassName)
+	assert.True(t, metav1.IsControlledBy(wl, app))
+	assert.Len(t, wl.Spec.PodSets, 2)
+
+	driver := wl.Spec.PodSets[0]
+	assert.Equal(t, driverPodSetName, driver.Name)
+	assert.Equal(t, int32(1), driver.Count)
+	assert.Equal(t, resource.MustParse("1"), driver.Template.Spec.Containers[0].Resources.Requests[corev1.ResourceCPU])
+
+	executor := wl.Spec.PodSets[1]
+	assert.Equal(t, executorPodSetName, executor.Name)
+	assert.Equal(t, int32(2), executor.Count)
+	assert.Equal(t, resource.MustParse("2"), executor.Template.Spec.Containers[0].Resources.Requests[corev1.ResourceCPU])
+}
+
+func TestNewWorkloadWithoutExecutors(t *testing.T) {
+	app := newTestApp()
+	app.Spec.Executor.Instances = util.Int32Ptr(0)
+	app.Labels = nil
+	app.Spec.BatchSchedulerOptions.Queue = util.StringPtr("team-b")
+	wl, err := newWorkload(app)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "team-b", wl.Spec.QueueName)
+	assert.Len(t, wl.Spec.PodSets, 1)
+	assert.Equal(t, driverPodSetName, wl.Spec.PodSets[0].Name)
+}
+
+func TestAdmit(t *testing.T) {
+	app := newTestApp()
+	s := newTestScheduler(t)
+
+	phase, message, err := s.Admit(app)
+	assert.Nil(t, err)
+	assert.Equal(t, v1beta2.BatchSchedulerAdmissionPending, phase)
+	assert.Equal(t, "waiting for workload sparkapplication-spark-pi to be admitted by Kueue in queue team-a", message)
+
+	setWorkloadStatus(t, s, app, workloadStatus{
+		Conditions: []metav1.Condition{{
+			Type:    workloadQuotaReserved,
+			Status:  metav1.ConditionFalse,
+			Reason:  "Pending",
+			Message: "insufficient quota for cpu",
+		}},
+	})
+	phase, message, err = s.Admit(app)
+	assert.Nil(t, err)
+	assert.Equal(t, v1beta2.BatchSchedulerAdmissionPending, phase)
+	assert.Equal(t, "waiting for workload sparkapplication-spark-pi to be admitted by Kueue in queue team-a: insufficient quota for cpu", message)
+
+	setWorkloadStatus(t, s, app, workloadStatus{
+		Conditions: []metav1.Condition{{Type: workloadAdmitted, Status: metav1.ConditionTrue, Reason: "Admitted"}},
+	})
+	phase, _, err = s.Admit(app)
+	assert.Nil(t, err)
+	assert.Equal(t, v1beta2.BatchSchedulerAdmissionAdmitted, phase)
+
+	evicted, _, err := s.Evicted(app)
+	assert.Nil(t, err)
+	assert.False(t, evicted)
+
+	setWorkloadStatus(t, s, app, workloadStatus{
+		Conditions: []metav1.Condition{{Type: workloadEvicted, Status: metav1.ConditionTrue, Reason: "Preempted", Message: "preempted by a higher priority workload"}},
+	})
+	evicted, message, err = s.Evicted(app)
+	assert.Nil(t, err)
+	assert.True(t, evicted)
+	assert.Equal(t, "workload sparkapplication-spark-pi was evicted by Kueue: preempted by a higher priority workload", message)
+
+	assert.Nil(t, s.Cleanup(app))
+	wl, err := s.getWorkload(app)
+	assert.Nil(t, err)
+	assert.Nil(t, wl)
+	assert.Nil(t, s.Cleanup(app))
+}
+
+func TestSchedule(t *testing.T) {
+	app := newTestApp()
+	flavor := &unstructured.Unstructured{}
+	flavor.SetGroupVersionKind(resourceFlavorGVK)
+	flavor.SetName("spot")
+	assert.Nil(t, unstructured.SetNestedStringMap(flavor.Object, map[string]string{"node-type": "spot"}, "spec", "nodeLabels"))
+	s := newTestScheduler(t, flavor)
+
+	_, _, err := s.Admit(app)
+	assert.Nil(t, err)
+	assert.NotNil(t, s.Schedule(app))
+
+	setWorkloadStatus(t, s, app, workloadStatus{
+		Admission: &admission{
+			ClusterQueue: "cluster-queue",
+			PodSetAssignments: []podSetAssignment{
+				{Name: driverPodSetName},
+				{Name: executorPodSetName, Flavors: map[corev1.ResourceName]string{corev1.ResourceCPU: "spot"}},
+			},
+		},
+		Conditions: []metav1.Condition{{Type: workloadAdmitted, Status: metav1.ConditionTrue, Reason: "Admitted"}},
+	})
+	assert.Nil(t, s.Schedule(app))
+	assert.Nil(t, app.Spec.Driver.NodeSelector)
+	assert.Equal(t, map[string]string{"node-type": "spot"}, app.Spec.Executor.NodeSelector)
+}
+
+func TestAdmitDeactivated(t *testing.T) {
+	app := newTestApp()
+	s := newTestScheduler(t)
+	_, _, err := s.Admit(app)
+	assert.Nil(t, err)
+
+	wl, err := s.getWorkload(app)
+	assert.Nil(t, err)
+	wl.Spec.Active = util.BoolPtr(false)
+	obj, err := toUnstructured(wl)
+	assert.Nil(t, err)
+	assert.Nil(t, s.client.Update(context.TODO(), obj))
+
+	phase, reason, err := s.Admit(app)
+	assert.Nil(t, err)
+	assert.Equal(t, v1beta2.BatchSchedulerAdmissionRejected, phase)
+	assert.Equal(t, "workload sparkapplication-spark-pi was deactivated", reason)
+}
+
+func TestAdmitDeactivatedRetry(t *testing.T) {
+	app := newTestApp()
+	s := newTestScheduler(t)
+	_, _, err := s.Admit(app)
+	assert.Nil(t, err)
+	setWorkloadStatus(t, s, app, workloadStatus{
+		Conditions: []metav1.Condition{{Type: workloadAdmitted, Status: metav1.ConditionFalse, Reason: "Deactivated"}},
+	})
+	wl, err := s.getWorkload(app)
+	assert.Nil(t, err)
+	wl.Spec.Active = util.BoolPtr(false)
+	obj, err := toUnstructured(wl)
+	assert.Nil(t, err)
+	assert.Nil(t, s.client.Update(context.TODO(), obj))
+
+	phase, _, err := s.Admit(app)
+	assert.Nil(t, err)
+	assert.Equal(t, v1beta2.BatchSchedulerAdmissionRejected, phase)
+
+	// The rejected application withdraws its admission, so that its retry gets a new Workload.
+	assert.Nil(t, s.Cleanup(app))
+	phase, _, err = s.Admit(app)
+	assert.Nil(t, err)
+	assert.Equal(t, v1beta2.BatchSchedulerAdmissionPending, phase)
+	wl, err = s.getWorkload(app)
+	assert.Nil(t, err)
+	assert.Nil(t, wl.Spec.Active)
+	assert.Empty(t, wl.Status.Conditions)
+
+	setWorkloadStatus(t, s, app, workloadStatus{
+		Conditions: []metav1.Condition{{Type: workloadAdmitted, Status: metav1.ConditionTrue, Reason: "Admitted"}},
+	})
+	phase, _, err = s.Admit(app)
+	assert.Nil(t, err)
+	assert.Equal(t, v1beta2.BatchSchedulerAdmissionAdmitted, phase)
+}
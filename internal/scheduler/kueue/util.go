@@ -0,0 +1,197 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kueue
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/resourceusage"
+)
+
+const (
+	// queueNameLabel is the label of the SparkApplications and Workloads telling the Kueue LocalQueue to use.
+	queueNameLabel = "kueue.x-k8s.io/queue-name"
+
+	// defaultQueueName is the name of the LocalQueue used if the application does not specify one.
+	defaultQueueName = "default"
+
+	driverPodSetName   = "driver"
+	executorPodSetName = "executor"
+
+	// Workload condition types.
+	workloadQuotaReserved = "QuotaReserved"
+	workloadAdmitted      = "Admitted"
+	workloadEvicted       = "Evicted"
+)
+
+var (
+	workloadGVK       = schema.GroupVersionKind{Group: "kueue.x-k8s.io", Version: "v1beta1", Kind: "Workload"}
+	resourceFlavorGVK = schema.GroupVersionKind{Group: "kueue.x-k8s.io", Version: "v1beta1", Kind: "ResourceFlavor"}
+)
+
+// The Kueue API types have been defined separately rather than imported to only include the fields in use.
+// https://github.com/kubernetes-sigs/kueue/blob/main/apis/kueue/v1beta1/workload_types.go
+type workload struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   workloadSpec   `json:"spec"`
+	Status workloadStatus `json:"status,omitempty"`
+}
+
+type workloadSpec struct {
+	PodSets             []podSet `json:"podSets"`
+	QueueName           string   `json:"queueName,omitempty"`
+	PriorityClassName   string   `json:"priorityClassName,omitempty"`
+	PriorityClassSource string   `json:"priorityClassSource,omitempty"`
+	Active              *bool    `json:"active,omitempty"`
+}
+
+type podSet struct {
+	Name     string                 `json:"name"`
+	Template corev1.PodTemplateSpec `json:"template"`
+	Count    int32                  `json:"count"`
+}
+
+type workloadStatus struct {
+	Admission  *admission         `json:"admission,omitempty"`
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+type admission struct {
+	ClusterQueue      string             `json:"clusterQueue"`
+	PodSetAssignments []podSetAssignment `json:"podSetAssignments"`
+}
+
+type podSetAssignment struct {
+	Name    string                         `json:"name"`
+	Flavors map[corev1.ResourceName]string `json:"flavors,omitempty"`
+}
+
+func getWorkloadName(app *v1beta2.SparkApplication) string {
+	return fmt.Sprintf("sparkapplication-%s", app.Name)
+}
+
+// getQueueName returns the name of the LocalQueue of the given application, which is given either by the
+// queue-name label or by the queue of its batch scheduler options.
+func getQueueName(app *v1beta2.SparkApplication) string {
+	if name, ok := app.Labels[queueNameLabel]; ok && name != "" {
+		return name
+	}
+	if app.Spec.BatchSchedulerOptions != nil && app.Spec.BatchSchedulerOptions.Queue != nil && *app.Spec.BatchSchedulerOptions.Queue != "" {
+		return *app.Spec.BatchSchedulerOptions.Queue
+	}
+	return defaultQueueName
+}
+
+// newWorkload returns the Workload of the given application, whose pod sets are sized by the resource
+// requests of the driver and of the executors of its gang.
+func newWorkload(app *v1beta2.SparkApplication) (*workload, error) {
+	driverResources, err := resourceusage.DriverResources(app)
+	if err != nil {
+		return nil, err
+	}
+
+	wl := &workload{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: workloadGVK.GroupVersion().String(),
+			Kind:       workloadGVK.Kind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getWorkloadName(app),
+			Namespace: app.Namespace,
+			Labels:    map[string]string{queueNameLabel: getQueueName(app)},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(app, v1beta2.SchemeGroupVersion.WithKind("SparkApplication")),
+			},
+		},
+		Spec: workloadSpec{
+			QueueName: getQueueName(app),
+			PodSets: []podSet{{
+				Name:     driverPodSetName,
+				Count:    1,
+				Template: newPodTemplate(app, &app.Spec.Driver.SparkPodSpec, common.SparkDriverContainerName, driverResources),
+			}},
+		},
+	}
+
+	// A count of zero is not valid for a pod set, so the executor pod set is left out if the gang has no executors.
+	if numExecutors := resourceusage.GangExecutorNumber(app); numExecutors > 0 {
+		executorResources, err := resourceusage.ExecutorResources(app)
+		if err != nil {
+			return nil, err
+		}
+		wl.Spec.PodSets = append(wl.Spec.PodSets, podSet{
+			Name:     executorPodSetName,
+			Count:    numExecutors,
+			Template: newPodTemplate(app, &app.Spec.Executor.SparkPodSpec, common.Spark3DefaultExecutorContainerName, executorResources),
+		})
+	}
+
+	if app.Spec.BatchSchedulerOptions != nil && app.Spec.BatchSchedulerOptions.PriorityClassName != nil {
+		wl.Spec.PriorityClassName = *app.Spec.BatchSchedulerOptions.PriorityClassName
+		wl.Spec.PriorityClassSource = "scheduling.k8s.io/priorityclass"
+	}
+	return wl, nil
+}
+
+// newPodTemplate returns the template of a pod set with the given resources and the scheduling
+// constraints of the given pod spec, which Kueue takes into account when assigning resource flavors.
+func newPodTemplate(app *v1beta2.SparkApplication, podSpec *v1beta2.SparkPodSpec, containerName string, resources resourceusage.Resources) corev1.PodTemplateSpec {
+	nodeSelector := make(map[string]string)
+	for key, value := range app.Spec.NodeSelector {
+		nodeSelector[key] = value
+	}
+	for key, value := range podSpec.NodeSelector {
+		nodeSelector[key] = value
+	}
+	return corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:      containerName,
+				Resources: corev1.ResourceRequirements{Requests: resources.Requests, Limits: resources.Limits},
+			}},
+			NodeSelector: nodeSelector,
+			Tolerations:  podSpec.Tolerations,
+			Affinity:     podSpec.Affinity,
+		},
+	}
+}
+
+func toUnstructured(wl *workload) (*unstructured.Unstructured, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(wl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert workload: %v", err)
+	}
+	return &unstructured.Unstructured{Object: obj}, nil
+}
+
+func fromUnstructured(obj *unstructured.Unstructured) (*workload, error) {
+	wl := &workload{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, wl); err != nil {
+		return nil, fmt.Errorf("failed to convert workload: %v", err)
+	}
+	return wl, nil
+}
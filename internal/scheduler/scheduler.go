@@ -30,10 +30,20 @@ var (
 type Interface interface {
 	Name() string
 	ShouldSchedule(app *v1beta2.SparkApplication) bool
+	// Admit requests the admission of the given SparkApplication before it is submitted. The application is only
+	// submitted once admitted, and the returned reason tells why it is pending or has been rejected otherwise.
+	Admit(app *v1beta2.SparkApplication) (v1beta2.BatchSchedulerAdmissionPhase, string, error)
 	Schedule(app *v1beta2.SparkApplication) error
 	Cleanup(app *v1beta2.SparkApplication) error
 }
 
+// Evictor is implemented by the batch schedulers that can evict admitted SparkApplications.
+type Evictor interface {
+	// Evicted tells whether the given admitted SparkApplication has been evicted, e.g. preempted by another one,
+	// and must be torn down and queued again. The returned message tells why.
+	Evicted(app *v1beta2.SparkApplication) (bool, string, error)
+}
+
 // Config defines the configuration of a batch scheduler.
 type Config interface{}
 
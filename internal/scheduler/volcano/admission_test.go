@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volcano
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	volcanofake "volcano.sh/apis/pkg/client/clientset/versioned/fake"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+func newAdmissionTestApp(queue string) *v1beta2.SparkApplication {
+	return &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "spark-pi",
+			Namespace: "default",
+		},
+		Spec: v1beta2.SparkApplicationSpec{
+			Type: v1beta2.SparkApplicationTypeScala,
+			Mode: v1beta2.DeployModeCluster,
+			Driver: v1beta2.DriverSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{Cores: util.Int32Ptr(1), Memory: util.StringPtr("512m")},
+			},
+			Executor: v1beta2.ExecutorSpec{
+				Instances:    util.Int32Ptr(1),
+				SparkPodSpec: v1beta2.SparkPodSpec{Cores: util.Int32Ptr(1), Memory: util.StringPtr("512m")},
+			},
+			BatchSchedulerOptions: &v1beta2.BatchSchedulerConfiguration{
+				Queue: util.StringPtr(queue),
+			},
+		},
+	}
+}
+
+func TestAdmit(t *testing.T) {
+	queue := &v1beta1.Queue{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Status:     v1beta1.QueueStatus{State: v1beta1.QueueStateOpen},
+	}
+	client := volcanofake.NewSimpleClientset(queue)
+	s := &Scheduler{volcanoClient: client}
+	app := newAdmissionTestApp("default")
+
+	phase, reason, err := s.Admit(app)
+	assert.Nil(t, err)
+	assert.Equal(t, v1beta2.BatchSchedulerAdmissionPending, phase)
+	assert.Equal(t, "waiting for PodGroup spark-spark-pi-pg to be enqueued by Volcano", reason)
+
+	podGroups := client.SchedulingV1beta1().PodGroups("default")
+	pg, err := podGroups.Get(context.TODO(), "spark-spark-pi-pg", metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, "default", pg.Spec.Queue)
+	pg.Status.Conditions = []v1beta1.PodGroupCondition{{
+		Type:    v1beta1.PodGroupUnschedulableType,
+		Status:  corev1.ConditionTrue,
+		Message: "queue resource quota insufficient",
+	}}
+	_, err = podGroups.Update(context.TODO(), pg, metav1.UpdateOptions{})
+	assert.Nil(t, err)
+
+	_, reason, err = s.Admit(app)
+	assert.Nil(t, err)
+	assert.Equal(t, "waiting for PodGroup spark-spark-pi-pg to be enqueued by Volcano: queue resource quota insufficient", reason)
+
+	pg.Status.Phase = v1beta1.PodGroupInqueue
+	_, err = podGroups.Update(context.TODO(), pg, metav1.UpdateOptions{})
+	assert.Nil(t, err)
+
+	phase, _, err = s.Admit(app)
+	assert.Nil(t, err)
+	assert.Equal(t, v1beta2.BatchSchedulerAdmissionAdmitted, phase)
+}
+
+func TestAdmitRejected(t *testing.T) {
+	queue := &v1beta1.Queue{
+		ObjectMeta: metav1.ObjectMeta{Name: "closed"},
+		Status:     v1beta1.QueueStatus{State: v1beta1.QueueStateClosed},
+	}
+	s := &Scheduler{volcanoClient: volcanofake.NewSimpleClientset(queue)}
+
+	phase, reason, err := s.Admit(newAdmissionTestApp("closed"))
+	assert.Nil(t, err)
+	assert.Equal(t, v1beta2.BatchSchedulerAdmissionRejected, phase)
+	assert.Equal(t, "queue closed is Closed", reason)
+
+	phase, reason, err = s.Admit(newAdmissionTestApp("missing"))
+	assert.Nil(t, err)
+	assert.Equal(t, v1beta2.BatchSchedulerAdmissionRejected, phase)
+	assert.Equal(t, "queue missing does not exist", reason)
+}
@@ -22,6 +22,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/rest"
@@ -32,7 +33,7 @@ import (
 	"github.com/kubeflow/spark-operator/api/v1beta2"
 	"github.com/kubeflow/spark-operator/internal/scheduler"
 	"github.com/kubeflow/spark-operator/pkg/common"
-	"github.com/kubeflow/spark-operator/pkg/util"
+	"github.com/kubeflow/spark-operator/pkg/resourceusage"
 )
 
 var (
@@ -106,6 +107,47 @@ func (s *Scheduler) ShouldSchedule(_ *v1beta2.SparkApplication) bool {
 	return true
 }
 
+// Admit implements batchscheduler.Interface. The PodGroup of the application is created before it is submitted,
+// and the application is admitted once Volcano has enqueued the PodGroup, i.e. once its queue has enough
+// resources for the minimum resources of the PodGroup.
+func (s *Scheduler) Admit(app *v1beta2.SparkApplication) (v1beta2.BatchSchedulerAdmissionPhase, string, error) {
+	if app.Spec.BatchSchedulerOptions != nil && app.Spec.BatchSchedulerOptions.Queue != nil {
+		name := *app.Spec.BatchSchedulerOptions.Queue
+		queue, err := s.volcanoClient.SchedulingV1beta1().Queues().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return v1beta2.BatchSchedulerAdmissionRejected, fmt.Sprintf("queue %s does not exist", name), nil
+			}
+			return "", "", fmt.Errorf("failed to get queue %s: %v", name, err)
+		}
+		if queue.Status.State != "" && queue.Status.State != v1beta1.QueueStateOpen {
+			return v1beta2.BatchSchedulerAdmissionRejected, fmt.Sprintf("queue %s is %s", name, queue.Status.State), nil
+		}
+	}
+
+	if err := s.syncPodGroup(app); err != nil {
+		return "", "", err
+	}
+	name := getPodGroupName(app)
+	pg, err := s.volcanoClient.SchedulingV1beta1().PodGroups(app.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get PodGroup %s: %v", name, err)
+	}
+
+	switch pg.Status.Phase {
+	case v1beta1.PodGroupInqueue, v1beta1.PodGroupRunning, v1beta1.PodGroupUnknown:
+		return v1beta2.BatchSchedulerAdmissionAdmitted, "", nil
+	}
+	reason := fmt.Sprintf("waiting for PodGroup %s to be enqueued by Volcano", name)
+	for _, condition := range pg.Status.Conditions {
+		if condition.Type == v1beta1.PodGroupUnschedulableType && condition.Status == corev1.ConditionTrue && condition.Message != "" {
+			reason = fmt.Sprintf("%s: %s", reason, condition.Message)
+			break
+		}
+	}
+	return v1beta2.BatchSchedulerAdmissionPending, reason, nil
+}
+
 // Schedule implements batchscheduler.Interface.
 func (s *Scheduler) Schedule(app *v1beta2.SparkApplication) error {
 	if app.ObjectMeta.Annotations == nil {
@@ -141,12 +183,7 @@ func (s *Scheduler) Cleanup(app *v1beta2.SparkApplication) error {
 func (s *Scheduler) syncPodGroupInClientMode(app *v1beta2.SparkApplication) error {
 	// We only care about the executor pods in client mode
 	if _, ok := app.Spec.Executor.Annotations[v1beta1.KubeGroupNameAnnotationKey]; !ok {
-		totalResource := util.GetExecutorRequestResource(app)
-
-		if app.Spec.BatchSchedulerOptions != nil && len(app.Spec.BatchSchedulerOptions.Resources) > 0 {
-			totalResource = app.Spec.BatchSchedulerOptions.Resources
-		}
-		if err := s.syncPodGroup(app, 1, totalResource); err == nil {
+		if err := s.syncPodGroup(app); err == nil {
 			app.Spec.Executor.Annotations[v1beta1.KubeGroupNameAnnotationKey] = getPodGroupName(app)
 		} else {
 			return err
@@ -160,12 +197,7 @@ func (s *Scheduler) syncPodGroupInClusterMode(app *v1beta2.SparkApplication) err
 	// In cluster mode, the initial size of PodGroup is set to 1 in order to schedule driver pod first.
 	if _, ok := app.Spec.Driver.Annotations[v1beta1.KubeGroupNameAnnotationKey]; !ok {
 		// Both driver and executor resource will be considered.
-		totalResource := util.SumResourceList([]corev1.ResourceList{util.GetDriverRequestResource(app), util.GetExecutorRequestResource(app)})
-		if app.Spec.BatchSchedulerOptions != nil && len(app.Spec.BatchSchedulerOptions.Resources) > 0 {
-			totalResource = app.Spec.BatchSchedulerOptions.Resources
-		}
-
-		if err := s.syncPodGroup(app, 1, totalResource); err != nil {
+		if err := s.syncPodGroup(app); err != nil {
 			return err
 		}
 		app.Spec.Driver.Annotations[v1beta1.KubeGroupNameAnnotationKey] = getPodGroupName(app)
@@ -174,11 +206,17 @@ func (s *Scheduler) syncPodGroupInClusterMode(app *v1beta2.SparkApplication) err
 	return nil
 }
 
-func (s *Scheduler) syncPodGroup(app *v1beta2.SparkApplication, size int32, minResource corev1.ResourceList) error {
+// syncPodGroup creates or updates the PodGroup of the given application, which is sized by its gang.
+func (s *Scheduler) syncPodGroup(app *v1beta2.SparkApplication) error {
 	var err error
 	var pg *v1beta1.PodGroup
 	name := getPodGroupName(app)
 	namespace := app.Namespace
+	size := resourceusage.GangMinMember(app)
+	minResource, err := getMinResources(app)
+	if err != nil {
+		return fmt.Errorf("failed to calculate minimum resources of PodGroup: %v", err)
+	}
 
 	if pg, err = s.volcanoClient.SchedulingV1beta1().PodGroups(namespace).Get(context.TODO(), name, metav1.GetOptions{}); err != nil {
 		if !errors.IsNotFound(err) {
@@ -214,8 +252,9 @@ func (s *Scheduler) syncPodGroup(app *v1beta2.SparkApplication, size int32, minR
 		}
 		_, err = s.volcanoClient.SchedulingV1beta1().PodGroups(namespace).Create(context.TODO(), &podGroup, metav1.CreateOptions{})
 	} else {
-		if pg.Spec.MinMember != size {
+		if pg.Spec.MinMember != size || pg.Spec.MinResources == nil || !equality.Semantic.DeepEqual(*pg.Spec.MinResources, minResource) {
 			pg.Spec.MinMember = size
+			pg.Spec.MinResources = &minResource
 			_, err = s.volcanoClient.SchedulingV1beta1().PodGroups(namespace).Update(context.TODO(), pg, metav1.UpdateOptions{})
 		}
 	}
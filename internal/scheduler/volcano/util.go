@@ -19,9 +19,21 @@ package volcano
 import (
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
+
 	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/resourceusage"
 )
 
 func getPodGroupName(app *v1beta2.SparkApplication) string {
 	return fmt.Sprintf("spark-%s-pg", app.Name)
 }
+
+// getMinResources returns the minimum resources of the PodGroup of the given application, which are the
+// resources of its gang unless specified by its batch scheduler options.
+func getMinResources(app *v1beta2.SparkApplication) (corev1.ResourceList, error) {
+	if app.Spec.BatchSchedulerOptions != nil && len(app.Spec.BatchSchedulerOptions.Resources) > 0 {
+		return app.Spec.BatchSchedulerOptions.Resources, nil
+	}
+	return resourceusage.GangMinResources(app)
+}
@@ -25,7 +25,7 @@ import (
 
 	"github.com/kubeflow/spark-operator/api/v1beta2"
 	"github.com/kubeflow/spark-operator/internal/scheduler"
-	"github.com/kubeflow/spark-operator/internal/scheduler/yunikorn/resourceusage"
+	"github.com/kubeflow/spark-operator/pkg/resourceusage"
 	"github.com/kubeflow/spark-operator/pkg/util"
 )
 
@@ -72,8 +72,13 @@ func (s *Scheduler) ShouldSchedule(_ *v1beta2.SparkApplication) bool {
 	return true
 }
 
+func (s *Scheduler) Admit(_ *v1beta2.SparkApplication) (v1beta2.BatchSchedulerAdmissionPhase, string, error) {
+	// Yunikorn queues the pods themselves once they are created, so the application is admitted directly
+	return v1beta2.BatchSchedulerAdmissionAdmitted, "", nil
+}
+
 func (s *Scheduler) Schedule(app *v1beta2.SparkApplication) error {
-	driverMinResources, err := resourceusage.DriverPodRequests(app)
+	driverResources, err := resourceusage.DriverResources(app)
 	if err != nil {
 		return fmt.Errorf("failed to calculate driver minResources: %w", err)
 	}
@@ -82,7 +87,7 @@ func (s *Scheduler) Schedule(app *v1beta2.SparkApplication) error {
 		{
 			Name:         driverTaskGroupName,
 			MinMember:    1,
-			MinResource:  driverMinResources,
+			MinResource:  toMinResource(driverResources.Requests),
 			NodeSelector: mergeNodeSelector(app.Spec.NodeSelector, app.Spec.Driver.NodeSelector),
 			Tolerations:  app.Spec.Driver.Tolerations,
 			Affinity:     app.Spec.Driver.Affinity,
@@ -91,17 +96,17 @@ func (s *Scheduler) Schedule(app *v1beta2.SparkApplication) error {
 	}
 
 	// A minMember of zero is not a valid config for a Yunikorn task group, so we should leave out
-	// the executor task group completely if the initial number of executors is zero
-	if numInitialExecutors := util.GetInitialExecutorNumber(app); numInitialExecutors > 0 {
-		executorMinResources, err := resourceusage.ExecutorPodRequests(app)
+	// the executor task group completely if the number of executors of the gang is zero
+	if numExecutors := resourceusage.GangExecutorNumber(app); numExecutors > 0 {
+		executorResources, err := resourceusage.ExecutorResources(app)
 		if err != nil {
 			return fmt.Errorf("failed to calculate executor minResources: %w", err)
 		}
 
 		taskGroups = append(taskGroups, taskGroup{
 			Name:         executorTaskGroupName,
-			MinMember:    numInitialExecutors,
-			MinResource:  executorMinResources,
+			MinMember:    numExecutors,
+			MinResource:  toMinResource(executorResources.Requests),
 			NodeSelector: mergeNodeSelector(app.Spec.NodeSelector, app.Spec.Executor.NodeSelector),
 			Tolerations:  app.Spec.Executor.Tolerations,
 			Affinity:     app.Spec.Executor.Affinity,
@@ -130,6 +135,15 @@ func (s *Scheduler) Cleanup(_ *v1beta2.SparkApplication) error {
 	return nil
 }
 
+// toMinResource converts a resource list to the format expected in the minResource of a task group.
+func toMinResource(resources corev1.ResourceList) map[string]string {
+	minResource := make(map[string]string, len(resources))
+	for name, quantity := range resources {
+		minResource[string(name)] = quantity.String()
+	}
+	return minResource
+}
+
 func addTaskGroupAnnotations(app *v1beta2.SparkApplication, taskGroups []taskGroup) error {
 	marshalledTaskGroups, err := json.Marshal(taskGroups)
 	if err != nil {
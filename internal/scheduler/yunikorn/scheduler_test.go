@@ -113,7 +113,7 @@ func TestSchedule(t *testing.T) {
 					Name:      "spark-driver",
 					MinMember: 1,
 					MinResource: map[string]string{
-						"cpu":    "2000m",   // CoreRequest takes precedence over Cores
+						"cpu":    "2",       // CoreRequest takes precedence over Cores
 						"memory": "10649Mi", // 1024Mi * 8 * 1.3 (manually specified overhead)
 					},
 				},
@@ -122,7 +122,7 @@ func TestSchedule(t *testing.T) {
 					MinMember: 8, // Max of instances, dynamic allocation min and initial
 					MinResource: map[string]string{
 						"cpu":    "8",
-						"memory": "67584Mi", // 1024Mi * 64 + 1024 * 2 (executor memory overhead takes precedence)
+						"memory": "66Gi", // 1024Mi * 64 + 1024 * 2 (executor memory overhead takes precedence)
 					},
 				},
 			},
@@ -18,11 +18,17 @@ package webhook
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
 )
 
 // NOTE: The 'path' attribute must follow a specific pattern and should not be modified directly here.
@@ -74,7 +80,107 @@ func (v *ScheduledSparkApplicationValidator) ValidateDelete(ctx context.Context,
 	return nil, nil
 }
 
-func (v *ScheduledSparkApplicationValidator) validate(_ *v1beta2.ScheduledSparkApplication) error {
-	// TODO: implement validate logic
+func (v *ScheduledSparkApplicationValidator) validate(app *v1beta2.ScheduledSparkApplication) error {
+	if err := v.validateTimeZone(app); err != nil {
+		return err
+	}
+	if err := v.validateTemplate(app); err != nil {
+		return err
+	}
+	if err := v.validateTasks(app); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateTasks checks that the tasks have unique names and that the graph of applications of a run does not
+// contain cycles.
+func (v *ScheduledSparkApplicationValidator) validateTasks(app *v1beta2.ScheduledSparkApplication) error {
+	dependencies := map[string][]string{app.Name: app.Spec.Template.DependsOn}
+	for _, task := range app.Spec.Tasks {
+		if task.Name == app.Name {
+			return fmt.Errorf("task must not have the name of the ScheduledSparkApplication: %s", task.Name)
+		}
+		if _, ok := dependencies[task.Name]; ok {
+			return fmt.Errorf("tasks have duplicate name: %s", task.Name)
+		}
+		// The names of the SparkApplications are used as label values, which are limited in length. The longest
+		// are those of the manual runs.
+		runName := util.GetScheduledManualRunName(app.Name, time.Now(), math.MaxInt32)
+		if name := util.GetScheduledTaskName(runName, task.Name); len(name) > validation.LabelValueMaxLength {
+			return fmt.Errorf("task name %s is too long, the names of its SparkApplications like %s exceed %d characters", task.Name, name, validation.LabelValueMaxLength)
+		}
+		dependencies[task.Name] = task.Template.DependsOn
+	}
+
+	// Depth-first search for cycles, where dependencies outside of the run are leaves.
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	states := make(map[string]int)
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch states[name] {
+		case visiting:
+			return fmt.Errorf("tasks have a dependency cycle through %s", name)
+		case visited:
+			return nil
+		}
+		states[name] = visiting
+		for _, upstream := range dependencies[name] {
+			if upstream == name {
+				return fmt.Errorf("task %s cannot depend on itself", name)
+			}
+			if _, ok := dependencies[upstream]; !ok {
+				continue
+			}
+			if err := visit(upstream); err != nil {
+				return err
+			}
+		}
+		states[name] = visited
+		return nil
+	}
+	for name := range dependencies {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateTemplate checks that the templates in the SparkApplication template can be rendered.
+func (v *ScheduledSparkApplicationValidator) validateTemplate(app *v1beta2.ScheduledSparkApplication) error {
+	now := time.Now()
+	vars := util.ScheduledRunVariables{
+		ScheduledTime:             now,
+		PreviousSuccessfulRunTime: now,
+		RunName:                   app.Name,
+		Namespace:                 app.Namespace,
+	}
+	if err := util.RenderSparkApplicationTemplate(app.Spec.Template.DeepCopy(), vars); err != nil {
+		return err
+	}
+	for _, task := range app.Spec.Tasks {
+		if err := util.RenderSparkApplicationTemplate(task.Template.DeepCopy(), vars); err != nil {
+			return fmt.Errorf("task %s: %v", task.Name, err)
+		}
+	}
+	return nil
+}
+
+func (v *ScheduledSparkApplicationValidator) validateTimeZone(app *v1beta2.ScheduledSparkApplication) error {
+	if app.Spec.TimeZone == nil {
+		return nil
+	}
+
+	if _, err := time.LoadLocation(*app.Spec.TimeZone); err != nil {
+		return fmt.Errorf("invalid time zone %q: %v", *app.Spec.TimeZone, err)
+	}
+	// The time zone must not be specified twice.
+	if strings.HasPrefix(app.Spec.Schedule, "TZ=") || strings.HasPrefix(app.Spec.Schedule, "CRON_TZ=") {
+		return fmt.Errorf("schedule must not specify a time zone with TZ or CRON_TZ when timeZone is set")
+	}
 	return nil
 }
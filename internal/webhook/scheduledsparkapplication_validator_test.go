@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+func TestValidateTimeZone(t *testing.T) {
+	testCases := []struct {
+		name     string
+		schedule string
+		timeZone *string
+		valid    bool
+	}{
+		{name: "no time zone", schedule: "0 2 * * *", valid: true},
+		{name: "valid time zone", schedule: "0 2 * * *", timeZone: util.StringPtr("America/New_York"), valid: true},
+		{name: "invalid time zone", schedule: "0 2 * * *", timeZone: util.StringPtr("America/Atlantis")},
+		{name: "time zone in schedule", schedule: "CRON_TZ=UTC 0 2 * * *", timeZone: util.StringPtr("UTC")},
+	}
+
+	validator := NewScheduledSparkApplicationValidator()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := &v1beta2.ScheduledSparkApplication{
+				Spec: v1beta2.ScheduledSparkApplicationSpec{
+					Schedule: tc.schedule,
+					TimeZone: tc.timeZone,
+				},
+			}
+			err := validator.validate(app)
+			if tc.valid && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+			if !tc.valid && err == nil {
+				t.Error("expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	validator := NewScheduledSparkApplicationValidator()
+	app := &v1beta2.ScheduledSparkApplication{
+		Spec: v1beta2.ScheduledSparkApplicationSpec{
+			Schedule: "0 2 * * *",
+			Template: v1beta2.SparkApplicationSpec{
+				Arguments: []string{"${{ .ScheduledTime.Format \"2006-01-02\" }}"},
+			},
+		},
+	}
+	if err := validator.validate(app); err != nil {
+		t.Errorf("expected valid template, got error: %v", err)
+	}
+	// The template is not rendered in place.
+	if app.Spec.Template.Arguments[0] != "${{ .ScheduledTime.Format \"2006-01-02\" }}" {
+		t.Errorf("expected template to be unchanged, got %q", app.Spec.Template.Arguments[0])
+	}
+
+	app.Spec.Template.Arguments = []string{"${{ .LogicalDate }}"}
+	if err := validator.validate(app); err == nil {
+		t.Error("expected error for template with unknown variable")
+	}
+}
+
+func TestValidateTasks(t *testing.T) {
+	newTask := func(name string, dependsOn ...string) v1beta2.ScheduledSparkApplicationTask {
+		return v1beta2.ScheduledSparkApplicationTask{
+			Name:     name,
+			Template: v1beta2.SparkApplicationSpec{DependsOn: dependsOn},
+		}
+	}
+	testCases := []struct {
+		name      string
+		dependsOn []string
+		tasks     []v1beta2.ScheduledSparkApplicationTask
+		valid     bool
+	}{
+		{name: "no tasks", valid: true},
+		{
+			name:  "graph",
+			tasks: []v1beta2.ScheduledSparkApplicationTask{newTask("transform", "etl"), newTask("report", "transform", "external")},
+			valid: true,
+		},
+		{name: "duplicate names", tasks: []v1beta2.ScheduledSparkApplicationTask{newTask("report"), newTask("report")}},
+		{name: "name of the scheduled application", tasks: []v1beta2.ScheduledSparkApplicationTask{newTask("etl")}},
+		{name: "self dependency", tasks: []v1beta2.ScheduledSparkApplicationTask{newTask("report", "report")}},
+		{name: "longest name", tasks: []v1beta2.ScheduledSparkApplicationTask{newTask(strings.Repeat("a", 27))}, valid: true},
+		{name: "name too long", tasks: []v1beta2.ScheduledSparkApplicationTask{newTask(strings.Repeat("a", 28))}},
+		{
+			name:      "cycle",
+			dependsOn: []string{"c"},
+			tasks:     []v1beta2.ScheduledSparkApplicationTask{newTask("a", "b"), newTask("b", "etl"), newTask("c", "a")},
+		},
+	}
+
+	validator := NewScheduledSparkApplicationValidator()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := &v1beta2.ScheduledSparkApplication{
+				ObjectMeta: metav1.ObjectMeta{Name: "etl"},
+				Spec: v1beta2.ScheduledSparkApplicationSpec{
+					Schedule: "0 2 * * *",
+					Template: v1beta2.SparkApplicationSpec{DependsOn: tc.dependsOn},
+					Tasks:    tc.tasks,
+				},
+			}
+			err := validator.validate(app)
+			if tc.valid && err != nil {
+				t.Errorf("expected valid tasks, got error: %v", err)
+			}
+			if !tc.valid && err == nil {
+				t.Error("expected error for invalid tasks")
+			}
+		})
+	}
+}
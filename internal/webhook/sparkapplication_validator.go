@@ -19,14 +19,19 @@ package webhook
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/resourceusage"
 	"github.com/kubeflow/spark-operator/pkg/util"
 )
 
@@ -101,7 +106,21 @@ func (v *SparkApplicationValidator) ValidateUpdate(ctx context.Context, oldObj r
 		}
 	}
 
-	return nil, nil
+	// Warn about the changes which will make the controller restart an application that has been submitted.
+	if state := newApp.Status.AppState.State; state != v1beta2.ApplicationStateNew && state != v1beta2.ApplicationStateSuspended {
+		for _, path := range util.GetRestartingSpecChanges(oldApp, newApp) {
+			warnings = append(warnings, fmt.Sprintf("changing %s restarts SparkApplication %s", path, newApp.Name))
+		}
+	}
+	if util.IsDriverRunning(newApp) && !equality.Semantic.DeepEqual(oldApp.Spec.Executor.Instances, newApp.Spec.Executor.Instances) {
+		if !util.IsLiveExecutorScaling(newApp) {
+			warnings = append(warnings, fmt.Sprintf("changing spec.executor.instances takes effect from the next attempt of SparkApplication %s", newApp.Name))
+		} else if limit := util.GetExecutorLimit(newApp); limit != *newApp.Spec.Executor.Instances {
+			warnings = append(warnings, fmt.Sprintf("SparkApplication %s is scaled to %d executors within its dynamic allocation bounds", newApp.Name, limit))
+		}
+	}
+
+	return warnings, nil
 }
 
 // ValidateDelete implements admission.CustomValidator.
@@ -114,7 +133,7 @@ func (v *SparkApplicationValidator) ValidateDelete(ctx context.Context, obj runt
 	return nil, nil
 }
 
-func (v *SparkApplicationValidator) validateSpec(_ context.Context, app *v1beta2.SparkApplication) error {
+func (v *SparkApplicationValidator) validateSpec(ctx context.Context, app *v1beta2.SparkApplication) error {
 	logger.V(1).Info("Validating SparkApplication spec", "name", app.Name, "namespace", app.Namespace, "state", util.GetApplicationState(app))
 
 	if err := v.validateSparkVersion(app); err != nil {
@@ -125,6 +144,14 @@ func (v *SparkApplicationValidator) validateSpec(_ context.Context, app *v1beta2
 		return fmt.Errorf("node selector cannot be defined at both SparkApplication and Driver/Executor")
 	}
 
+	if app.Spec.MainApplicationFile == nil && app.Spec.Server == nil {
+		return fmt.Errorf("mainApplicationFile must be specified unless the application is a server")
+	}
+
+	if err := v.validateServer(app); err != nil {
+		return err
+	}
+
 	servicePorts := make(map[int32]bool)
 	ingressURLFormats := make(map[string]bool)
 	for _, item := range app.Spec.DriverIngressOptions {
@@ -145,9 +172,85 @@ func (v *SparkApplicationValidator) validateSpec(_ context.Context, app *v1beta2
 		ingressURLFormats[item.IngressURLFormat] = true
 	}
 
+	if err := v.validateDependencies(ctx, app); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+func (v *SparkApplicationValidator) validateServer(app *v1beta2.SparkApplication) error {
+	server := app.Spec.Server
+	if server == nil {
+		return nil
+	}
+	if app.Spec.Type != v1beta2.SparkApplicationTypeJava && app.Spec.Type != v1beta2.SparkApplicationTypeScala {
+		return fmt.Errorf("server applications must be of type Java or Scala")
+	}
+	if app.Spec.Mode != "" && app.Spec.Mode != v1beta2.DeployModeCluster {
+		return fmt.Errorf("server applications must run in cluster mode")
+	}
+	if server.Ingress != nil {
+		if server.Type == v1beta2.ServerTypeThrift {
+			return fmt.Errorf("ingress is not supported for Thrift servers")
+		}
+		if server.Ingress.Host == "" {
+			return fmt.Errorf("server ingress has empty host")
+		}
+	}
+	return nil
+}
+
+func (v *SparkApplicationValidator) validateDependencies(ctx context.Context, app *v1beta2.SparkApplication) error {
+	dependencies := make(map[string]bool)
+	for _, name := range app.Spec.DependsOn {
+		if name == "" {
+			return fmt.Errorf("dependsOn has empty SparkApplication name")
+		}
+		if name == app.Name {
+			return fmt.Errorf("SparkApplication cannot depend on itself")
+		}
+		if dependencies[name] {
+			return fmt.Errorf("dependsOn has duplicate SparkApplication name: %s", name)
+		}
+		dependencies[name] = true
+	}
+	return v.validateDependencyCycles(ctx, app)
+}
+
+// validateDependencyCycles checks that the given SparkApplication does not depend on itself through the
+// SparkApplications it depends on, which would leave all of them waiting forever. Upstreams that do not exist
+// yet are leaves of the graph, as they are validated once they are created.
+func (v *SparkApplicationValidator) validateDependencyCycles(ctx context.Context, app *v1beta2.SparkApplication) error {
+	visited := map[string]bool{app.Name: true}
+	var visit func(path []string, dependsOn []string) error
+	visit = func(path []string, dependsOn []string) error {
+		for _, name := range dependsOn {
+			next := append(slices.Clone(path), name)
+			if name == app.Name {
+				return fmt.Errorf("dependsOn forms a cycle: %s", strings.Join(next, " -> "))
+			}
+			if visited[name] {
+				continue
+			}
+			visited[name] = true
+
+			upstream := &v1beta2.SparkApplication{}
+			if err := v.client.Get(ctx, types.NamespacedName{Namespace: app.Namespace, Name: name}, upstream); err != nil {
+				if errors.IsNotFound(err) {
+					continue
+				}
+				return fmt.Errorf("failed to get SparkApplication %s: %v", name, err)
+			}
+			if err := visit(next, upstream.Spec.DependsOn); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return visit([]string{app.Name}, app.Spec.DependsOn)
+}
+
 func (v *SparkApplicationValidator) validateSparkVersion(app *v1beta2.SparkApplication) error {
 	// The pod template feature requires Spark version 3.0.0 or higher.
 	if app.Spec.Driver.Template != nil || app.Spec.Executor.Template != nil {
@@ -161,10 +264,11 @@ func (v *SparkApplicationValidator) validateSparkVersion(app *v1beta2.SparkAppli
 func (v *SparkApplicationValidator) validateResourceUsage(ctx context.Context, app *v1beta2.SparkApplication) error {
 	logger.V(1).Info("Validating SparkApplication resource usage", "name", app.Name, "namespace", app.Namespace, "state", util.GetApplicationState(app))
 
-	requests, err := getResourceList(app)
+	usage, err := resourceusage.Calculate(app)
 	if err != nil {
-		return fmt.Errorf("failed to calculate resource quests: %v", err)
+		return fmt.Errorf("failed to calculate resource requests: %v", err)
 	}
+	requests := usage.ResourceQuotaList()
 
 	resourceQuotaList := &corev1.ResourceQuotaList{}
 	if err := v.client.List(ctx, resourceQuotaList, client.InNamespace(app.Namespace)); err != nil {
@@ -178,7 +282,7 @@ func (v *SparkApplicationValidator) validateResourceUsage(ctx context.Context, a
 			continue
 		}
 
-		if !validateResourceQuota(requests, resourceQuota) {
+		if !util.ValidateResourceQuota(requests, resourceQuota) {
 			return fmt.Errorf("failed to validate resource quota \"%s/%s\"", resourceQuota.Namespace, resourceQuota.Name)
 		}
 	}
@@ -0,0 +1,138 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+func TestValidateUpdateWarnings(t *testing.T) {
+	oldApp := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "spark-test", Namespace: "default"},
+		Spec: v1beta2.SparkApplicationSpec{
+			Type:                v1beta2.SparkApplicationTypeScala,
+			MainApplicationFile: util.StringPtr("local:///opt/spark/examples/jars/spark-examples.jar"),
+			Driver: v1beta2.DriverSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{Cores: util.Int32Ptr(1)},
+			},
+		},
+		Status: v1beta2.SparkApplicationStatus{
+			AppState: v1beta2.ApplicationState{State: v1beta2.ApplicationStateRunning},
+		},
+	}
+	validator := NewSparkApplicationValidator(nil, false)
+
+	newApp := oldApp.DeepCopy()
+	newApp.Spec.TimeToLiveSeconds = util.Int64Ptr(60)
+	newApp.Spec.Driver.Labels = map[string]string{"team": "data"}
+	warnings, err := validator.ValidateUpdate(context.TODO(), oldApp, newApp)
+	assert.Nil(t, err)
+	assert.Empty(t, warnings)
+
+	newApp.Spec.Driver.Cores = util.Int32Ptr(2)
+	warnings, err = validator.ValidateUpdate(context.TODO(), oldApp, newApp)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"changing spec.driver.cores restarts SparkApplication spark-test"}, []string(warnings))
+
+	// Applications which have not been submitted are not restarted.
+	oldApp.Status.AppState.State = v1beta2.ApplicationStateNew
+	newApp.Status.AppState.State = v1beta2.ApplicationStateNew
+	warnings, err = validator.ValidateUpdate(context.TODO(), oldApp, newApp)
+	assert.Nil(t, err)
+	assert.Empty(t, warnings)
+}
+
+func TestValidateUpdateScalingWarnings(t *testing.T) {
+	oldApp := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "spark-test", Namespace: "default"},
+		Spec: v1beta2.SparkApplicationSpec{
+			Type:                v1beta2.SparkApplicationTypeScala,
+			MainApplicationFile: util.StringPtr("local:///opt/spark/examples/jars/spark-examples.jar"),
+			Executor: v1beta2.ExecutorSpec{
+				Instances: util.Int32Ptr(2),
+			},
+		},
+		Status: v1beta2.SparkApplicationStatus{
+			AppState: v1beta2.ApplicationState{State: v1beta2.ApplicationStateRunning},
+		},
+	}
+	validator := NewSparkApplicationValidator(nil, false)
+
+	newApp := oldApp.DeepCopy()
+	newApp.Spec.Executor.Instances = util.Int32Ptr(4)
+	warnings, err := validator.ValidateUpdate(context.TODO(), oldApp, newApp)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"changing spec.executor.instances takes effect from the next attempt of SparkApplication spark-test"}, []string(warnings))
+
+	// The executors of a running driver with dynamic allocation are scaled live within its bounds.
+	newApp.Spec.DynamicAllocation = &v1beta2.DynamicAllocation{Enabled: true, MaxExecutors: util.Int32Ptr(5)}
+	oldApp.Spec.DynamicAllocation = newApp.Spec.DynamicAllocation
+	warnings, err = validator.ValidateUpdate(context.TODO(), oldApp, newApp)
+	assert.Nil(t, err)
+	assert.Empty(t, warnings)
+
+	newApp.Spec.Executor.Instances = util.Int32Ptr(8)
+	warnings, err = validator.ValidateUpdate(context.TODO(), oldApp, newApp)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"SparkApplication spark-test is scaled to 5 executors within its dynamic allocation bounds"}, []string(warnings))
+
+	newApp.Status.AppState.State = v1beta2.ApplicationStateSuspended
+	oldApp.Status.AppState.State = v1beta2.ApplicationStateSuspended
+	warnings, err = validator.ValidateUpdate(context.TODO(), oldApp, newApp)
+	assert.Nil(t, err)
+	assert.Empty(t, warnings)
+}
+
+func TestValidateDependencyCycles(t *testing.T) {
+	newApp := func(name string, dependsOn ...string) *v1beta2.SparkApplication {
+		return &v1beta2.SparkApplication{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: v1beta2.SparkApplicationSpec{
+				Type:                v1beta2.SparkApplicationTypeScala,
+				MainApplicationFile: util.StringPtr("local:///opt/spark/examples/jars/spark-examples.jar"),
+				DependsOn:           dependsOn,
+			},
+		}
+	}
+	scheme := runtime.NewScheme()
+	assert.Nil(t, v1beta2.AddToScheme(scheme))
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(newApp("extract"), newApp("transform", "extract", "report"), newApp("other", "extract")).
+		Build()
+	validator := NewSparkApplicationValidator(c, false)
+
+	// The upstreams form a graph without cycles, where missing upstreams are leaves.
+	_, err := validator.ValidateCreate(context.TODO(), newApp("load", "transform", "other", "missing"))
+	assert.Nil(t, err)
+
+	_, err = validator.ValidateCreate(context.TODO(), newApp("report", "other", "transform"))
+	assert.EqualError(t, err, "dependsOn forms a cycle: report -> transform -> report")
+
+	oldApp := newApp("extract")
+	_, err = validator.ValidateUpdate(context.TODO(), oldApp, newApp("extract", "other"))
+	assert.EqualError(t, err, "dependsOn forms a cycle: extract -> other -> extract")
+}
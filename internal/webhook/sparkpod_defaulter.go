@@ -21,11 +21,13 @@ import (
 	"fmt"
 	"strings"
 
+	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
@@ -88,6 +90,13 @@ func (d *SparkPodDefaulter) Default(ctx context.Context, obj runtime.Object) err
 		return fmt.Errorf("failed to get SparkApplication %s/%s: %v", namespace, appName, err)
 	}
 
+	if req, err := admission.RequestFromContext(ctx); err == nil && req.Operation == admissionv1.Create && util.IsExecutorPod(pod) {
+		if err := d.checkExecutorLimit(ctx, app); err != nil {
+			logger.Info("Denying Spark pod", "name", pod.Name, "namespace", namespace, "errorMessage", err.Error())
+			return err
+		}
+	}
+
 	logger.Info("Mutating Spark pod", "name", pod.Name, "namespace", namespace, "phase", pod.Status.Phase)
 	if err := mutateSparkPod(pod, app); err != nil {
 		logger.Info("Denying Spark pod", "name", pod.Name, "namespace", namespace, "errorMessage", err.Error())
@@ -97,6 +106,31 @@ func (d *SparkPodDefaulter) Default(ctx context.Context, obj runtime.Object) err
 	return nil
 }
 
+// checkExecutorLimit checks that a new executor of the given SparkApplication does not exceed the limit the
+// application was scaled to, if any. The driver of the application retries creating executors that are denied.
+func (d *SparkPodDefaulter) checkExecutorLimit(ctx context.Context, app *v1beta2.SparkApplication) error {
+	if app.Status.ExecutorLimit == nil {
+		return nil
+	}
+
+	labels := util.GetResourceLabels(app)
+	labels[common.LabelSparkRole] = common.SparkRoleExecutor
+	pods := &corev1.PodList{}
+	if err := d.client.List(ctx, pods, client.InNamespace(app.Namespace), client.MatchingLabels(labels)); err != nil {
+		return fmt.Errorf("failed to list executor pods of SparkApplication %s/%s: %v", app.Namespace, app.Name, err)
+	}
+	var active int32
+	for i := range pods.Items {
+		if util.IsPodActive(&pods.Items[i]) {
+			active++
+		}
+	}
+	if active >= *app.Status.ExecutorLimit {
+		return fmt.Errorf("SparkApplication %s/%s is scaled to %d executors", app.Namespace, app.Name, *app.Status.ExecutorLimit)
+	}
+	return nil
+}
+
 func (d *SparkPodDefaulter) isSparkJobNamespace(ns string) bool {
 	return d.sparkJobNamespaces[metav1.NamespaceAll] || d.sparkJobNamespaces[ns]
 }
@@ -113,6 +147,7 @@ func mutateSparkPod(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
 		addGeneralConfigMaps,
 		addVolumes,
 		addContainerPorts,
+		addServerReadinessProbe,
 		addHostNetwork,
 		addHostAliases,
 		addInitContainers,
@@ -372,6 +407,33 @@ func addContainerPorts(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
 	return nil
 }
 
+// addServerReadinessProbe exposes the server port on the driver container of server applications, and makes
+// the driver pod ready only once the server accepts connections.
+func addServerReadinessProbe(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
+	if app.Spec.Server == nil || !util.IsDriverPod(pod) {
+		return nil
+	}
+
+	port := util.GetServerPort(app)
+	portName := util.GetServerPortName(app)
+	if err := addContainerPort(pod, port, string(corev1.ProtocolTCP), portName); err != nil {
+		return fmt.Errorf("failed to expose server port %d: %v", port, err)
+	}
+
+	i := findContainer(pod)
+	if pod.Spec.Containers[i].ReadinessProbe != nil {
+		return nil
+	}
+	pod.Spec.Containers[i].ReadinessProbe = &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(port)},
+		},
+		PeriodSeconds:    10,
+		FailureThreshold: 3,
+	}
+	return nil
+}
+
 func addContainerPort(pod *corev1.Pod, port int32, protocol string, portName string) error {
 	i := findContainer(pod)
 	if i < 0 {
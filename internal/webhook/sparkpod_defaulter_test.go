@@ -17,16 +17,23 @@ limitations under the License.
 package webhook
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/kubeflow/spark-operator/api/v1beta2"
 	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
 )
 
 func TestPatchSparkPod_OwnerReference(t *testing.T) {
@@ -2086,3 +2093,95 @@ func TestPatchSparkPod_ShareProcessNamespace(t *testing.T) {
 		}
 	}
 }
+
+func TestPatchSparkPod_ServerReadinessProbe(t *testing.T) {
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-test",
+			UID:  "spark-test-1",
+		},
+		Spec: v1beta2.SparkApplicationSpec{
+			Server: &v1beta2.ServerSpec{Type: v1beta2.ServerTypeConnect},
+		},
+	}
+
+	driverPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-driver",
+			Labels: map[string]string{
+				common.LabelSparkRole:               common.SparkRoleDriver,
+				common.LabelLaunchedBySparkOperator: "true",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  common.SparkDriverContainerName,
+					Image: "spark-driver:latest",
+				},
+			},
+		},
+	}
+
+	modifiedDriverPod, err := getModifiedPod(driverPod, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	container := modifiedDriverPod.Spec.Containers[0]
+	assert.Len(t, container.Ports, 1)
+	assert.Equal(t, "spark-connect", container.Ports[0].Name)
+	assert.Equal(t, common.DefaultSparkConnectServerPort, container.Ports[0].ContainerPort)
+	assert.NotNil(t, container.ReadinessProbe)
+	assert.Equal(t, common.DefaultSparkConnectServerPort, container.ReadinessProbe.TCPSocket.Port.IntVal)
+}
+
+func TestSparkPodDefaulterExecutorLimit(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.Nil(t, clientgoscheme.AddToScheme(scheme))
+	assert.Nil(t, v1beta2.AddToScheme(scheme))
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "spark-test", Namespace: "default", UID: "spark-test-1"},
+		Status: v1beta2.SparkApplicationStatus{
+			SubmissionID:  "submission",
+			ExecutorLimit: util.Int32Ptr(2),
+		},
+	}
+	newExecutorPod := func(name string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					common.LabelSparkAppName:    app.Name,
+					common.LabelSubmissionID:    app.Status.SubmissionID,
+					common.LabelSparkRole:       common.SparkRoleExecutor,
+					common.LabelSparkExecutorID: name,
+				},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: common.SparkExecutorContainerName, Image: "spark-executor:latest"}},
+			},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(app, newExecutorPod("1", corev1.PodRunning), newExecutorPod("2", corev1.PodFailed)).
+		Build()
+	defaulter := NewSparkPodDefaulter(c, nil)
+	ctx := admission.NewContextWithRequest(context.TODO(), admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Create},
+	})
+
+	// The failed executor does not count towards the limit.
+	assert.Nil(t, defaulter.Default(ctx, newExecutorPod("3", corev1.PodPending)))
+	assert.Nil(t, c.Create(context.TODO(), newExecutorPod("3", corev1.PodPending)))
+	assert.EqualError(t, defaulter.Default(ctx, newExecutorPod("4", corev1.PodPending)), "SparkApplication default/spark-test is scaled to 2 executors")
+
+	// Executors are not limited without a limit, nor are updates of existing executors.
+	assert.Nil(t, defaulter.Default(context.TODO(), newExecutorPod("3", corev1.PodRunning)))
+	app.Status.ExecutorLimit = nil
+	assert.Nil(t, c.Update(context.TODO(), app))
+	assert.Nil(t, defaulter.Default(ctx, newExecutorPod("4", corev1.PodPending)))
+}
@@ -20,6 +20,34 @@ package common
 const (
 	EventSparkApplicationAdded = "SparkApplicationAdded"
 
+	EventSparkApplicationWaiting = "SparkApplicationWaiting"
+
+	EventSparkApplicationUpstreamCompleted = "SparkApplicationUpstreamCompleted"
+
+	EventSparkApplicationQueued = "SparkApplicationQueued"
+
+	EventSparkApplicationAdmitted = "SparkApplicationAdmitted"
+
+	EventSparkApplicationBatchSchedulerPending = "SparkApplicationBatchSchedulerPending"
+
+	EventSparkApplicationBatchSchedulerAdmitted = "SparkApplicationBatchSchedulerAdmitted"
+
+	EventSparkApplicationBatchSchedulerRejected = "SparkApplicationBatchSchedulerRejected"
+
+	EventSparkApplicationPreempted = "SparkApplicationPreempted"
+
+	EventSparkApplicationPreempting = "SparkApplicationPreempting"
+
+	EventSparkApplicationEvicted = "SparkApplicationEvicted"
+
+	EventSparkApplicationServerHealthy = "SparkApplicationServerHealthy"
+
+	EventSparkApplicationServerUnhealthy = "SparkApplicationServerUnhealthy"
+
+	EventSparkApplicationServerRestarting = "SparkApplicationServerRestarting"
+
+	EventSparkApplicationPendingSubmission = "SparkApplicationPendingSubmission"
+
 	EventSparkApplicationSubmitted = "SparkApplicationSubmitted"
 
 	EventSparkApplicationSubmissionFailed = "SparkApplicationSubmissionFailed"
@@ -28,7 +56,34 @@ const (
 
 	EventSparkApplicationFailed = "SparkApplicationFailed"
 
+	EventSparkApplicationSkipped = "SparkApplicationSkipped"
+
 	EventSparkApplicationPendingRerun = "SparkApplicationPendingRerun"
+
+	EventSparkApplicationRetryScheduled = "SparkApplicationRetryScheduled"
+
+	EventSparkApplicationDeadlineExceeded = "SparkApplicationDeadlineExceeded"
+
+	EventSparkApplicationStuckPending = "SparkApplicationStuckPending"
+
+	EventSparkApplicationSuspended = "SparkApplicationSuspended"
+
+	EventSparkApplicationSpecUpdated = "SparkApplicationSpecUpdated"
+
+	EventSparkApplicationExecutorsScaled = "SparkApplicationExecutorsScaled"
+
+	EventSparkApplicationExecutorScalingDeferred = "SparkApplicationExecutorScalingDeferred"
+)
+
+// ScheduledSparkApplication events
+const (
+	EventScheduledSparkApplicationMissedRuns = "ScheduledSparkApplicationMissedRuns"
+
+	EventScheduledSparkApplicationManualRunsRequested = "ScheduledSparkApplicationManualRunsRequested"
+
+	EventScheduledSparkApplicationInvalidRunRequest = "ScheduledSparkApplicationInvalidRunRequest"
+
+	EventScheduledSparkApplicationManualRunStarted = "ScheduledSparkApplicationManualRunStarted"
 )
 
 // Spark driver events
@@ -39,6 +39,22 @@ const (
 	MetricSparkApplicationStartLatencySecondsHistogram = "spark_application_start_latency_seconds_histogram"
 )
 
+// Spark application metric labels.
+const (
+	// MetricLabelFailureReason is the label of the failure metrics of Spark applications which tells the reason
+	// of the failures.
+	MetricLabelFailureReason = "failure_reason"
+)
+
+// Spark application submission metric names.
+const (
+	MetricSparkApplicationSubmissionQueueDepth = "spark_application_submission_queue_depth"
+
+	MetricSparkApplicationSubmissionQueueWaitSeconds = "spark_application_submission_queue_wait_seconds"
+
+	MetricSparkApplicationSubmissionLatencySeconds = "spark_application_submission_latency_seconds"
+)
+
 // Spark executor metric names.
 const (
 	MetricSparkExecutorRunningCount = "spark_executor_running_count"
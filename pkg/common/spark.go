@@ -38,6 +38,10 @@ const (
 
 	SparkExecutorInstances = "spark.executor.instances"
 
+	// SparkExecutorMaxNumFailures is the Spark configuration key for specifying the maximum number of executor
+	// failures before the application fails.
+	SparkExecutorMaxNumFailures = "spark.executor.maxNumFailures"
+
 	SparkExecutorEnvTemplate = "spark.executor.env.%s"
 
 	SparkExecutorCores = "spark.executor.cores"
@@ -49,6 +53,10 @@ const (
 	SparkUIProxyBase = "spark.ui.proxyBase"
 
 	SparkUIProxyRedirectURI = "spark.ui.proxyRedirectUri"
+
+	// SparkScheduledTime is the configuration property for the scheduled time, i.e. the logical date, of a run
+	// of a ScheduledSparkApplication.
+	SparkScheduledTime = "spark.sparkoperator.scheduledTime"
 )
 
 // Spark on Kubernetes properties.
@@ -304,6 +312,21 @@ const (
 	// LabelScheduledSparkAppName is the name of the label for the ScheduledSparkApplication object name.
 	LabelScheduledSparkAppName = LabelAnnotationPrefix + "scheduled-app-name"
 
+	// LabelScheduledRunName is the name of the label for the name of the run of a ScheduledSparkApplication a task
+	// belongs to.
+	LabelScheduledRunName = LabelAnnotationPrefix + "scheduled-run-name"
+
+	// LabelScheduledTime is the name of the label for the nominal schedule time of a run of a ScheduledSparkApplication.
+	LabelScheduledTime = LabelAnnotationPrefix + "scheduled-time"
+
+	// AnnotationTriggerRun is the annotation on a ScheduledSparkApplication that requests a run with the given
+	// scheduled time in RFC 3339 format.
+	AnnotationTriggerRun = LabelAnnotationPrefix + "trigger"
+
+	// AnnotationBackfill is the annotation on a ScheduledSparkApplication that requests runs for all the scheduled
+	// times within the given interval, formatted as "<start>/<end>" in RFC 3339 format.
+	AnnotationBackfill = LabelAnnotationPrefix + "backfill"
+
 	// LabelLaunchedBySparkOperator is a label on Spark pods launched through the Spark Operator.
 	LabelLaunchedBySparkOperator = LabelAnnotationPrefix + "launched-by-spark-operator"
 
@@ -341,6 +364,31 @@ const (
 	DefaultSparkWebUIPortName = "spark-driver-ui-port"
 )
 
+const (
+	// SparkConnectServerMainClass is the main class of the Spark Connect server.
+	SparkConnectServerMainClass = "org.apache.spark.sql.connect.service.SparkConnectServer"
+
+	// SparkThriftServerMainClass is the main class of the Thrift JDBC/ODBC server.
+	SparkThriftServerMainClass = "org.apache.spark.sql.hive.thriftserver.HiveThriftServer2"
+
+	// SparkInternalResource is the primary resource of applications whose main class is bundled with Spark.
+	SparkInternalResource = "spark-internal"
+
+	// SparkConnectGrpcBindingPort is the Spark configuration property of the port of the Spark Connect server.
+	SparkConnectGrpcBindingPort = "spark.connect.grpc.binding.port"
+
+	// SparkHiveServer2ThriftPort is the Spark configuration property of the port of the Thrift JDBC/ODBC server.
+	SparkHiveServer2ThriftPort = "spark.hive.server2.thrift.port"
+
+	DefaultSparkConnectServerPort int32 = 15002
+
+	DefaultSparkThriftServerPort int32 = 10000
+
+	// DefaultServerRestartIntervalSeconds is the interval in seconds between restarts of a server whose restart
+	// policy does not specify a retry interval.
+	DefaultServerRestartIntervalSeconds int64 = 10
+)
+
 // https://spark.apache.org/docs/latest/configuration.html
 const (
 	DefaultCPUMilliCores = 1000
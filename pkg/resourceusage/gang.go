@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceusage
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// GangExecutorNumber returns the number of executors scheduled together with the driver as a gang, which is
+// the initial number of executors, or the minimum number of executors of dynamic allocation if the gang policy
+// of the application is Minimum.
+func GangExecutorNumber(app *v1beta2.SparkApplication) int32 {
+	options := app.Spec.BatchSchedulerOptions
+	dynamicAllocation := app.Spec.DynamicAllocation
+	if options != nil && options.GangPolicy != nil && *options.GangPolicy == v1beta2.GangPolicyMinimum &&
+		dynamicAllocation != nil && dynamicAllocation.Enabled {
+		if dynamicAllocation.MinExecutors != nil {
+			return *dynamicAllocation.MinExecutors
+		}
+		return 0
+	}
+	return util.GetInitialExecutorNumber(app)
+}
+
+// GangMinMember returns the number of pods of the gang of the given application that must be scheduled at once.
+// In cluster mode, the executors are only created once the driver is running, so the driver is scheduled on
+// its own while the minimum resources of the gang reserve room for its executors. In client mode, all the pods
+// of the gang are executors created at once.
+func GangMinMember(app *v1beta2.SparkApplication) int32 {
+	if app.Spec.Mode == v1beta2.DeployModeClient {
+		return max(GangExecutorNumber(app), 1)
+	}
+	return 1
+}
+
+// GangMinResources returns the resources requested by the pods of the gang of the given application, including
+// the memory overhead of the driver and executors.
+func GangMinResources(app *v1beta2.SparkApplication) (corev1.ResourceList, error) {
+	var resourceLists []corev1.ResourceList
+
+	if app.Spec.Mode != v1beta2.DeployModeClient {
+		driver, err := DriverResources(app)
+		if err != nil {
+			return nil, err
+		}
+		resourceLists = append(resourceLists, driver.Requests)
+	}
+
+	if numExecutors := GangExecutorNumber(app); numExecutors > 0 {
+		executor, err := ExecutorResources(app)
+		if err != nil {
+			return nil, err
+		}
+		for i := int32(0); i < numExecutors; i++ {
+			resourceLists = append(resourceLists, executor.Requests)
+		}
+	}
+
+	return util.SumResourceList(resourceLists), nil
+}
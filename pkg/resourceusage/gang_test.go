@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceusage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+func newGangTestApp(mode v1beta2.DeployMode, gangPolicy v1beta2.GangPolicy) *v1beta2.SparkApplication {
+	return &v1beta2.SparkApplication{
+		Spec: v1beta2.SparkApplicationSpec{
+			Type: v1beta2.SparkApplicationTypeScala,
+			Mode: mode,
+			Driver: v1beta2.DriverSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{Cores: util.Int32Ptr(1), Memory: util.StringPtr("1g")},
+			},
+			Executor: v1beta2.ExecutorSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{Cores: util.Int32Ptr(2), Memory: util.StringPtr("2g")},
+			},
+			DynamicAllocation: &v1beta2.DynamicAllocation{
+				Enabled:          true,
+				InitialExecutors: util.Int32Ptr(4),
+				MinExecutors:     util.Int32Ptr(1),
+				MaxExecutors:     util.Int32Ptr(10),
+			},
+			BatchSchedulerOptions: &v1beta2.BatchSchedulerConfiguration{
+				GangPolicy: &gangPolicy,
+			},
+		},
+	}
+}
+
+func TestGangExecutorNumber(t *testing.T) {
+	app := newGangTestApp(v1beta2.DeployModeCluster, v1beta2.GangPolicyInitial)
+	assert.Equal(t, int32(4), GangExecutorNumber(app))
+
+	app = newGangTestApp(v1beta2.DeployModeCluster, v1beta2.GangPolicyMinimum)
+	assert.Equal(t, int32(1), GangExecutorNumber(app))
+
+	app.Spec.DynamicAllocation.MinExecutors = nil
+	assert.Equal(t, int32(0), GangExecutorNumber(app))
+
+	// The minimum executors only matter with dynamic allocation.
+	app.Spec.DynamicAllocation = nil
+	app.Spec.Executor.Instances = util.Int32Ptr(3)
+	assert.Equal(t, int32(3), GangExecutorNumber(app))
+}
+
+func TestGangMinMember(t *testing.T) {
+	assert.Equal(t, int32(1), GangMinMember(newGangTestApp(v1beta2.DeployModeCluster, v1beta2.GangPolicyInitial)))
+	assert.Equal(t, int32(4), GangMinMember(newGangTestApp(v1beta2.DeployModeClient, v1beta2.GangPolicyInitial)))
+
+	app := newGangTestApp(v1beta2.DeployModeClient, v1beta2.GangPolicyMinimum)
+	app.Spec.DynamicAllocation.MinExecutors = util.Int32Ptr(0)
+	assert.Equal(t, int32(1), GangMinMember(app))
+}
+
+func TestGangMinResources(t *testing.T) {
+	testCases := []struct {
+		name     string
+		app      *v1beta2.SparkApplication
+		expected corev1.ResourceList
+	}{
+		{
+			name: "driver and initial executors",
+			app:  newGangTestApp(v1beta2.DeployModeCluster, v1beta2.GangPolicyInitial),
+			expected: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("9"),
+				corev1.ResourceMemory: resource.MustParse("11136Mi"),
+			},
+		},
+		{
+			name: "driver and minimum executors",
+			app:  newGangTestApp(v1beta2.DeployModeCluster, v1beta2.GangPolicyMinimum),
+			expected: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("3"),
+				corev1.ResourceMemory: resource.MustParse("3840Mi"),
+			},
+		},
+		{
+			name: "minimum executors in client mode",
+			app:  newGangTestApp(v1beta2.DeployModeClient, v1beta2.GangPolicyMinimum),
+			expected: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("2432Mi"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := GangMinResources(tc.app)
+			assert.Nil(t, err)
+			for name, quantity := range tc.expected {
+				assert.Equal(t, 0, quantity.Cmp(actual[name]), "%s: expected %s, got %s", name, quantity.String(), actual.Name(name, resource.DecimalSI).String())
+			}
+		})
+	}
+}
@@ -27,6 +27,11 @@ func TestByteStringAsMb(t *testing.T) {
 		input    string
 		expected int
 	}{
+		{"1b", 1},
+		{"100k", 100 * 1024},
+		{"1gb", 1024 * 1024 * 1024},
+		{"10TB", 10 * 1024 * 1024 * 1024 * 1024},
+		{"10PB", 10 * 1024 * 1024 * 1024 * 1024 * 1024},
 		{"1k", 1024},
 		{"1m", 1024 * 1024},
 		{"1g", 1024 * 1024 * 1024},
@@ -36,7 +41,7 @@ func TestByteStringAsMb(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.input, func(t *testing.T) {
-			actual, err := byteStringAsBytes(tc.input)
+			actual, err := ParseJavaMemoryString(tc.input)
 			assert.Nil(t, err)
 			assert.Equal(t, int64(tc.expected), actual)
 		})
@@ -56,7 +61,7 @@ func TestByteStringAsMbInvalid(t *testing.T) {
 
 	for _, input := range invalidInputs {
 		t.Run(input, func(t *testing.T) {
-			_, err := byteStringAsBytes(input)
+			_, err := ParseJavaMemoryString(input)
 			assert.NotNil(t, err)
 		})
 	}
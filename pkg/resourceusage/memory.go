@@ -0,0 +1,187 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceusage
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+)
+
+var (
+	javaStringSuffixes = map[string]int64{
+		"b":  1,
+		"kb": 1 << 10,
+		"k":  1 << 10,
+		"mb": 1 << 20,
+		"m":  1 << 20,
+		"gb": 1 << 30,
+		"g":  1 << 30,
+		"tb": 1 << 40,
+		"t":  1 << 40,
+		"pb": 1 << 50,
+		"p":  1 << 50,
+	}
+
+	javaStringPattern = regexp.MustCompile(`^([0-9]+)([a-z]+)?$`)
+)
+
+// defaultMemory is the default memory of the driver and executors.
+const defaultMemory = "1g"
+
+// ParseJavaMemoryString parses a Java-style memory string such as 512m or 1g as a number of bytes.
+// Logic copied from https://github.com/apache/spark/blob/5264164a67df498b73facae207eda12ee133be7d/common/network-common/src/main/java/org/apache/spark/network/util/JavaUtils.java#L276
+func ParseJavaMemoryString(s string) (int64, error) {
+	matches := javaStringPattern.FindStringSubmatch(strings.ToLower(s))
+	if matches != nil {
+		value, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		if multiplier, present := javaStringSuffixes[matches[2]]; present {
+			return value * multiplier, nil
+		}
+	}
+	return 0, fmt.Errorf("could not parse string '%s' as a Java-style memory value. Examples: 100kb, 1mb, 1g", s)
+}
+
+// parseMemoryConf parses the value of a Spark memory configuration property as a number of bytes.
+// Values without a unit are interpreted as mebibytes.
+func parseMemoryConf(value string) (int64, error) {
+	if _, err := strconv.Atoi(value); err == nil {
+		value = value + "m"
+	}
+	return ParseJavaMemoryString(value)
+}
+
+func isJVMApp(app *v1beta2.SparkApplication) bool {
+	return app.Spec.Type == v1beta2.SparkApplicationTypeJava || app.Spec.Type == v1beta2.SparkApplicationTypeScala
+}
+
+func memoryOverheadFactor(app *v1beta2.SparkApplication, r role) (float64, error) {
+	value, ok := r.conf(app, "spark.%s.memoryOverheadFactor")
+	if !ok && app.Spec.MemoryOverheadFactor != nil {
+		value, ok = *app.Spec.MemoryOverheadFactor, true
+	}
+	if !ok {
+		value, ok = app.Spec.SparkConf[common.SparkKubernetesMemoryOverheadFactor]
+	}
+	if !ok {
+		if isJVMApp(app) {
+			return common.DefaultJVMMemoryOverheadFactor, nil
+		}
+		return common.DefaultNonJVMMemoryOverheadFactor, nil
+	}
+
+	factor, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse memory overhead factor %q: %w", value, err)
+	}
+	return factor, nil
+}
+
+// memoryRequest returns the memory request of the pods of the role, which is the sum of the memory, of the
+// memory overhead and, for executors, of the PySpark memory and of the off-heap memory.
+// https://github.com/apache/spark/blob/11b682cf5b7c5360a02410be288b7905eecc1d28/resource-managers/kubernetes/core/src/main/scala/org/apache/spark/deploy/k8s/features/BasicExecutorFeatureStep.scala#L121
+func memoryRequest(app *v1beta2.SparkApplication, r role) (resource.Quantity, error) {
+	memory := defaultMemory
+	if r.podSpec.Memory != nil {
+		memory = *r.podSpec.Memory
+	} else if conf, ok := r.conf(app, "spark.%s.memory"); ok {
+		memory = conf
+	}
+	memoryBytes, err := parseMemoryConf(memory)
+	if err != nil {
+		return resource.Quantity{}, fmt.Errorf("failed to parse memory: %w", err)
+	}
+
+	var overheadBytes int64
+	overhead, ok := r.conf(app, "spark.%s.memoryOverhead")
+	if r.podSpec.MemoryOverhead != nil {
+		overhead, ok = *r.podSpec.MemoryOverhead, true
+	}
+	if ok {
+		if overheadBytes, err = parseMemoryConf(overhead); err != nil {
+			return resource.Quantity{}, fmt.Errorf("failed to parse memory overhead: %w", err)
+		}
+	} else {
+		factor, err := memoryOverheadFactor(app, r)
+		if err != nil {
+			return resource.Quantity{}, err
+		}
+		overheadBytes = max(int64(float64(memoryBytes)*factor), common.MinMemoryOverhead)
+	}
+
+	totalBytes := memoryBytes + overheadBytes
+	if r.name == "executor" {
+		pysparkBytes, err := executorPySparkMemoryBytes(app)
+		if err != nil {
+			return resource.Quantity{}, err
+		}
+		offHeapBytes, err := offHeapMemoryBytes(app)
+		if err != nil {
+			return resource.Quantity{}, err
+		}
+		totalBytes += pysparkBytes + offHeapBytes
+	}
+
+	// Spark requests memory in whole mebibytes.
+	return resource.MustParse(bytesToMi(totalBytes)), nil
+}
+
+func executorPySparkMemoryBytes(app *v1beta2.SparkApplication) (int64, error) {
+	pysparkMemory, found := app.Spec.SparkConf["spark.executor.pyspark.memory"]
+	if app.Spec.Type != v1beta2.SparkApplicationTypePython || !found {
+		return 0, nil
+	}
+	pysparkMemoryBytes, err := parseMemoryConf(pysparkMemory)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse PySpark memory: %w", err)
+	}
+	return pysparkMemoryBytes, nil
+}
+
+func offHeapMemoryBytes(app *v1beta2.SparkApplication) (int64, error) {
+	offHeapMemoryEnabled, found := app.Spec.SparkConf["spark.memory.offHeap.enabled"]
+	if !found || !strings.EqualFold(offHeapMemoryEnabled, "true") {
+		return 0, nil
+	}
+	offHeapSize, found := app.Spec.SparkConf["spark.memory.offHeap.size"]
+	if !found {
+		return 0, nil
+	}
+	// Unlike the other memory properties, values without a unit are interpreted as bytes.
+	if _, err := strconv.Atoi(offHeapSize); err == nil {
+		offHeapSize = offHeapSize + "b"
+	}
+	offHeapBytes, err := ParseJavaMemoryString(offHeapSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse off-heap memory: %w", err)
+	}
+	return offHeapBytes, nil
+}
+
+func bytesToMi(b int64) string {
+	// this floors the value to the nearest mebibyte
+	return fmt.Sprintf("%dMi", b/1024/1024)
+}
@@ -0,0 +1,272 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceusage
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// Resources are the resource requests and limits of a pod.
+type Resources struct {
+	Requests corev1.ResourceList `json:"requests,omitempty"`
+	Limits   corev1.ResourceList `json:"limits,omitempty"`
+}
+
+// Usage is the resource usage of a SparkApplication.
+type Usage struct {
+	// Driver is the resources of the driver pod. It is nil in client mode, as the driver does not run in a pod.
+	Driver *Resources `json:"driver,omitempty"`
+	// Executor is the resources of a single executor pod.
+	Executor Resources `json:"executor"`
+	// Executors is the initial number of executors.
+	Executors int32 `json:"executors"`
+	// Total is the resources of the driver and of all the initial executors.
+	Total Resources `json:"total"`
+}
+
+// role describes the driver or the executors of a SparkApplication.
+type role struct {
+	// name is the name of the role in the Spark configuration properties, i.e. driver or executor.
+	name        string
+	podSpec     *v1beta2.SparkPodSpec
+	coreRequest *string
+}
+
+func driverRole(app *v1beta2.SparkApplication) role {
+	return role{
+		name:        "driver",
+		podSpec:     &app.Spec.Driver.SparkPodSpec,
+		coreRequest: app.Spec.Driver.CoreRequest,
+	}
+}
+
+func executorRole(app *v1beta2.SparkApplication) role {
+	return role{
+		name:        "executor",
+		podSpec:     &app.Spec.Executor.SparkPodSpec,
+		coreRequest: app.Spec.Executor.CoreRequest,
+	}
+}
+
+// conf returns the value of the Spark configuration property of the role formatted from the given key.
+func (r role) conf(app *v1beta2.SparkApplication, format string) (string, bool) {
+	value, ok := app.Spec.SparkConf[fmt.Sprintf(format, r.name)]
+	return value, ok
+}
+
+// Calculate returns the resource usage of the given SparkApplication.
+func Calculate(app *v1beta2.SparkApplication) (*Usage, error) {
+	usage := &Usage{
+		Executors: util.GetInitialExecutorNumber(app),
+	}
+
+	var requests, limits []corev1.ResourceList
+	if app.Spec.Mode != v1beta2.DeployModeClient {
+		driver, err := DriverResources(app)
+		if err != nil {
+			return nil, err
+		}
+		usage.Driver = &driver
+		requests = append(requests, driver.Requests)
+		limits = append(limits, driver.Limits)
+	}
+
+	executor, err := ExecutorResources(app)
+	if err != nil {
+		return nil, err
+	}
+	usage.Executor = executor
+	for i := int32(0); i < usage.Executors; i++ {
+		requests = append(requests, executor.Requests)
+		limits = append(limits, executor.Limits)
+	}
+
+	usage.Total = Resources{
+		Requests: util.SumResourceList(requests),
+		Limits:   util.SumResourceList(limits),
+	}
+	return usage, nil
+}
+
+// ResourceQuotaList returns the total resource usage keyed by the resource names used in ResourceQuotas.
+func (u *Usage) ResourceQuotaList() corev1.ResourceList {
+	resourceList := corev1.ResourceList{}
+	for name, quantity := range u.Total.Requests {
+		switch name {
+		case corev1.ResourceCPU, corev1.ResourceMemory, corev1.ResourceEphemeralStorage:
+			resourceList[name] = quantity
+		}
+		resourceList[corev1.ResourceName(corev1.DefaultResourceRequestsPrefix+string(name))] = quantity
+	}
+	for name, quantity := range u.Total.Limits {
+		switch name {
+		case corev1.ResourceCPU, corev1.ResourceMemory, corev1.ResourceEphemeralStorage:
+			resourceList[corev1.ResourceName("limits."+string(name))] = quantity
+		}
+	}
+	return resourceList
+}
+
+// DriverResources returns the resources of the driver pod of the given SparkApplication.
+func DriverResources(app *v1beta2.SparkApplication) (Resources, error) {
+	resources, err := podResources(app, driverRole(app))
+	if err != nil {
+		return Resources{}, fmt.Errorf("failed to calculate driver resources: %w", err)
+	}
+	return resources, nil
+}
+
+// ExecutorResources returns the resources of a single executor pod of the given SparkApplication.
+func ExecutorResources(app *v1beta2.SparkApplication) (Resources, error) {
+	resources, err := podResources(app, executorRole(app))
+	if err != nil {
+		return Resources{}, fmt.Errorf("failed to calculate executor resources: %w", err)
+	}
+	return resources, nil
+}
+
+func podResources(app *v1beta2.SparkApplication, r role) (Resources, error) {
+	resources := Resources{
+		Requests: corev1.ResourceList{},
+		Limits:   corev1.ResourceList{},
+	}
+
+	cpuRequest, err := cpuRequest(app, r)
+	if err != nil {
+		return resources, err
+	}
+	resources.Requests[corev1.ResourceCPU] = cpuRequest
+
+	cpuLimit, err := cpuLimit(app, r)
+	if err != nil {
+		return resources, err
+	}
+	if cpuLimit != nil {
+		resources.Limits[corev1.ResourceCPU] = *cpuLimit
+	}
+
+	// The memory limit of Spark pods is the same as their memory request.
+	memory, err := memoryRequest(app, r)
+	if err != nil {
+		return resources, err
+	}
+	resources.Requests[corev1.ResourceMemory] = memory
+	resources.Limits[corev1.ResourceMemory] = memory
+
+	// Extended resources cannot be overcommitted, so their requests are the same as their limits.
+	gpuName, gpuQuantity, err := gpu(app, r)
+	if err != nil {
+		return resources, err
+	}
+	if gpuName != "" {
+		resources.Requests[gpuName] = gpuQuantity
+		resources.Limits[gpuName] = gpuQuantity
+	}
+
+	// Spark does not set the ephemeral storage of the pods, which is kept from the pod template if any.
+	if container := templateContainer(app, r); container != nil {
+		if quantity, ok := container.Resources.Requests[corev1.ResourceEphemeralStorage]; ok {
+			resources.Requests[corev1.ResourceEphemeralStorage] = quantity
+		}
+		if quantity, ok := container.Resources.Limits[corev1.ResourceEphemeralStorage]; ok {
+			resources.Limits[corev1.ResourceEphemeralStorage] = quantity
+		}
+	}
+
+	return resources, nil
+}
+
+func cpuRequest(app *v1beta2.SparkApplication, r role) (resource.Quantity, error) {
+	// The core request takes precedence over the number of cores, while the core limit does not matter.
+	value := "1"
+	if r.coreRequest != nil {
+		value = *r.coreRequest
+	} else if conf, ok := r.conf(app, "spark.kubernetes.%s.request.cores"); ok {
+		value = conf
+	} else if r.podSpec.Cores != nil {
+		value = fmt.Sprintf("%d", *r.podSpec.Cores)
+	} else if conf, ok := r.conf(app, "spark.%s.cores"); ok {
+		value = conf
+	}
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return resource.Quantity{}, fmt.Errorf("failed to parse core request %q: %w", value, err)
+	}
+	return quantity, nil
+}
+
+func cpuLimit(app *v1beta2.SparkApplication, r role) (*resource.Quantity, error) {
+	var value string
+	if r.podSpec.CoreLimit != nil {
+		value = *r.podSpec.CoreLimit
+	} else if conf, ok := r.conf(app, "spark.kubernetes.%s.limit.cores"); ok {
+		value = conf
+	} else {
+		return nil, nil
+	}
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse core limit %q: %w", value, err)
+	}
+	return &quantity, nil
+}
+
+func gpu(app *v1beta2.SparkApplication, r role) (corev1.ResourceName, resource.Quantity, error) {
+	if r.podSpec.GPU != nil {
+		return corev1.ResourceName(r.podSpec.GPU.Name), *resource.NewQuantity(r.podSpec.GPU.Quantity, resource.DecimalSI), nil
+	}
+
+	// Spark requests the resources of a vendor under the name <vendor>/<resource>.
+	amount, ok := r.conf(app, "spark.%s.resource.gpu.amount")
+	if !ok {
+		return "", resource.Quantity{}, nil
+	}
+	vendor, ok := r.conf(app, "spark.%s.resource.gpu.vendor")
+	if !ok {
+		return "", resource.Quantity{}, nil
+	}
+	quantity, err := resource.ParseQuantity(amount)
+	if err != nil {
+		return "", resource.Quantity{}, fmt.Errorf("failed to parse GPU amount %q: %w", amount, err)
+	}
+	return corev1.ResourceName(vendor + "/gpu"), quantity, nil
+}
+
+// templateContainer returns the Spark container of the pod template of the role, if any.
+func templateContainer(app *v1beta2.SparkApplication, r role) *corev1.Container {
+	if r.podSpec.Template == nil || len(r.podSpec.Template.Spec.Containers) == 0 {
+		return nil
+	}
+	containers := r.podSpec.Template.Spec.Containers
+	// Spark uses the first container of the template unless told otherwise.
+	name, ok := r.conf(app, "spark.kubernetes.%s.podTemplateContainerName")
+	if !ok {
+		return &containers[0]
+	}
+	for i := range containers {
+		if containers[i].Name == name {
+			return &containers[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,487 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceusage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// assertResourceList asserts that the given resource list has the expected quantities and nothing else.
+func assertResourceList(t *testing.T, expected map[corev1.ResourceName]string, actual corev1.ResourceList) {
+	assert.Len(t, actual, len(expected))
+	for name, value := range expected {
+		quantity, ok := actual[name]
+		assert.True(t, ok, "missing %s", name)
+		assert.Equal(t, 0, quantity.Cmp(resource.MustParse(value)), "%s: expected %s, got %s", name, value, quantity.String())
+	}
+}
+
+func TestCpuRequest(t *testing.T) {
+	testCases := []struct {
+		cores       *int32
+		coreRequest *string
+		sparkConf   map[string]string
+		expected    string
+	}{
+		{nil, nil, nil, "1"},
+		{util.Int32Ptr(1), nil, nil, "1"},
+		{nil, util.StringPtr("1"), nil, "1"},
+		{util.Int32Ptr(1), util.StringPtr("500m"), nil, "500m"},
+		{nil, nil, map[string]string{"spark.driver.cores": "3"}, "3"},
+		{util.Int32Ptr(2), nil, map[string]string{"spark.kubernetes.driver.request.cores": "250m"}, "250m"},
+	}
+
+	for _, tc := range testCases {
+		app := &v1beta2.SparkApplication{
+			Spec: v1beta2.SparkApplicationSpec{
+				SparkConf: tc.sparkConf,
+				Driver: v1beta2.DriverSpec{
+					SparkPodSpec: v1beta2.SparkPodSpec{Cores: tc.cores},
+					CoreRequest:  tc.coreRequest,
+				},
+			},
+		}
+		actual, err := cpuRequest(app, driverRole(app))
+		assert.Nil(t, err)
+		assert.Equal(t, 0, actual.Cmp(resource.MustParse(tc.expected)))
+	}
+}
+
+func TestCpuRequestInvalid(t *testing.T) {
+	invalidInputs := []string{
+		"",
+		"asd",
+		"Random 500m",
+	}
+
+	for _, input := range invalidInputs {
+		app := &v1beta2.SparkApplication{
+			Spec: v1beta2.SparkApplicationSpec{
+				Driver: v1beta2.DriverSpec{CoreRequest: util.StringPtr(input)},
+			},
+		}
+		_, err := cpuRequest(app, driverRole(app))
+		assert.NotNil(t, err)
+	}
+}
+
+func TestDriverResources(t *testing.T) {
+	testCases := []struct {
+		name             string
+		app              *v1beta2.SparkApplication
+		expectedRequests map[corev1.ResourceName]string
+		expectedLimits   map[corev1.ResourceName]string
+	}{
+		{
+			name: "cores and memory with overhead",
+			app: &v1beta2.SparkApplication{
+				Spec: v1beta2.SparkApplicationSpec{
+					Type: v1beta2.SparkApplicationTypeScala,
+					Driver: v1beta2.DriverSpec{
+						SparkPodSpec: v1beta2.SparkPodSpec{
+							Cores:          util.Int32Ptr(1),
+							CoreLimit:      util.StringPtr("2"),
+							Memory:         util.StringPtr("1024m"),
+							MemoryOverhead: util.StringPtr("1024m"),
+						},
+					},
+				},
+			},
+			expectedRequests: map[corev1.ResourceName]string{corev1.ResourceCPU: "1", corev1.ResourceMemory: "2048Mi"},
+			expectedLimits:   map[corev1.ResourceName]string{corev1.ResourceCPU: "2", corev1.ResourceMemory: "2048Mi"},
+		},
+		{
+			name: "core limit is not used as request",
+			app: &v1beta2.SparkApplication{
+				Spec: v1beta2.SparkApplicationSpec{
+					Type: v1beta2.SparkApplicationTypeScala,
+					Driver: v1beta2.DriverSpec{
+						SparkPodSpec: v1beta2.SparkPodSpec{
+							CoreLimit: util.StringPtr("4"),
+						},
+					},
+				},
+			},
+			// 1g of default memory plus the minimum memory overhead.
+			expectedRequests: map[corev1.ResourceName]string{corev1.ResourceCPU: "1", corev1.ResourceMemory: "1408Mi"},
+			expectedLimits:   map[corev1.ResourceName]string{corev1.ResourceCPU: "4", corev1.ResourceMemory: "1408Mi"},
+		},
+		{
+			name: "Spark configuration properties and non-JVM overhead",
+			app: &v1beta2.SparkApplication{
+				Spec: v1beta2.SparkApplicationSpec{
+					Type: v1beta2.SparkApplicationTypePython,
+					SparkConf: map[string]string{
+						"spark.driver.cores":  "2",
+						"spark.driver.memory": "2048",
+					},
+				},
+			},
+			// 2048Mi * 1.4 non-JVM overhead
+			expectedRequests: map[corev1.ResourceName]string{corev1.ResourceCPU: "2", corev1.ResourceMemory: "2867Mi"},
+			expectedLimits:   map[corev1.ResourceName]string{corev1.ResourceMemory: "2867Mi"},
+		},
+		{
+			name: "GPU and ephemeral storage",
+			app: &v1beta2.SparkApplication{
+				Spec: v1beta2.SparkApplicationSpec{
+					Type: v1beta2.SparkApplicationTypeScala,
+					Driver: v1beta2.DriverSpec{
+						SparkPodSpec: v1beta2.SparkPodSpec{
+							Memory: util.StringPtr("4g"),
+							GPU:    &v1beta2.GPUSpec{Name: "nvidia.com/gpu", Quantity: 1},
+							Template: &corev1.PodTemplateSpec{
+								Spec: corev1.PodSpec{
+									Containers: []corev1.Container{{
+										Name: "spark",
+										Resources: corev1.ResourceRequirements{
+											Requests: corev1.ResourceList{corev1.ResourceEphemeralStorage: resource.MustParse("10Gi")},
+											Limits:   corev1.ResourceList{corev1.ResourceEphemeralStorage: resource.MustParse("20Gi")},
+										},
+									}},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedRequests: map[corev1.ResourceName]string{
+				corev1.ResourceCPU:              "1",
+				corev1.ResourceMemory:           "4505Mi", // 4096Mi * 1.1 JVM overhead
+				"nvidia.com/gpu":                "1",
+				corev1.ResourceEphemeralStorage: "10Gi",
+			},
+			expectedLimits: map[corev1.ResourceName]string{
+				corev1.ResourceMemory:           "4505Mi",
+				"nvidia.com/gpu":                "1",
+				corev1.ResourceEphemeralStorage: "20Gi",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := DriverResources(tc.app)
+			assert.Nil(t, err)
+			assertResourceList(t, tc.expectedRequests, actual.Requests)
+			assertResourceList(t, tc.expectedLimits, actual.Limits)
+		})
+	}
+}
+
+func TestExecutorResources(t *testing.T) {
+	testCases := []struct {
+		name     string
+		app      *v1beta2.SparkApplication
+		expected map[corev1.ResourceName]string
+	}{
+		{
+			name: "core request takes precedence over cores",
+			app: &v1beta2.SparkApplication{
+				Spec: v1beta2.SparkApplicationSpec{
+					Type: v1beta2.SparkApplicationTypeJava,
+					Executor: v1beta2.ExecutorSpec{
+						SparkPodSpec: v1beta2.SparkPodSpec{
+							Cores:          util.Int32Ptr(2),
+							Memory:         util.StringPtr("1024m"),
+							MemoryOverhead: util.StringPtr("1024m"),
+						},
+						CoreRequest: util.StringPtr("1"),
+					},
+				},
+			},
+			expected: map[corev1.ResourceName]string{corev1.ResourceCPU: "1", corev1.ResourceMemory: "2048Mi"},
+		},
+		{
+			name: "PySpark and off-heap memory",
+			app: &v1beta2.SparkApplication{
+				Spec: v1beta2.SparkApplicationSpec{
+					Type: v1beta2.SparkApplicationTypePython,
+					SparkConf: map[string]string{
+						"spark.executor.pyspark.memory": "500",
+						"spark.memory.offHeap.enabled":  "true",
+						"spark.memory.offHeap.size":     "400m",
+					},
+					MemoryOverheadFactor: util.StringPtr("0.1"),
+					Executor: v1beta2.ExecutorSpec{
+						SparkPodSpec: v1beta2.SparkPodSpec{
+							Memory: util.StringPtr("512m"),
+						},
+					},
+				},
+			},
+			// 512Mi + 384Mi min overhead + 500Mi PySpark memory + 400Mi off-heap memory
+			expected: map[corev1.ResourceName]string{corev1.ResourceCPU: "1", corev1.ResourceMemory: "1796Mi"},
+		},
+		{
+			name: "GPU from Spark configuration properties",
+			app: &v1beta2.SparkApplication{
+				Spec: v1beta2.SparkApplicationSpec{
+					Type: v1beta2.SparkApplicationTypeScala,
+					SparkConf: map[string]string{
+						"spark.executor.resource.gpu.amount": "2",
+						"spark.executor.resource.gpu.vendor": "amd.com",
+						"spark.executor.memoryOverhead":      "1g",
+					},
+				},
+			},
+			expected: map[corev1.ResourceName]string{corev1.ResourceCPU: "1", corev1.ResourceMemory: "2048Mi", "amd.com/gpu": "2"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := ExecutorResources(tc.app)
+			assert.Nil(t, err)
+			assertResourceList(t, tc.expected, actual.Requests)
+		})
+	}
+}
+
+func TestCalculate(t *testing.T) {
+	app := &v1beta2.SparkApplication{
+		Spec: v1beta2.SparkApplicationSpec{
+			Type: v1beta2.SparkApplicationTypeScala,
+			Driver: v1beta2.DriverSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{Cores: util.Int32Ptr(1), CoreLimit: util.StringPtr("2"), Memory: util.StringPtr("1g")},
+			},
+			Executor: v1beta2.ExecutorSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{Cores: util.Int32Ptr(2), Memory: util.StringPtr("2g")},
+				Instances:    util.Int32Ptr(3),
+			},
+		},
+	}
+
+	usage, err := Calculate(app)
+	assert.Nil(t, err)
+	assert.NotNil(t, usage.Driver)
+	assert.Equal(t, int32(3), usage.Executors)
+	// 1408Mi for the driver and 2432Mi for each executor.
+	assertResourceList(t, map[corev1.ResourceName]string{corev1.ResourceCPU: "7", corev1.ResourceMemory: "8704Mi"}, usage.Total.Requests)
+	assertResourceList(t, map[corev1.ResourceName]string{corev1.ResourceCPU: "2", corev1.ResourceMemory: "8704Mi"}, usage.Total.Limits)
+	assertResourceList(t, map[corev1.ResourceName]string{
+		corev1.ResourceCPU:            "7",
+		corev1.ResourceMemory:         "8704Mi",
+		corev1.ResourceRequestsCPU:    "7",
+		corev1.ResourceRequestsMemory: "8704Mi",
+		corev1.ResourceLimitsCPU:      "2",
+		corev1.ResourceLimitsMemory:   "8704Mi",
+	}, usage.ResourceQuotaList())
+
+	// The driver does not run in a pod in client mode.
+	app.Spec.Mode = v1beta2.DeployModeClient
+	usage, err = Calculate(app)
+	assert.Nil(t, err)
+	assert.Nil(t, usage.Driver)
+	assertResourceList(t, map[corev1.ResourceName]string{corev1.ResourceCPU: "6", corev1.ResourceMemory: "7296Mi"}, usage.Total.Requests)
+}
+
+func TestGetDriverResource(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		app      *v1beta2.SparkApplication
+		expected map[corev1.ResourceName]string
+	}{
+		{
+			Name: "Validate Core and memory",
+			app: &v1beta2.SparkApplication{
+				Spec: v1beta2.SparkApplicationSpec{
+					Driver: v1beta2.DriverSpec{
+						SparkPodSpec: v1beta2.SparkPodSpec{
+							Cores:          util.Int32Ptr(1),
+							CoreLimit:      util.StringPtr("2"),
+							Memory:         util.StringPtr("1024m"),
+							MemoryOverhead: util.StringPtr("1024m"),
+						},
+					},
+				},
+			},
+			expected: map[corev1.ResourceName]string{corev1.ResourceCPU: "1", corev1.ResourceMemory: "2048Mi"},
+		},
+		{
+			Name: "Validate CoreLimit and memory",
+			app: &v1beta2.SparkApplication{
+				Spec: v1beta2.SparkApplicationSpec{
+					Driver: v1beta2.DriverSpec{
+						SparkPodSpec: v1beta2.SparkPodSpec{
+							CoreLimit:      util.StringPtr("1"),
+							Memory:         util.StringPtr("1024m"),
+							MemoryOverhead: util.StringPtr("1024m"),
+						},
+					},
+				},
+			},
+			expected: map[corev1.ResourceName]string{corev1.ResourceCPU: "1", corev1.ResourceMemory: "2048Mi"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			actual, err := DriverResources(tc.app)
+			assert.Nil(t, err)
+			assertResourceList(t, tc.expected, actual.Requests)
+		})
+	}
+}
+
+func TestGetExecutorResource(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		app      *v1beta2.SparkApplication
+		expected map[corev1.ResourceName]string
+	}{
+		{
+			Name: "Validate Core and memory",
+			app: &v1beta2.SparkApplication{
+				Spec: v1beta2.SparkApplicationSpec{
+					Executor: v1beta2.ExecutorSpec{
+						SparkPodSpec: v1beta2.SparkPodSpec{
+							Cores:          util.Int32Ptr(1),
+							Memory:         util.StringPtr("1024m"),
+							MemoryOverhead: util.StringPtr("1024m"),
+						},
+						Instances: util.Int32Ptr(2),
+					},
+				},
+			},
+			expected: map[corev1.ResourceName]string{corev1.ResourceCPU: "2", corev1.ResourceMemory: "4096Mi"},
+		},
+		{
+			Name: "Validate CoreRequest and memory",
+			app: &v1beta2.SparkApplication{
+				Spec: v1beta2.SparkApplicationSpec{
+					Executor: v1beta2.ExecutorSpec{
+						SparkPodSpec: v1beta2.SparkPodSpec{
+							Cores:          util.Int32Ptr(2),
+							Memory:         util.StringPtr("1024m"),
+							MemoryOverhead: util.StringPtr("1024m"),
+						},
+						CoreRequest: util.StringPtr("1"),
+						Instances:   util.Int32Ptr(2),
+					},
+				},
+			},
+			expected: map[corev1.ResourceName]string{corev1.ResourceCPU: "2", corev1.ResourceMemory: "4096Mi"},
+		},
+		{
+			Name: "Validate CoreLimit and memory",
+			app: &v1beta2.SparkApplication{
+				Spec: v1beta2.SparkApplicationSpec{
+					Executor: v1beta2.ExecutorSpec{
+						SparkPodSpec: v1beta2.SparkPodSpec{
+							CoreLimit:      util.StringPtr("1"),
+							Memory:         util.StringPtr("1024m"),
+							MemoryOverhead: util.StringPtr("1024m"),
+						},
+						Instances: util.Int32Ptr(2),
+					},
+				},
+			},
+			expected: map[corev1.ResourceName]string{corev1.ResourceCPU: "2", corev1.ResourceMemory: "4096Mi"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			// The expected resources are those of all the executors, without the driver.
+			tc.app.Spec.Mode = v1beta2.DeployModeClient
+			usage, err := Calculate(tc.app)
+			assert.Nil(t, err)
+			assertResourceList(t, tc.expected, usage.Total.Requests)
+		})
+	}
+}
+
+func TestResourceQuotaList(t *testing.T) {
+	app := &v1beta2.SparkApplication{
+		Spec: v1beta2.SparkApplicationSpec{
+			Mode: v1beta2.DeployModeClient,
+			Executor: v1beta2.ExecutorSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{
+					Cores:          util.Int32Ptr(1),
+					CoreLimit:      util.StringPtr("2"),
+					Memory:         util.StringPtr("1024m"),
+					MemoryOverhead: util.StringPtr("1024m"),
+				},
+				Instances: util.Int32Ptr(2),
+			},
+		},
+	}
+
+	usage, err := Calculate(app)
+	assert.Nil(t, err)
+	// The memory overhead is counted in both the requests and limits charged to the ResourceQuota.
+	assertResourceList(t, map[corev1.ResourceName]string{
+		corev1.ResourceCPU:            "2",
+		corev1.ResourceMemory:         "4096Mi",
+		corev1.ResourceRequestsCPU:    "2",
+		corev1.ResourceRequestsMemory: "4096Mi",
+		corev1.ResourceLimitsCPU:      "4",
+		corev1.ResourceLimitsMemory:   "4096Mi",
+	}, usage.ResourceQuotaList())
+}
+
+func assertMemory(memoryString string, expectedBytes int64, t *testing.T) {
+	m, err := ParseJavaMemoryString(memoryString)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if m != expectedBytes {
+		t.Errorf("%s: expected %v bytes, got %v bytes", memoryString, expectedBytes, m)
+		return
+	}
+}
+
+func TestJavaMemoryString(t *testing.T) {
+	assertMemory("1b", 1, t)
+	assertMemory("100k", 100*1024, t)
+	assertMemory("1gb", 1024*1024*1024, t)
+	assertMemory("10TB", 10*1024*1024*1024*1024, t)
+	assertMemory("10PB", 10*1024*1024*1024*1024*1024, t)
+}
+
+func TestParseJavaMemoryStringAsMiB(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected int64
+	}{
+		{"512", 512},
+		{"2048k", 2},
+		{"512m", 512},
+		{"4g", 4096},
+		{"1T", 1024 * 1024},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, err := parseMemoryConf(tc.input)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, actual>>20)
+		})
+	}
+
+	_, err := parseMemoryConf("1.5g")
+	assert.NotNil(t, err)
+}
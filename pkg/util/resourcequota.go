@@ -35,3 +35,17 @@ func SumResourceList(lists []corev1.ResourceList) corev1.ResourceList {
 	}
 	return total
 }
+
+// ValidateResourceQuota checks whether the resource list will satisfy the resource quota.
+func ValidateResourceQuota(resourceList corev1.ResourceList, resourceQuota corev1.ResourceQuota) bool {
+	for key, quantity := range resourceList {
+		if _, ok := resourceQuota.Status.Hard[key]; !ok {
+			continue
+		}
+		quantity.Add(resourceQuota.Status.Used[key])
+		if quantity.Cmp(resourceQuota.Spec.Hard[key]) > 0 {
+			return false
+		}
+	}
+	return true
+}
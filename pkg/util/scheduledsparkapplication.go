@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+)
+
+// ScheduledRunVariables are the variables available to the templates in the SparkApplication template of a
+// ScheduledSparkApplication, e.g. "${{ .ScheduledTime.Format \"2006-01-02\" }}".
+type ScheduledRunVariables struct {
+	// ScheduledTime is the scheduled time of the run, i.e. its logical date.
+	ScheduledTime time.Time
+	// PreviousSuccessfulRunTime is the scheduled time of the previous successful run, or the zero time if there
+	// is none.
+	PreviousSuccessfulRunTime time.Time
+	// RunName is the name of the SparkApplication of the run.
+	RunName string
+	// Namespace is the namespace of the run.
+	Namespace string
+}
+
+const (
+	// scheduledRunTemplateLeftDelim and scheduledRunTemplateRightDelim delimit the actions of the templates in the
+	// SparkApplication template of a ScheduledSparkApplication. They differ from the default Go template delimiters
+	// so that Spark's own placeholders such as "{{APP_ID}}" and "{{EXECUTOR_ID}}" are left untouched.
+	scheduledRunTemplateLeftDelim  = "${{"
+	scheduledRunTemplateRightDelim = "}}"
+)
+
+// RenderSparkApplicationTemplate renders the Go templates in the arguments, the Spark and Hadoop configurations
+// and the environment variables of the given SparkApplication spec in place with the given variables. Only the
+// strings containing "${{" are considered templates, and their actions are delimited by "${{" and "}}".
+func RenderSparkApplicationTemplate(spec *v1beta2.SparkApplicationSpec, vars ScheduledRunVariables) error {
+	render := func(field string, s *string) error {
+		if !strings.Contains(*s, scheduledRunTemplateLeftDelim) {
+			return nil
+		}
+		tmpl, err := template.New(field).
+			Delims(scheduledRunTemplateLeftDelim, scheduledRunTemplateRightDelim).
+			Option("missingkey=error").
+			Parse(*s)
+		if err != nil {
+			return fmt.Errorf("invalid template in %s: %v", field, err)
+		}
+		var b strings.Builder
+		if err := tmpl.Execute(&b, vars); err != nil {
+			return fmt.Errorf("failed to render template in %s: %v", field, err)
+		}
+		*s = b.String()
+		return nil
+	}
+	renderMap := func(field string, m map[string]string) error {
+		for key, value := range m {
+			if err := render(fmt.Sprintf("%s[%s]", field, key), &value); err != nil {
+				return err
+			}
+			m[key] = value
+		}
+		return nil
+	}
+	renderPod := func(field string, podSpec *v1beta2.SparkPodSpec) error {
+		for i := range podSpec.Env {
+			if err := render(fmt.Sprintf("%s.env[%s]", field, podSpec.Env[i].Name), &podSpec.Env[i].Value); err != nil {
+				return err
+			}
+		}
+		return renderMap(field+".envVars", podSpec.EnvVars)
+	}
+
+	for i := range spec.Arguments {
+		if err := render(fmt.Sprintf("arguments[%d]", i), &spec.Arguments[i]); err != nil {
+			return err
+		}
+	}
+	if err := renderMap("sparkConf", spec.SparkConf); err != nil {
+		return err
+	}
+	if err := renderMap("hadoopConf", spec.HadoopConf); err != nil {
+		return err
+	}
+	if err := renderPod("driver", &spec.Driver.SparkPodSpec); err != nil {
+		return err
+	}
+	return renderPod("executor", &spec.Executor.SparkPodSpec)
+}
+
+// GetScheduledRunName returns the name of the SparkApplication of the run of the given ScheduledSparkApplication
+// scheduled at the given time.
+func GetScheduledRunName(scheduledAppName string, t time.Time) string {
+	return fmt.Sprintf("%s-%d", scheduledAppName, t.UnixNano())
+}
+
+// GetScheduledManualRunName returns the name of the SparkApplication of the n-th manual run of the given
+// ScheduledSparkApplication scheduled at the given time, which differs from the name of the run started by the
+// schedule at the same time.
+func GetScheduledManualRunName(scheduledAppName string, t time.Time, n int32) string {
+	return fmt.Sprintf("%s-m%d", GetScheduledRunName(scheduledAppName, t), n)
+}
+
+// GetScheduledTaskName returns the name of the SparkApplication of the given task of the given run.
+func GetScheduledTaskName(runName string, taskName string) string {
+	return fmt.Sprintf("%s-%s", runName, taskName)
+}
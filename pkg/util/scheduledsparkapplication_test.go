@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+func TestRenderSparkApplicationTemplate(t *testing.T) {
+	spec := &v1beta2.SparkApplicationSpec{
+		Arguments: []string{
+			"--date=${{ .ScheduledTime.Format \"2006-01-02\" }}",
+			"--since=${{ .PreviousSuccessfulRunTime.Format \"2006-01-02T15:04:05Z07:00\" }}",
+			"--verbose",
+		},
+		SparkConf:  map[string]string{"spark.app.name": "${{ .RunName }}"},
+		HadoopConf: map[string]string{"fs.defaultFS": "hdfs://${{ .Namespace }}"},
+		Driver: v1beta2.DriverSpec{
+			SparkPodSpec: v1beta2.SparkPodSpec{
+				Env: []corev1.EnvVar{{Name: "HOUR", Value: "${{ .ScheduledTime.Hour }}"}},
+			},
+		},
+		Executor: v1beta2.ExecutorSpec{
+			SparkPodSpec: v1beta2.SparkPodSpec{
+				EnvVars: map[string]string{"RUN": "${{ .RunName }}"},
+			},
+		},
+	}
+	vars := util.ScheduledRunVariables{
+		ScheduledTime:             time.Date(2024, time.March, 1, 2, 0, 0, 0, time.UTC),
+		PreviousSuccessfulRunTime: time.Date(2024, time.February, 29, 2, 0, 0, 0, time.UTC),
+		RunName:                   "etl-1709258400000000000",
+		Namespace:                 "spark",
+	}
+	assert.Nil(t, util.RenderSparkApplicationTemplate(spec, vars))
+	assert.Equal(t, []string{"--date=2024-03-01", "--since=2024-02-29T02:00:00Z", "--verbose"}, spec.Arguments)
+	assert.Equal(t, "etl-1709258400000000000", spec.SparkConf["spark.app.name"])
+	assert.Equal(t, "hdfs://spark", spec.HadoopConf["fs.defaultFS"])
+	assert.Equal(t, "2", spec.Driver.Env[0].Value)
+	assert.Equal(t, "etl-1709258400000000000", spec.Executor.EnvVars["RUN"])
+}
+
+func TestRenderSparkApplicationTemplateInvalid(t *testing.T) {
+	vars := util.ScheduledRunVariables{ScheduledTime: time.Now()}
+	for _, argument := range []string{"${{ .ScheduledTime", "${{ .Unknown }}"} {
+		spec := &v1beta2.SparkApplicationSpec{Arguments: []string{argument}}
+		assert.NotNil(t, util.RenderSparkApplicationTemplate(spec, vars), argument)
+	}
+}
+
+func TestRenderSparkApplicationTemplateSparkPlaceholders(t *testing.T) {
+	spec := &v1beta2.SparkApplicationSpec{
+		Arguments: []string{"--app-id={{APP_ID}}", "--date=${{ .ScheduledTime.Format \"2006-01-02\" }}/{{APP_ID}}"},
+		SparkConf: map[string]string{
+			"spark.executor.extraJavaOptions": "-Dlog.file=/tmp/{{APP_ID}}-{{EXECUTOR_ID}}.log",
+		},
+	}
+	vars := util.ScheduledRunVariables{ScheduledTime: time.Date(2024, time.March, 1, 2, 0, 0, 0, time.UTC)}
+	assert.Nil(t, util.RenderSparkApplicationTemplate(spec, vars))
+	assert.Equal(t, []string{"--app-id={{APP_ID}}", "--date=2024-03-01/{{APP_ID}}"}, spec.Arguments)
+	assert.Equal(t, "-Dlog.file=/tmp/{{APP_ID}}-{{EXECUTOR_ID}}.log", spec.SparkConf["spark.executor.extraJavaOptions"])
+}
@@ -19,14 +19,19 @@ package util
 import (
 	"crypto/md5"
 	"fmt"
+	"math"
+	"math/rand/v2"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
-	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/kubeflow/spark-operator/api/v1beta2"
 	"github.com/kubeflow/spark-operator/pkg/common"
@@ -55,7 +60,13 @@ func GetApplicationState(app *v1beta2.SparkApplication) v1beta2.ApplicationState
 // IsTerminated returns whether the given SparkApplication is terminated.
 func IsTerminated(app *v1beta2.SparkApplication) bool {
 	return app.Status.AppState.State == v1beta2.ApplicationStateCompleted ||
-		app.Status.AppState.State == v1beta2.ApplicationStateFailed
+		app.Status.AppState.State == v1beta2.ApplicationStateFailed ||
+		app.Status.AppState.State == v1beta2.ApplicationStateSkipped
+}
+
+// IsSuspended returns whether the given SparkApplication is requested to be suspended.
+func IsSuspended(app *v1beta2.SparkApplication) bool {
+	return app.Spec.Suspend != nil && *app.Spec.Suspend
 }
 
 // IsExpired returns whether the given SparkApplication is expired.
@@ -80,6 +91,33 @@ func IsDriverRunning(app *v1beta2.SparkApplication) bool {
 }
 
 func ShouldRetry(app *v1beta2.SparkApplication) bool {
+	// Runs which exceeded their deadline are only retried if the restart policy asks for it.
+	if app.Status.AppState.State == v1beta2.ApplicationStateFailing &&
+		app.Status.AppState.Reason == v1beta2.ApplicationStateReasonDeadlineExceeded &&
+		(app.Spec.RestartPolicy.RetryOnDeadlineExceeded == nil || !*app.Spec.RestartPolicy.RetryOnDeadlineExceeded) &&
+		!slices.Contains(app.Spec.RestartPolicy.RetryOnFailureReasons, v1beta2.FailureReasonDeadlineExceeded) {
+		return false
+	}
+
+	// Servers are long-running and are always restarted, whatever their restart policy.
+	if app.Spec.Server != nil {
+		switch app.Status.AppState.State {
+		case v1beta2.ApplicationStateSucceeding, v1beta2.ApplicationStateFailing, v1beta2.ApplicationStateFailedSubmission:
+			return true
+		}
+		return false
+	}
+
+	// Failures are only retried for the selected reasons, if any.
+	if reasons := app.Spec.RestartPolicy.RetryOnFailureReasons; len(reasons) > 0 {
+		switch app.Status.AppState.State {
+		case v1beta2.ApplicationStateFailing, v1beta2.ApplicationStateFailedSubmission:
+			if !slices.Contains(reasons, GetFailureReason(app)) {
+				return false
+			}
+		}
+	}
+
 	switch app.Status.AppState.State {
 	case v1beta2.ApplicationStateSucceeding:
 		return app.Spec.RestartPolicy.Type == v1beta2.RestartPolicyAlways
@@ -105,25 +143,99 @@ func ShouldRetry(app *v1beta2.SparkApplication) bool {
 	return false
 }
 
+// TimeUntilNextRetryDue returns the time left until the given failed SparkApplication is due to be retried,
+// which is negative if it is overdue. The next retry time recorded in the status takes precedence.
 func TimeUntilNextRetryDue(app *v1beta2.SparkApplication) (time.Duration, error) {
+	if !app.Status.NextRetryTime.IsZero() {
+		return time.Until(app.Status.NextRetryTime.Time), nil
+	}
+
+	nextRetryTime, err := GetNextRetryTime(app)
+	if err != nil {
+		return -1, err
+	}
+	return time.Until(nextRetryTime), nil
+}
+
+// GetNextRetryTime returns the time at which the given failed SparkApplication is due to be retried. Without a
+// backoff policy, the interval since the last submission attempt grows linearly with the number of attempts.
+// As the backoff policy may add a random jitter, the result may differ between calls.
+func GetNextRetryTime(app *v1beta2.SparkApplication) (time.Time, error) {
 	var retryInterval *int64
+	var lastFailureTime metav1.Time
 	switch app.Status.AppState.State {
 	case v1beta2.ApplicationStateFailedSubmission:
 		retryInterval = app.Spec.RestartPolicy.OnSubmissionFailureRetryInterval
+		lastFailureTime = app.Status.LastSubmissionAttemptTime
 	case v1beta2.ApplicationStateFailing:
 		retryInterval = app.Spec.RestartPolicy.OnFailureRetryInterval
+		lastFailureTime = app.Status.TerminationTime
+	}
+
+	if retryInterval == nil && app.Spec.Server != nil {
+		defaultInterval := common.DefaultServerRestartIntervalSeconds
+		retryInterval = &defaultInterval
+	}
+
+	if backoff := app.Spec.RestartPolicy.Backoff; backoff != nil {
+		if lastFailureTime.IsZero() {
+			lastFailureTime = app.Status.LastSubmissionAttemptTime
+		}
+		return getBackoffRetryTime(app, backoff, retryInterval, lastFailureTime)
 	}
 
 	attemptsDone := app.Status.SubmissionAttempts
 	lastAttemptTime := app.Status.LastSubmissionAttemptTime
 	if retryInterval == nil || lastAttemptTime.IsZero() || attemptsDone <= 0 {
-		return -1, fmt.Errorf("invalid retry interval (%v), last attempt time (%v) or attemptsDone (%v)", retryInterval, lastAttemptTime, attemptsDone)
+		return time.Time{}, fmt.Errorf("invalid retry interval (%v), last attempt time (%v) or attemptsDone (%v)", retryInterval, lastAttemptTime, attemptsDone)
 	}
 
 	// Retry wait time is attempts*RetryInterval to do a linear backoff.
 	interval := time.Duration(*retryInterval) * time.Second * time.Duration(attemptsDone)
-	currentTime := time.Now()
-	return interval - currentTime.Sub(lastAttemptTime.Time), nil
+	return lastAttemptTime.Add(interval), nil
+}
+
+// getBackoffRetryTime returns the time at which the given failed SparkApplication is due to be retried according
+// to the given backoff policy, whose base interval defaults to the given retry interval.
+func getBackoffRetryTime(app *v1beta2.SparkApplication, backoff *v1beta2.RestartBackoff, retryInterval *int64, lastFailureTime metav1.Time) (time.Time, error) {
+	base := retryInterval
+	if backoff.BaseSeconds != nil {
+		base = backoff.BaseSeconds
+	}
+
+	attemptsDone := app.Status.SubmissionAttempts
+	if app.Status.AppState.State == v1beta2.ApplicationStateFailing {
+		attemptsDone = app.Status.ExecutionAttempts
+	}
+	if base == nil || lastFailureTime.IsZero() || attemptsDone <= 0 {
+		return time.Time{}, fmt.Errorf("invalid backoff base (%v), last failure time (%v) or attemptsDone (%v)", base, lastFailureTime, attemptsDone)
+	}
+
+	multiplier := int64(2)
+	if backoff.Multiplier != nil {
+		multiplier = int64(*backoff.Multiplier)
+	}
+	maxInterval := time.Duration(math.MaxInt64)
+	if backoff.MaxIntervalSeconds != nil {
+		maxInterval = time.Duration(*backoff.MaxIntervalSeconds) * time.Second
+	}
+
+	interval := time.Duration(*base) * time.Second
+	for i := int32(1); i < attemptsDone && interval < maxInterval; i++ {
+		if interval > maxInterval/time.Duration(multiplier) {
+			interval = maxInterval
+			break
+		}
+		interval *= time.Duration(multiplier)
+	}
+	interval = min(interval, maxInterval)
+
+	if backoff.JitterPercent != nil && *backoff.JitterPercent > 0 {
+		if jitter := interval / 100 * time.Duration(*backoff.JitterPercent); jitter > 0 && interval <= math.MaxInt64-jitter {
+			interval += rand.N(jitter)
+		}
+	}
+	return lastFailureTime.Add(interval), nil
 }
 
 func GetLocalVolumes(app *v1beta2.SparkApplication) map[string]corev1.Volume {
@@ -177,6 +289,56 @@ func GetDefaultUIIngressName(app *v1beta2.SparkApplication) string {
 	return generateName(app.Name, "ui-ingress")
 }
 
+// GetServerServiceName returns the name of the Service exposing the server of the given SparkApplication.
+func GetServerServiceName(app *v1beta2.SparkApplication) string {
+	return generateName(app.Name, "server-svc")
+}
+
+// GetServerIngressName returns the name of the Ingress exposing the server of the given SparkApplication.
+func GetServerIngressName(app *v1beta2.SparkApplication) string {
+	return generateName(app.Name, "server-ingress")
+}
+
+// GetServerMainClass returns the main class of the server of the given SparkApplication.
+func GetServerMainClass(app *v1beta2.SparkApplication) string {
+	if app.Spec.Server.Type == v1beta2.ServerTypeThrift {
+		return common.SparkThriftServerMainClass
+	}
+	return common.SparkConnectServerMainClass
+}
+
+// GetServerPortConf returns the Spark configuration property of the port of the server of the given
+// SparkApplication and its default value.
+func GetServerPortConf(app *v1beta2.SparkApplication) (string, int32) {
+	if app.Spec.Server.Type == v1beta2.ServerTypeThrift {
+		return common.SparkHiveServer2ThriftPort, common.DefaultSparkThriftServerPort
+	}
+	return common.SparkConnectGrpcBindingPort, common.DefaultSparkConnectServerPort
+}
+
+// GetServerPort returns the port the server of the given SparkApplication listens on, which is the port of
+// the server spec if set, or else the one set in the Spark configuration properties or the default one.
+func GetServerPort(app *v1beta2.SparkApplication) int32 {
+	if app.Spec.Server.Port != nil {
+		return *app.Spec.Server.Port
+	}
+	key, defaultPort := GetServerPortConf(app)
+	if value, ok := app.Spec.SparkConf[key]; ok {
+		if port, err := strconv.ParseInt(value, 10, 32); err == nil {
+			return int32(port)
+		}
+	}
+	return defaultPort
+}
+
+// GetServerPortName returns the name of the port of the server of the given SparkApplication.
+func GetServerPortName(app *v1beta2.SparkApplication) string {
+	if app.Spec.Server.Type == v1beta2.ServerTypeThrift {
+		return "thrift-server"
+	}
+	return "spark-connect"
+}
+
 func GetResourceLabels(app *v1beta2.SparkApplication) map[string]string {
 	labels := map[string]string{
 		common.LabelSparkAppName: app.Name,
@@ -381,93 +543,79 @@ func DriverStateToApplicationState(driverState v1beta2.DriverState) v1beta2.Appl
 	}
 }
 
-// GetDriverRequestResource returns the driver request resource list.
-func GetDriverRequestResource(app *v1beta2.SparkApplication) corev1.ResourceList {
-	minResource := corev1.ResourceList{}
-
-	// Cores correspond to driver's core request
-	if app.Spec.Driver.Cores != nil {
-		if value, err := resource.ParseQuantity(fmt.Sprintf("%d", *app.Spec.Driver.Cores)); err == nil {
-			minResource[corev1.ResourceCPU] = value
-		}
+// GetFailureReason returns the reason for the failure of the last attempt of the given SparkApplication, which is
+// Unknown if it failed for a reason which was not recorded.
+func GetFailureReason(app *v1beta2.SparkApplication) v1beta2.FailureReason {
+	if app.Status.FailureReason == "" {
+		return v1beta2.FailureReasonUnknown
 	}
+	return app.Status.FailureReason
+}
 
-	// CoreLimit correspond to driver's core limit, this attribute will be used only when core request is empty.
-	if app.Spec.Driver.CoreLimit != nil {
-		if _, ok := minResource[corev1.ResourceCPU]; !ok {
-			if value, err := resource.ParseQuantity(*app.Spec.Driver.CoreLimit); err == nil {
-				minResource[corev1.ResourceCPU] = value
-			}
-		}
+// GetDriverFailureReason classifies the failure of the given failed driver pod of the SparkApplication, based on
+// the reason of the pod status, the termination state of the driver container and the executor failures.
+func GetDriverFailureReason(app *v1beta2.SparkApplication, pod *corev1.Pod) v1beta2.FailureReason {
+	switch pod.Status.Reason {
+	case "Evicted":
+		return v1beta2.FailureReasonDriverEvicted
+	case "NodeLost":
+		return v1beta2.FailureReasonNodeLost
 	}
-
-	// Memory + MemoryOverhead correspond to driver's memory request
-	if app.Spec.Driver.Memory != nil {
-		if value, err := resource.ParseQuantity(*app.Spec.Driver.Memory); err == nil {
-			minResource[corev1.ResourceMemory] = value
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type != corev1.DisruptionTarget || condition.Status != corev1.ConditionTrue {
+			continue
 		}
-	}
-	if app.Spec.Driver.MemoryOverhead != nil {
-		if value, err := resource.ParseQuantity(*app.Spec.Driver.MemoryOverhead); err == nil {
-			if existing, ok := minResource[corev1.ResourceMemory]; ok {
-				existing.Add(value)
-				minResource[corev1.ResourceMemory] = existing
-			}
+		switch condition.Reason {
+		case "DeletionByTaintManager":
+			return v1beta2.FailureReasonNodeLost
+		case "EvictionByEvictionAPI", "TerminationByKubelet":
+			return v1beta2.FailureReasonDriverEvicted
+		case "PreemptionByScheduler":
+			return v1beta2.FailureReasonPreempted
 		}
 	}
 
-	return minResource
-}
-
-// GetExecutorRequestResource returns the executor request resource list.
-func GetExecutorRequestResource(app *v1beta2.SparkApplication) corev1.ResourceList {
-	minResource := corev1.ResourceList{}
-
-	// CoreRequest correspond to executor's core request
-	if app.Spec.Executor.CoreRequest != nil {
-		if value, err := resource.ParseQuantity(*app.Spec.Executor.CoreRequest); err == nil {
-			minResource[corev1.ResourceCPU] = value
-		}
+	state := GetDriverContainerTerminatedState(pod)
+	if state != nil && state.Reason == "OOMKilled" {
+		return v1beta2.FailureReasonDriverOOMKilled
 	}
-
-	// Use Core attribute if CoreRequest is empty
-	if app.Spec.Executor.Cores != nil {
-		if _, ok := minResource[corev1.ResourceCPU]; !ok {
-			if value, err := resource.ParseQuantity(fmt.Sprintf("%d", *app.Spec.Executor.Cores)); err == nil {
-				minResource[corev1.ResourceCPU] = value
-			}
-		}
+	if GetFailedExecutorNumber(app) >= GetMaxExecutorFailures(app) {
+		return v1beta2.FailureReasonExecutorLossExceeded
 	}
-
-	// CoreLimit correspond to executor's core limit, this attribute will be used only when core request is empty.
-	if app.Spec.Executor.CoreLimit != nil {
-		if _, ok := minResource[corev1.ResourceCPU]; !ok {
-			if value, err := resource.ParseQuantity(*app.Spec.Executor.CoreLimit); err == nil {
-				minResource[corev1.ResourceCPU] = value
-			}
-		}
+	if state != nil && state.ExitCode != 0 {
+		return v1beta2.FailureReasonUserCodeError
 	}
+	return v1beta2.FailureReasonUnknown
+}
 
-	// Memory + MemoryOverhead correspond to executor's memory request
-	if app.Spec.Executor.Memory != nil {
-		if value, err := resource.ParseQuantity(*app.Spec.Executor.Memory); err == nil {
-			minResource[corev1.ResourceMemory] = value
+// GetFailedExecutorNumber returns the number of failed executors of the current attempt of the SparkApplication.
+func GetFailedExecutorNumber(app *v1beta2.SparkApplication) int32 {
+	var failed int32
+	for _, state := range app.Status.ExecutorState {
+		if state == v1beta2.ExecutorStateFailed {
+			failed++
 		}
 	}
-	if app.Spec.Executor.MemoryOverhead != nil {
-		if value, err := resource.ParseQuantity(*app.Spec.Executor.MemoryOverhead); err == nil {
-			if existing, ok := minResource[corev1.ResourceMemory]; ok {
-				existing.Add(value)
-				minResource[corev1.ResourceMemory] = existing
-			}
+	return failed
+}
+
+// GetMaxExecutorFailures returns the number of executor failures after which the driver of the SparkApplication
+// gives up. The reference for this implementation: https://github.com/apache/spark/blob/v3.5.0/core/src/main/scala/org/apache/spark/deploy/ExecutorFailureTracker.scala
+func GetMaxExecutorFailures(app *v1beta2.SparkApplication) int32 {
+	if value, ok := app.Spec.SparkConf[common.SparkExecutorMaxNumFailures]; ok {
+		if maxFailures, err := strconv.ParseInt(value, 10, 32); err == nil {
+			return int32(maxFailures)
 		}
 	}
 
-	resourceList := []corev1.ResourceList{{}}
-	for i := int32(0); i < *app.Spec.Executor.Instances; i++ {
-		resourceList = append(resourceList, minResource)
+	maxExecutors := GetInitialExecutorNumber(app)
+	if app.Spec.DynamicAllocation != nil && app.Spec.DynamicAllocation.Enabled {
+		maxExecutors = math.MaxInt32 / 2
+		if app.Spec.DynamicAllocation.MaxExecutors != nil {
+			maxExecutors = *app.Spec.DynamicAllocation.MaxExecutors
+		}
 	}
-	return SumResourceList(resourceList)
+	return max(2*maxExecutors, 3)
 }
 
 // GetInitialExecutorNumber calculates the initial number of executor pods that will be requested by the driver on startup.
@@ -495,3 +643,118 @@ func GetInitialExecutorNumber(app *v1beta2.SparkApplication) int32 {
 
 	return initialNumExecutors
 }
+
+// liveSpecFields are the fields of SparkApplicationSpec whose changes are applied to a submitted application
+// without restarting it. Changes of the executor instance count are applied live if IsLiveExecutorScaling,
+// and from the next attempt of the application otherwise.
+var liveSpecFields = map[string]bool{
+	"spec.executor.instances":     true,
+	"spec.timeToLiveSeconds":      true,
+	"spec.activeDeadlineSeconds":  true,
+	"spec.pendingDeadlineSeconds": true,
+	"spec.stuckPendingPolicy":     true,
+	"spec.restartPolicy":          true,
+	"spec.driver.labels":          true,
+	"spec.driver.annotations":     true,
+	"spec.executor.labels":        true,
+	"spec.executor.annotations":   true,
+}
+
+// GetSpecChanges returns the paths of the fields of the spec of the given SparkApplication which differ between
+// its old and new version, e.g. spec.driver.cores.
+func GetSpecChanges(oldApp, newApp *v1beta2.SparkApplication) []string {
+	var changes []string
+	diffSpecFields("spec", reflect.ValueOf(oldApp.Spec), reflect.ValueOf(newApp.Spec), &changes)
+	return changes
+}
+
+// GetRestartingSpecChanges returns the paths of the fields of the spec of the given SparkApplication which differ
+// between its old and new version and cannot be applied without restarting the application.
+func GetRestartingSpecChanges(oldApp, newApp *v1beta2.SparkApplication) []string {
+	var changes []string
+	for _, path := range GetSpecChanges(oldApp, newApp) {
+		if !IsLiveSpecChange(path) {
+			changes = append(changes, path)
+		}
+	}
+	return changes
+}
+
+// IsLiveSpecChange returns whether the change of the spec field at the given path can be applied to a
+// SparkApplication without restarting it.
+func IsLiveSpecChange(path string) bool {
+	for field := range liveSpecFields {
+		if path == field || strings.HasPrefix(path, field+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// IsLiveExecutorScaling returns whether the executors of the given running SparkApplication can be scaled live to
+// its executor instance count. This is the case when dynamic allocation is enabled, as the driver then copes with
+// executors being removed and not being created, so that its executors can be limited from outside of it. Spark
+// does not provide a way to change the executor count of a driver without dynamic allocation.
+func IsLiveExecutorScaling(app *v1beta2.SparkApplication) bool {
+	dynamicAllocation := app.Spec.DynamicAllocation
+	return dynamicAllocation != nil && dynamicAllocation.Enabled && app.Spec.Executor.Instances != nil
+}
+
+// GetExecutorLimit returns the number of executors the given running SparkApplication with dynamic allocation is
+// limited to when scaled live, which is its executor instance count bounded by its minimum and maximum number of
+// executors.
+func GetExecutorLimit(app *v1beta2.SparkApplication) int32 {
+	limit := *app.Spec.Executor.Instances
+	if maxExecutors := app.Spec.DynamicAllocation.MaxExecutors; maxExecutors != nil {
+		limit = min(limit, *maxExecutors)
+	}
+	if minExecutors := app.Spec.DynamicAllocation.MinExecutors; minExecutors != nil {
+		limit = max(limit, *minExecutors)
+	}
+	return limit
+}
+
+// GetExecutorSelector returns the label selector of the executor pods of the current attempt of the given
+// SparkApplication.
+func GetExecutorSelector(app *v1beta2.SparkApplication) string {
+	selector := GetResourceLabels(app)
+	selector[common.LabelSparkRole] = common.SparkRoleExecutor
+	return labels.SelectorFromSet(selector).String()
+}
+
+// GetExecutorReplicas returns the number of running executors of the given SparkApplication.
+func GetExecutorReplicas(app *v1beta2.SparkApplication) int32 {
+	var replicas int32
+	for _, state := range app.Status.ExecutorState {
+		if state == v1beta2.ExecutorStateRunning {
+			replicas++
+		}
+	}
+	return replicas
+}
+
+// diffSpecFields appends the paths of the fields differing between the given values of the same struct type to
+// changes. Struct fields of types of the API package are compared field by field, other fields as a whole.
+func diffSpecFields(path string, oldValue, newValue reflect.Value, changes *[]string) {
+	apiPkgPath := reflect.TypeOf(v1beta2.SparkApplicationSpec{}).PkgPath()
+	valueType := oldValue.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		fieldPath := path
+		if name != "" {
+			fieldPath = path + "." + name
+		}
+		if field.Type.Kind() == reflect.Struct && field.Type.PkgPath() == apiPkgPath {
+			diffSpecFields(fieldPath, oldValue.Field(i), newValue.Field(i), changes)
+			continue
+		}
+		if !equality.Semantic.DeepEqual(oldValue.Field(i).Interface(), newValue.Field(i).Interface()) {
+			*changes = append(*changes, fieldPath)
+		}
+	}
+}
@@ -356,3 +356,434 @@ var _ = Describe("DriverStateToApplicationState", func() {
 		Expect(util.DriverStateToApplicationState(v1beta2.DriverStateUnknown)).To(Equal(v1beta2.ApplicationStateUnknown))
 	})
 })
+
+var _ = Describe("GetServerPort", func() {
+	Context("Spark Connect server without port", func() {
+		app := &v1beta2.SparkApplication{
+			Spec: v1beta2.SparkApplicationSpec{
+				Server: &v1beta2.ServerSpec{Type: v1beta2.ServerTypeConnect},
+			},
+		}
+
+		It("Should return the default Spark Connect port", func() {
+			Expect(util.GetServerPort(app)).To(Equal(common.DefaultSparkConnectServerPort))
+		})
+	})
+
+	Context("Thrift server with port in Spark conf", func() {
+		app := &v1beta2.SparkApplication{
+			Spec: v1beta2.SparkApplicationSpec{
+				SparkConf: map[string]string{common.SparkHiveServer2ThriftPort: "10001"},
+				Server:    &v1beta2.ServerSpec{Type: v1beta2.ServerTypeThrift},
+			},
+		}
+
+		It("Should return the port in Spark conf", func() {
+			Expect(util.GetServerPort(app)).To(Equal(int32(10001)))
+		})
+	})
+
+	Context("Thrift server with port in both server spec and Spark conf", func() {
+		app := &v1beta2.SparkApplication{
+			Spec: v1beta2.SparkApplicationSpec{
+				SparkConf: map[string]string{common.SparkHiveServer2ThriftPort: "10001"},
+				Server:    &v1beta2.ServerSpec{Type: v1beta2.ServerTypeThrift, Port: util.Int32Ptr(10002)},
+			},
+		}
+
+		It("Should return the port in server spec", func() {
+			Expect(util.GetServerPort(app)).To(Equal(int32(10002)))
+		})
+	})
+})
+
+var _ = Describe("ShouldRetry", func() {
+	Context("Server application which is succeeding with restart policy Never", func() {
+		app := &v1beta2.SparkApplication{
+			Spec: v1beta2.SparkApplicationSpec{
+				RestartPolicy: v1beta2.RestartPolicy{Type: v1beta2.RestartPolicyNever},
+				Server:        &v1beta2.ServerSpec{Type: v1beta2.ServerTypeConnect},
+			},
+			Status: v1beta2.SparkApplicationStatus{
+				AppState: v1beta2.ApplicationState{State: v1beta2.ApplicationStateSucceeding},
+			},
+		}
+
+		It("Should retry", func() {
+			Expect(util.ShouldRetry(app)).To(BeTrue())
+		})
+	})
+
+	Context("Batch application which is succeeding with restart policy Never", func() {
+		app := &v1beta2.SparkApplication{
+			Spec: v1beta2.SparkApplicationSpec{
+				RestartPolicy: v1beta2.RestartPolicy{Type: v1beta2.RestartPolicyNever},
+			},
+			Status: v1beta2.SparkApplicationStatus{
+				AppState: v1beta2.ApplicationState{State: v1beta2.ApplicationStateSucceeding},
+			},
+		}
+
+		It("Should not retry", func() {
+			Expect(util.ShouldRetry(app)).To(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("GetRestartingSpecChanges", func() {
+	oldApp := &v1beta2.SparkApplication{
+		Spec: v1beta2.SparkApplicationSpec{
+			Driver: v1beta2.DriverSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{Cores: util.Int32Ptr(1)},
+			},
+			Executor: v1beta2.ExecutorSpec{
+				Instances: util.Int32Ptr(2),
+			},
+			DynamicAllocation: &v1beta2.DynamicAllocation{
+				Enabled:      true,
+				MinExecutors: util.Int32Ptr(1),
+				MaxExecutors: util.Int32Ptr(5),
+			},
+		},
+	}
+
+	Context("Changes which can be applied live", func() {
+		newApp := oldApp.DeepCopy()
+		newApp.Spec.TimeToLiveSeconds = util.Int64Ptr(60)
+		newApp.Spec.RestartPolicy.Type = v1beta2.RestartPolicyAlways
+		newApp.Spec.Executor.Annotations = map[string]string{"key": "value"}
+		newApp.Spec.Executor.Instances = util.Int32Ptr(4)
+
+		It("Should return all the changes", func() {
+			Expect(util.GetSpecChanges(oldApp, newApp)).To(ConsistOf(
+				"spec.timeToLiveSeconds",
+				"spec.restartPolicy.type",
+				"spec.executor.annotations",
+				"spec.executor.instances",
+			))
+		})
+
+		It("Should return no restarting changes", func() {
+			Expect(util.GetRestartingSpecChanges(oldApp, newApp)).To(BeEmpty())
+		})
+	})
+
+	Context("Changes which require a restart", func() {
+		newApp := oldApp.DeepCopy()
+		newApp.Spec.Driver.Cores = util.Int32Ptr(2)
+		newApp.Spec.Executor.Instances = util.Int32Ptr(6)
+
+		It("Should return the restarting changes", func() {
+			Expect(util.GetRestartingSpecChanges(oldApp, newApp)).To(ConsistOf("spec.driver.cores"))
+		})
+	})
+})
+
+var _ = Describe("IsLiveExecutorScaling", func() {
+	app := &v1beta2.SparkApplication{
+		Spec: v1beta2.SparkApplicationSpec{
+			Executor: v1beta2.ExecutorSpec{
+				Instances: util.Int32Ptr(4),
+			},
+			DynamicAllocation: &v1beta2.DynamicAllocation{
+				Enabled:      true,
+				MinExecutors: util.Int32Ptr(1),
+				MaxExecutors: util.Int32Ptr(5),
+			},
+		},
+	}
+
+	It("Should scale live with dynamic allocation", func() {
+		Expect(util.IsLiveExecutorScaling(app)).To(BeTrue())
+		Expect(util.GetExecutorLimit(app)).To(Equal(int32(4)))
+	})
+
+	It("Should limit the executors within the dynamic allocation bounds", func() {
+		scaled := app.DeepCopy()
+		scaled.Spec.Executor.Instances = util.Int32Ptr(6)
+		Expect(util.GetExecutorLimit(scaled)).To(Equal(int32(5)))
+		scaled.Spec.Executor.Instances = util.Int32Ptr(0)
+		Expect(util.GetExecutorLimit(scaled)).To(Equal(int32(1)))
+	})
+
+	It("Should not scale live without dynamic allocation", func() {
+		scaled := app.DeepCopy()
+		scaled.Spec.DynamicAllocation = nil
+		Expect(util.IsLiveExecutorScaling(scaled)).To(BeFalse())
+	})
+})
+
+var _ = Describe("GetExecutorSelector", func() {
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-app"},
+		Status: v1beta2.SparkApplicationStatus{
+			SubmissionID: "test-submission",
+			ExecutorState: map[string]v1beta2.ExecutorState{
+				"exec-1": v1beta2.ExecutorStateRunning,
+				"exec-2": v1beta2.ExecutorStateRunning,
+				"exec-3": v1beta2.ExecutorStateFailed,
+			},
+		},
+	}
+
+	It("Should select the executor pods of the current attempt", func() {
+		Expect(util.GetExecutorSelector(app)).To(Equal(
+			"spark-role=executor,sparkoperator.k8s.io/app-name=test-app,sparkoperator.k8s.io/submission-id=test-submission"))
+	})
+
+	It("Should count the running executors", func() {
+		Expect(util.GetExecutorReplicas(app)).To(Equal(int32(2)))
+	})
+})
+
+var _ = Describe("GetNextRetryTime", func() {
+	lastAttemptTime := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	terminationTime := metav1.NewTime(lastAttemptTime.Add(time.Hour))
+
+	Context("SparkApplication without backoff policy", func() {
+		app := &v1beta2.SparkApplication{
+			Spec: v1beta2.SparkApplicationSpec{
+				RestartPolicy: v1beta2.RestartPolicy{
+					Type:                   v1beta2.RestartPolicyOnFailure,
+					OnFailureRetryInterval: util.Int64Ptr(10),
+				},
+			},
+			Status: v1beta2.SparkApplicationStatus{
+				AppState:                  v1beta2.ApplicationState{State: v1beta2.ApplicationStateFailing},
+				SubmissionAttempts:        3,
+				LastSubmissionAttemptTime: lastAttemptTime,
+				TerminationTime:           terminationTime,
+			},
+		}
+
+		It("Should retry after a linear interval since the last attempt", func() {
+			nextRetryTime, err := util.GetNextRetryTime(app)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nextRetryTime).To(Equal(lastAttemptTime.Add(30 * time.Second)))
+		})
+	})
+
+	Context("SparkApplication with backoff policy", func() {
+		app := &v1beta2.SparkApplication{
+			Spec: v1beta2.SparkApplicationSpec{
+				RestartPolicy: v1beta2.RestartPolicy{
+					Type:                   v1beta2.RestartPolicyOnFailure,
+					OnFailureRetryInterval: util.Int64Ptr(10),
+					Backoff: &v1beta2.RestartBackoff{
+						Multiplier:         util.Int32Ptr(3),
+						MaxIntervalSeconds: util.Int64Ptr(600),
+					},
+				},
+			},
+			Status: v1beta2.SparkApplicationStatus{
+				AppState:                  v1beta2.ApplicationState{State: v1beta2.ApplicationStateFailing},
+				ExecutionAttempts:         3,
+				LastSubmissionAttemptTime: lastAttemptTime,
+				TerminationTime:           terminationTime,
+			},
+		}
+
+		It("Should retry after an exponential interval since the failure", func() {
+			nextRetryTime, err := util.GetNextRetryTime(app)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nextRetryTime).To(Equal(terminationTime.Add(90 * time.Second)))
+		})
+
+		It("Should cap the interval", func() {
+			capped := app.DeepCopy()
+			capped.Status.ExecutionAttempts = 100
+			nextRetryTime, err := util.GetNextRetryTime(capped)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nextRetryTime).To(Equal(terminationTime.Add(600 * time.Second)))
+		})
+
+		It("Should add a bounded jitter", func() {
+			jittered := app.DeepCopy()
+			jittered.Spec.RestartPolicy.Backoff.JitterPercent = util.Int32Ptr(50)
+			nextRetryTime, err := util.GetNextRetryTime(jittered)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nextRetryTime).To(BeTemporally(">=", terminationTime.Add(90*time.Second)))
+			Expect(nextRetryTime).To(BeTemporally("<", terminationTime.Add(135*time.Second)))
+		})
+	})
+
+	Context("SparkApplication which failed submission with backoff policy", func() {
+		app := &v1beta2.SparkApplication{
+			Spec: v1beta2.SparkApplicationSpec{
+				RestartPolicy: v1beta2.RestartPolicy{
+					Type:    v1beta2.RestartPolicyOnFailure,
+					Backoff: &v1beta2.RestartBackoff{BaseSeconds: util.Int64Ptr(5)},
+				},
+			},
+			Status: v1beta2.SparkApplicationStatus{
+				AppState:                  v1beta2.ApplicationState{State: v1beta2.ApplicationStateFailedSubmission},
+				SubmissionAttempts:        2,
+				LastSubmissionAttemptTime: lastAttemptTime,
+			},
+		}
+
+		It("Should retry after an exponential interval since the last attempt", func() {
+			nextRetryTime, err := util.GetNextRetryTime(app)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nextRetryTime).To(Equal(lastAttemptTime.Add(10 * time.Second)))
+		})
+	})
+})
+
+var _ = Describe("ShouldRetry on deadline exceeded", func() {
+	app := &v1beta2.SparkApplication{
+		Spec: v1beta2.SparkApplicationSpec{
+			RestartPolicy: v1beta2.RestartPolicy{Type: v1beta2.RestartPolicyAlways},
+		},
+		Status: v1beta2.SparkApplicationStatus{
+			AppState: v1beta2.ApplicationState{
+				State:  v1beta2.ApplicationStateFailing,
+				Reason: v1beta2.ApplicationStateReasonDeadlineExceeded,
+			},
+		},
+	}
+
+	It("Should not retry by default", func() {
+		Expect(util.ShouldRetry(app)).To(BeFalse())
+	})
+
+	It("Should retry if the restart policy asks for it", func() {
+		retried := app.DeepCopy()
+		retried.Spec.RestartPolicy.RetryOnDeadlineExceeded = util.BoolPtr(true)
+		Expect(util.ShouldRetry(retried)).To(BeTrue())
+	})
+})
+
+var _ = Describe("GetDriverFailureReason", func() {
+	app := &v1beta2.SparkApplication{
+		Spec: v1beta2.SparkApplicationSpec{
+			Executor: v1beta2.ExecutorSpec{Instances: util.Int32Ptr(1)},
+		},
+	}
+
+	newDriverPod := func(reason string, exitCode int32) *corev1.Pod {
+		return &corev1.Pod{
+			Status: corev1.PodStatus{
+				Phase: corev1.PodFailed,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name: common.SparkDriverContainerName,
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{Reason: reason, ExitCode: exitCode},
+					},
+				}},
+			},
+		}
+	}
+
+	It("Should classify evicted drivers", func() {
+		pod := newDriverPod("Error", 137)
+		pod.Status.Reason = "Evicted"
+		Expect(util.GetDriverFailureReason(app, pod)).To(Equal(v1beta2.FailureReasonDriverEvicted))
+	})
+
+	It("Should classify drivers deleted from lost nodes", func() {
+		pod := newDriverPod("Error", 143)
+		pod.Status.Conditions = []corev1.PodCondition{{
+			Type:   corev1.DisruptionTarget,
+			Status: corev1.ConditionTrue,
+			Reason: "DeletionByTaintManager",
+		}}
+		Expect(util.GetDriverFailureReason(app, pod)).To(Equal(v1beta2.FailureReasonNodeLost))
+	})
+
+	It("Should classify drivers killed for running out of memory", func() {
+		Expect(util.GetDriverFailureReason(app, newDriverPod("OOMKilled", 137))).To(Equal(v1beta2.FailureReasonDriverOOMKilled))
+	})
+
+	It("Should classify drivers which gave up after too many executor failures", func() {
+		failed := app.DeepCopy()
+		failed.Status.ExecutorState = map[string]v1beta2.ExecutorState{
+			"exec-1": v1beta2.ExecutorStateFailed,
+			"exec-2": v1beta2.ExecutorStateFailed,
+			"exec-3": v1beta2.ExecutorStateFailed,
+		}
+		Expect(util.GetDriverFailureReason(failed, newDriverPod("Error", 1))).To(Equal(v1beta2.FailureReasonExecutorLossExceeded))
+	})
+
+	It("Should classify other non-zero exit codes as user code errors", func() {
+		Expect(util.GetDriverFailureReason(app, newDriverPod("Error", 1))).To(Equal(v1beta2.FailureReasonUserCodeError))
+	})
+
+	It("Should not classify drivers without container status", func() {
+		Expect(util.GetDriverFailureReason(app, &corev1.Pod{})).To(Equal(v1beta2.FailureReasonUnknown))
+	})
+})
+
+var _ = Describe("GetMaxExecutorFailures", func() {
+	It("Should default to twice the executor instances, and at least 3", func() {
+		app := &v1beta2.SparkApplication{
+			Spec: v1beta2.SparkApplicationSpec{
+				Executor: v1beta2.ExecutorSpec{Instances: util.Int32Ptr(5)},
+			},
+		}
+		Expect(util.GetMaxExecutorFailures(app)).To(Equal(int32(10)))
+		app.Spec.Executor.Instances = util.Int32Ptr(1)
+		Expect(util.GetMaxExecutorFailures(app)).To(Equal(int32(3)))
+	})
+
+	It("Should use twice the max executors of dynamic allocation", func() {
+		app := &v1beta2.SparkApplication{
+			Spec: v1beta2.SparkApplicationSpec{
+				DynamicAllocation: &v1beta2.DynamicAllocation{Enabled: true, MaxExecutors: util.Int32Ptr(20)},
+			},
+		}
+		Expect(util.GetMaxExecutorFailures(app)).To(Equal(int32(40)))
+	})
+
+	It("Should use the configured max number of failures", func() {
+		app := &v1beta2.SparkApplication{
+			Spec: v1beta2.SparkApplicationSpec{
+				SparkConf: map[string]string{common.SparkExecutorMaxNumFailures: "7"},
+			},
+		}
+		Expect(util.GetMaxExecutorFailures(app)).To(Equal(int32(7)))
+	})
+})
+
+var _ = Describe("ShouldRetry on failure reasons", func() {
+	app := &v1beta2.SparkApplication{
+		Spec: v1beta2.SparkApplicationSpec{
+			RestartPolicy: v1beta2.RestartPolicy{
+				Type:                  v1beta2.RestartPolicyAlways,
+				RetryOnFailureReasons: []v1beta2.FailureReason{v1beta2.FailureReasonNodeLost, v1beta2.FailureReasonDeadlineExceeded},
+			},
+		},
+		Status: v1beta2.SparkApplicationStatus{
+			AppState: v1beta2.ApplicationState{State: v1beta2.ApplicationStateFailing},
+		},
+	}
+
+	It("Should retry failures with a selected reason", func() {
+		failed := app.DeepCopy()
+		failed.Status.FailureReason = v1beta2.FailureReasonNodeLost
+		Expect(util.ShouldRetry(failed)).To(BeTrue())
+	})
+
+	It("Should not retry failures with another reason", func() {
+		failed := app.DeepCopy()
+		failed.Status.FailureReason = v1beta2.FailureReasonUserCodeError
+		Expect(util.ShouldRetry(failed)).To(BeFalse())
+	})
+
+	It("Should not retry failures without a reason", func() {
+		Expect(util.ShouldRetry(app)).To(BeFalse())
+	})
+
+	It("Should retry exceeded deadlines if selected", func() {
+		failed := app.DeepCopy()
+		failed.Status.AppState.Reason = v1beta2.ApplicationStateReasonDeadlineExceeded
+		failed.Status.FailureReason = v1beta2.FailureReasonDeadlineExceeded
+		Expect(util.ShouldRetry(failed)).To(BeTrue())
+	})
+
+	It("Should still retry successes", func() {
+		succeeding := app.DeepCopy()
+		succeeding.Status.AppState.State = v1beta2.ApplicationStateSucceeding
+		Expect(util.ShouldRetry(succeeding)).To(BeTrue())
+	})
+})
@@ -17,8 +17,11 @@ limitations under the License.
 package util
 
 import (
+	"fmt"
+
 	corev1 "k8s.io/api/core/v1"
 
+	"github.com/kubeflow/spark-operator/api/v1beta2"
 	"github.com/kubeflow/spark-operator/pkg/common"
 )
 
@@ -51,3 +54,65 @@ func GetAppName(pod *corev1.Pod) string {
 func GetSparkApplicationID(pod *corev1.Pod) string {
 	return pod.Labels[common.LabelSparkApplicationSelector]
 }
+
+// IsPodReady returns whether the given pod is ready to serve requests.
+func IsPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// IsPodActive returns whether the given pod has neither terminated nor is being deleted.
+func IsPodActive(pod *corev1.Pod) bool {
+	return pod.DeletionTimestamp == nil && pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed
+}
+
+// stuckContainerWaitingReasons are the reasons for which a waiting container does not start without intervention.
+var stuckContainerWaitingReasons = map[string]v1beta2.FailureReason{
+	"ImagePullBackOff":           v1beta2.FailureReasonImagePullFailure,
+	"ErrImagePull":               v1beta2.FailureReasonImagePullFailure,
+	"InvalidImageName":           v1beta2.FailureReasonImagePullFailure,
+	"CreateContainerConfigError": v1beta2.FailureReasonContainerConfigError,
+	"CreateContainerError":       v1beta2.FailureReasonContainerConfigError,
+}
+
+// GetPodStuckPendingReason returns the reason why the given pending pod cannot start, along with a message
+// describing it, or an empty reason if it is not known to be stuck. A pod is stuck if it cannot be scheduled, or
+// if one of its containers is waiting for a reason that does not resolve itself, e.g. ImagePullBackOff.
+func GetPodStuckPendingReason(pod *corev1.Pod) (string, string) {
+	if pod.Status.Phase != corev1.PodPending {
+		return "", ""
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse &&
+			condition.Reason == corev1.PodReasonUnschedulable {
+			return condition.Reason, condition.Message
+		}
+	}
+
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if waiting := status.State.Waiting; waiting != nil {
+			if _, ok := stuckContainerWaitingReasons[waiting.Reason]; ok {
+				return waiting.Reason, fmt.Sprintf("container %s: %s", status.Name, waiting.Message)
+			}
+		}
+	}
+	return "", ""
+}
+
+// GetStuckPendingFailureReason returns the failure reason of an application whose pods are stuck pending for
+// the given reason, as returned by GetPodStuckPendingReason.
+func GetStuckPendingFailureReason(reason string) v1beta2.FailureReason {
+	if reason == corev1.PodReasonUnschedulable {
+		return v1beta2.FailureReasonUnschedulable
+	}
+	if failureReason, ok := stuckContainerWaitingReasons[reason]; ok {
+		return failureReason
+	}
+	return v1beta2.FailureReasonUnknown
+}
@@ -23,6 +23,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/kubeflow/spark-operator/api/v1beta2"
 	"github.com/kubeflow/spark-operator/pkg/common"
 	"github.com/kubeflow/spark-operator/pkg/util"
 )
@@ -347,3 +348,77 @@ var _ = Describe("GetSparkExecutorID", func() {
 		})
 	})
 })
+
+var _ = Describe("GetPodStuckPendingReason", func() {
+	Context("Unschedulable pod", func() {
+		pod := &corev1.Pod{
+			Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				Conditions: []corev1.PodCondition{{
+					Type:    corev1.PodScheduled,
+					Status:  corev1.ConditionFalse,
+					Reason:  corev1.PodReasonUnschedulable,
+					Message: "0/3 nodes are available",
+				}},
+			},
+		}
+
+		It("Should return Unschedulable", func() {
+			reason, message := util.GetPodStuckPendingReason(pod)
+			Expect(reason).To(Equal("Unschedulable"))
+			Expect(message).To(Equal("0/3 nodes are available"))
+		})
+	})
+
+	Context("Pod whose image cannot be pulled", func() {
+		pod := &corev1.Pod{
+			Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name: common.SparkDriverContainerName,
+					State: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "Back-off pulling image"},
+					},
+				}},
+			},
+		}
+
+		It("Should return ImagePullBackOff", func() {
+			reason, message := util.GetPodStuckPendingReason(pod)
+			Expect(reason).To(Equal("ImagePullBackOff"))
+			Expect(message).To(Equal("container spark-kubernetes-driver: Back-off pulling image"))
+		})
+	})
+
+	Context("Pod whose container is being created", func() {
+		pod := &corev1.Pod{
+			Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					State: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"},
+					},
+				}},
+			},
+		}
+
+		It("Should not be stuck", func() {
+			reason, _ := util.GetPodStuckPendingReason(pod)
+			Expect(reason).To(BeEmpty())
+		})
+	})
+})
+
+var _ = Describe("GetStuckPendingFailureReason", func() {
+	It("Should classify unschedulable pods", func() {
+		Expect(util.GetStuckPendingFailureReason("Unschedulable")).To(Equal(v1beta2.FailureReasonUnschedulable))
+	})
+
+	It("Should classify pods whose image cannot be pulled", func() {
+		Expect(util.GetStuckPendingFailureReason("ErrImagePull")).To(Equal(v1beta2.FailureReasonImagePullFailure))
+	})
+
+	It("Should classify pods whose containers cannot be created", func() {
+		Expect(util.GetStuckPendingFailureReason("CreateContainerConfigError")).To(Equal(v1beta2.FailureReasonContainerConfigError))
+	})
+})
@@ -21,6 +21,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"unicode/utf8"
 
 	"golang.org/x/mod/semver"
 	"sigs.k8s.io/yaml"
@@ -82,6 +83,23 @@ func StringPtr(s string) *string {
 	return &s
 }
 
+// TruncateString truncates the given string to at most maxLength bytes, replacing the truncated part with
+// an ellipsis and without splitting a UTF-8 character.
+func TruncateString(s string, maxLength int) string {
+	const ellipsis = "..."
+	if len(s) <= maxLength {
+		return s
+	}
+	if maxLength <= len(ellipsis) {
+		return ellipsis[:maxLength]
+	}
+	end := maxLength - len(ellipsis)
+	for end > 0 && !utf8.RuneStart(s[end]) {
+		end--
+	}
+	return s[:end] + ellipsis
+}
+
 // CompareSemanticVersion compares two semantic versions.
 func CompareSemanticVersion(v1, v2 string) int {
 	// Add 'v' prefix if needed
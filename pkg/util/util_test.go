@@ -132,6 +132,20 @@ var _ = Describe("StringPtr", func() {
 	})
 })
 
+var _ = Describe("TruncateString", func() {
+	It("Should return the string if it is not longer than the maximum length", func() {
+		Expect(util.TruncateString("spark-pi", 8)).To(Equal("spark-pi"))
+	})
+
+	It("Should truncate the string to the maximum length", func() {
+		Expect(util.TruncateString("spark-pi-driver", 10)).To(Equal("spark-p..."))
+	})
+
+	It("Should not split a UTF-8 character", func() {
+		Expect(util.TruncateString("spark-€-driver", 11)).To(Equal("spark-..."))
+	})
+})
+
 var _ = Describe("CompareSemanticVersions", func() {
 	It("Should return 0 if the two versions are equal", func() {
 		Expect(util.CompareSemanticVersion("1.2.3", "1.2.3"))
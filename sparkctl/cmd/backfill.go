@@ -0,0 +1,88 @@
+/*
+Copyright 2017 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	crdclientset "github.com/kubeflow/spark-operator/pkg/client/clientset/versioned"
+	"github.com/kubeflow/spark-operator/pkg/common"
+)
+
+var BackfillStart string
+var BackfillEnd string
+
+var backfillCmd = &cobra.Command{
+	Use:   "backfill <name> --start <time> --end <time>",
+	Short: "Backfill runs of a ScheduledSparkApplication",
+	Long: `Start a run of a ScheduledSparkApplication with a given name for every scheduled time between the given start
+and end times, both included. The runs are started in chronological order as the concurrency policy of the
+ScheduledSparkApplication allows it.`,
+	Run: func(_ *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "must specify a ScheduledSparkApplication name")
+			return
+		}
+
+		start, err := time.Parse(time.RFC3339, BackfillStart)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid start time %q: %v\n", BackfillStart, err)
+			return
+		}
+		end, err := time.Parse(time.RFC3339, BackfillEnd)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid end time %q: %v\n", BackfillEnd, err)
+			return
+		}
+		if end.Before(start) {
+			fmt.Fprintln(os.Stderr, "end time must not be before start time")
+			return
+		}
+
+		crdClientset, err := getSparkApplicationClient()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to get SparkApplication client: %v\n", err)
+			return
+		}
+
+		if err := doBackfill(args[0], start, end, crdClientset); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to backfill ScheduledSparkApplication %s: %v\n", args[0], err)
+		}
+	},
+}
+
+func init() {
+	backfillCmd.Flags().StringVar(&BackfillStart, "start", "", "The start of the interval to backfill in RFC 3339 format")
+	backfillCmd.Flags().StringVar(&BackfillEnd, "end", "", "The end of the interval to backfill in RFC 3339 format")
+	_ = backfillCmd.MarkFlagRequired("start")
+	_ = backfillCmd.MarkFlagRequired("end")
+}
+
+func doBackfill(name string, start time.Time, end time.Time, crdClientset crdclientset.Interface) error {
+	value := fmt.Sprintf("%s/%s", start.UTC().Format(time.RFC3339), end.UTC().Format(time.RFC3339))
+	if err := annotateScheduledSparkApplication(name, common.AnnotationBackfill, value, crdClientset); err != nil {
+		return err
+	}
+
+	fmt.Printf("ScheduledSparkApplication \"%s\" backfill requested for %s\n", name, value)
+
+	return nil
+}
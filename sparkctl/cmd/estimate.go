@@ -0,0 +1,112 @@
+/*
+Copyright 2017 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kubeflow/spark-operator/pkg/resourceusage"
+)
+
+var EstimateOutput string
+
+var estimateCmd = &cobra.Command{
+	Use:   "estimate <yaml file>",
+	Short: "Estimate the resource usage of a SparkApplication",
+	Long: `Estimate the resource requests and limits of the driver and executor pods of a SparkApplication
+defined in a YAML file, and of the application in total, in the same way as the operator does.`,
+	Run: func(_ *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "must specify a YAML file of a SparkApplication")
+			return
+		}
+
+		if err := doEstimate(args[0], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to estimate resource usage of %s: %v\n", args[0], err)
+		}
+	},
+}
+
+func init() {
+	estimateCmd.Flags().StringVarP(&EstimateOutput, "output", "o", "table",
+		"The output format, either table or json")
+}
+
+func doEstimate(yamlFile string, out io.Writer) error {
+	app, err := loadFromYAML(yamlFile)
+	if err != nil {
+		return fmt.Errorf("failed to read a SparkApplication from %s: %v", yamlFile, err)
+	}
+
+	usage, err := resourceusage.Calculate(app)
+	if err != nil {
+		return err
+	}
+
+	switch EstimateOutput {
+	case "table":
+		printUsage(usage, out)
+	case "json":
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(usage)
+	default:
+		return fmt.Errorf("unsupported output format %q", EstimateOutput)
+	}
+	return nil
+}
+
+func printUsage(usage *resourceusage.Usage, out io.Writer) {
+	table := tablewriter.NewWriter(out)
+	table.SetHeader([]string{"Pod", "Count", "Requests", "Limits"})
+	if usage.Driver != nil {
+		table.Append([]string{"driver", "1", formatResourceList(usage.Driver.Requests), formatResourceList(usage.Driver.Limits)})
+	}
+	table.Append([]string{
+		"executor",
+		fmt.Sprintf("%d", usage.Executors),
+		formatResourceList(usage.Executor.Requests),
+		formatResourceList(usage.Executor.Limits),
+	})
+	table.Append([]string{"total", "", formatResourceList(usage.Total.Requests), formatResourceList(usage.Total.Limits)})
+	table.Render()
+}
+
+// formatResourceList formats a resource list as comma-separated name=quantity pairs sorted by name.
+func formatResourceList(resources corev1.ResourceList) string {
+	names := make([]string, 0, len(resources))
+	for name := range resources {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		quantity := resources[corev1.ResourceName(name)]
+		pairs = append(pairs, fmt.Sprintf("%s=%s", name, quantity.String()))
+	}
+	return formatNotAvailable(strings.Join(pairs, ", "))
+}
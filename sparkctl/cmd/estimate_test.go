@@ -0,0 +1,59 @@
+/*
+Copyright 2017 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/kubeflow/spark-operator/pkg/resourceusage"
+)
+
+func TestFormatResourceList(t *testing.T) {
+	assert.Equal(t, "N.A.", formatResourceList(nil))
+	assert.Equal(t, "cpu=500m, memory=1408Mi, nvidia.com/gpu=1", formatResourceList(corev1.ResourceList{
+		"nvidia.com/gpu":      resource.MustParse("1"),
+		corev1.ResourceMemory: resource.MustParse("1408Mi"),
+		corev1.ResourceCPU:    resource.MustParse("500m"),
+	}))
+}
+
+func TestDoEstimate(t *testing.T) {
+	EstimateOutput = "json"
+	defer func() { EstimateOutput = "table" }()
+
+	var out bytes.Buffer
+	assert.Nil(t, doEstimate("testdata/test-app.yaml", &out))
+
+	usage := &resourceusage.Usage{}
+	assert.Nil(t, json.Unmarshal(out.Bytes(), usage))
+	assert.NotNil(t, usage.Driver)
+	assert.Equal(t, int32(1), usage.Executors)
+	// Default 1 core and 1g of memory plus the minimum memory overhead of 384Mi for both pods.
+	cpu := usage.Total.Requests[corev1.ResourceCPU]
+	assert.Equal(t, "2", cpu.String())
+	memory := usage.Total.Requests[corev1.ResourceMemory]
+	assert.Equal(t, "2816Mi", memory.String())
+
+	EstimateOutput = "yaml"
+	assert.NotNil(t, doEstimate("testdata/test-app.yaml", &out))
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2017 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	crdclientset "github.com/kubeflow/spark-operator/pkg/client/clientset/versioned"
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph <name>",
+	Short: "Show the dependency graph of a SparkApplication",
+	Long: `Show the state of the SparkApplications in the dependency graph of a SparkApplication with a given name,
+i.e. all the applications it is connected to through their dependencies, in dependency order.`,
+	Run: func(_ *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "must specify a SparkApplication name")
+			return
+		}
+
+		crdClientset, err := getSparkApplicationClient()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to get SparkApplication client: %v\n", err)
+			return
+		}
+
+		if err := doGraph(args[0], crdClientset); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to show dependency graph of SparkApplication %s: %v\n", args[0], err)
+		}
+	},
+}
+
+func doGraph(name string, crdClientset crdclientset.Interface) error {
+	apps, err := crdClientset.SparkoperatorV1beta2().SparkApplications(Namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	graph, err := getDependencyGraph(name, apps.Items)
+	if err != nil {
+		return err
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Name", "State", "Depends On", "Message"})
+	for _, app := range graph {
+		table.Append([]string{
+			app.Name,
+			formatNotAvailable(string(app.Status.AppState.State)),
+			strings.Join(app.Spec.DependsOn, ", "),
+			app.Status.AppState.ErrorMessage,
+		})
+	}
+	table.Render()
+
+	return nil
+}
+
+// getDependencyGraph returns the SparkApplications connected to the one with the given name through their
+// dependencies, sorted so that every application comes after the ones it depends on.
+func getDependencyGraph(name string, apps []v1beta2.SparkApplication) ([]*v1beta2.SparkApplication, error) {
+	byName := make(map[string]*v1beta2.SparkApplication)
+	downstream := make(map[string][]string)
+	for i := range apps {
+		app := &apps[i]
+		byName[app.Name] = app
+		for _, upstream := range app.Spec.DependsOn {
+			downstream[upstream] = append(downstream[upstream], app.Name)
+		}
+	}
+	if _, ok := byName[name]; !ok {
+		return nil, fmt.Errorf("SparkApplication %s not found", name)
+	}
+
+	// Collect the connected applications, ignoring dependencies on applications that do not exist.
+	connected := map[string]bool{name: true}
+	queue := []string{name}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		neighbors := append([]string{}, downstream[current]...)
+		neighbors = append(neighbors, byName[current].Spec.DependsOn...)
+		for _, neighbor := range neighbors {
+			if _, ok := byName[neighbor]; ok && !connected[neighbor] {
+				connected[neighbor] = true
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+
+	// Sort the applications topologically, breaking ties by name.
+	var graph []*v1beta2.SparkApplication
+	done := make(map[string]bool)
+	for len(graph) < len(connected) {
+		var ready []string
+		for current := range connected {
+			if done[current] {
+				continue
+			}
+			isReady := true
+			for _, upstream := range byName[current].Spec.DependsOn {
+				if connected[upstream] && !done[upstream] {
+					isReady = false
+					break
+				}
+			}
+			if isReady {
+				ready = append(ready, current)
+			}
+		}
+		if len(ready) == 0 {
+			return nil, fmt.Errorf("dependency graph of SparkApplication %s has a cycle", name)
+		}
+		sort.Strings(ready)
+		for _, current := range ready {
+			done[current] = true
+			graph = append(graph, byName[current])
+		}
+	}
+	return graph, nil
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2017 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+)
+
+func TestGetDependencyGraph(t *testing.T) {
+	newApp := func(name string, dependsOn ...string) v1beta2.SparkApplication {
+		return v1beta2.SparkApplication{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       v1beta2.SparkApplicationSpec{DependsOn: dependsOn},
+		}
+	}
+	apps := []v1beta2.SparkApplication{
+		newApp("report", "transform", "enrich"),
+		newApp("transform", "extract"),
+		newApp("enrich", "extract", "reference-data"),
+		newApp("extract"),
+		newApp("unrelated"),
+	}
+
+	graph, err := getDependencyGraph("transform", apps)
+	assert.Nil(t, err)
+	var names []string
+	for _, app := range graph {
+		names = append(names, app.Name)
+	}
+	assert.Equal(t, []string{"extract", "enrich", "transform", "report"}, names)
+
+	_, err = getDependencyGraph("missing", apps)
+	assert.NotNil(t, err)
+
+	_, err = getDependencyGraph("a", []v1beta2.SparkApplication{newApp("a", "b"), newApp("b", "a")})
+	assert.NotNil(t, err)
+}
@@ -85,6 +85,25 @@ func printStatus(app *v1beta2.SparkApplication) {
 		table.Render()
 	}
 
+	if attempt := app.Status.LastSubmissionAttempt; attempt != nil {
+		fmt.Println("last submission attempt:")
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Attempt", "Submitter", "Exit Code"})
+		exitCode := "N.A."
+		if attempt.ExitCode != nil {
+			exitCode = fmt.Sprintf("%d", *attempt.ExitCode)
+		}
+		table.Append([]string{
+			fmt.Sprintf("%d", attempt.Attempt),
+			formatNotAvailable(string(attempt.Submitter)),
+			exitCode,
+		})
+		table.Render()
+		if attempt.Output != "" {
+			fmt.Printf("\nsubmission output:\n%s\n", attempt.Output)
+		}
+	}
+
 	if app.Status.AppState.ErrorMessage != "" {
 		fmt.Printf("\napplication error message: %s\n", app.Status.AppState.ErrorMessage)
 	}
@@ -0,0 +1,103 @@
+/*
+Copyright 2017 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	crdclientset "github.com/kubeflow/spark-operator/pkg/client/clientset/versioned"
+	"github.com/kubeflow/spark-operator/pkg/common"
+)
+
+var TriggerTime string
+
+var triggerCmd = &cobra.Command{
+	Use:   "trigger <name>",
+	Short: "Trigger a run of a ScheduledSparkApplication",
+	Long: `Trigger a run of a ScheduledSparkApplication with a given name. The run is started by the operator as soon as
+the concurrency policy of the ScheduledSparkApplication allows it.`,
+	Run: func(_ *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "must specify a ScheduledSparkApplication name")
+			return
+		}
+
+		scheduleTime := time.Now()
+		if TriggerTime != "" {
+			t, err := time.Parse(time.RFC3339, TriggerTime)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "invalid scheduled time %q: %v\n", TriggerTime, err)
+				return
+			}
+			scheduleTime = t
+		}
+
+		crdClientset, err := getSparkApplicationClient()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to get SparkApplication client: %v\n", err)
+			return
+		}
+
+		if err := doTrigger(args[0], scheduleTime, crdClientset); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to trigger ScheduledSparkApplication %s: %v\n", args[0], err)
+		}
+	},
+}
+
+func init() {
+	triggerCmd.Flags().StringVarP(&TriggerTime, "time", "t", "",
+		"The scheduled time of the run in RFC 3339 format, which is passed to the application. Defaults to now")
+}
+
+func doTrigger(name string, scheduleTime time.Time, crdClientset crdclientset.Interface) error {
+	value := scheduleTime.UTC().Format(time.RFC3339)
+	if err := annotateScheduledSparkApplication(name, common.AnnotationTriggerRun, value, crdClientset); err != nil {
+		return err
+	}
+
+	fmt.Printf("ScheduledSparkApplication \"%s\" triggered for %s\n", name, value)
+
+	return nil
+}
+
+// annotateScheduledSparkApplication sets the given annotation on the ScheduledSparkApplication with the given name.
+func annotateScheduledSparkApplication(name string, key string, value string, crdClientset crdclientset.Interface) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{key: value},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = crdClientset.SparkoperatorV1beta2().ScheduledSparkApplications(Namespace).Patch(
+		context.TODO(),
+		name,
+		types.MergePatchType,
+		patch,
+		metav1.PatchOptions{},
+	)
+	return err
+}